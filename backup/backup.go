@@ -16,9 +16,11 @@ import (
 	"seedhammer.com/bc/ur"
 	"seedhammer.com/bc/urtypes"
 	"seedhammer.com/bip39"
+	"seedhammer.com/codex32"
 	"seedhammer.com/engrave"
 	"seedhammer.com/font"
 	"seedhammer.com/seedqr"
+	"seedhammer.com/slip39"
 )
 
 type PlateSize int
@@ -62,12 +64,72 @@ type PlateDesc struct {
 	Descriptor urtypes.OutputDescriptor
 	KeyIdx     int
 	Mnemonic   bip39.Mnemonic
-	Font       *font.Face
+	// MarkPassphrase marks the plate as protected by a BIP-39
+	// passphrase.
+	MarkPassphrase bool
+	// SLIP39 is a SLIP-39 share to engrave instead of
+	// Mnemonic and Descriptor.
+	SLIP39 slip39.Mnemonic
+	// Codex32 is a codex32 share string to engrave instead of
+	// Mnemonic and Descriptor.
+	Codex32 string
+	Font    *font.Face
+	// Templates lists the plates to fit the backup on, in order of
+	// preference. If empty, the SeedHammer plates are used.
+	Templates []Template
+	// Symbology selects the 2D code of the descriptor side. Only
+	// QR codes are readable by the SeedHammer scanner, and Verify
+	// rejects plates with other symbols.
+	Symbology Symbology
+	// DotPitch, if positive, engraves the plate as dots spaced at
+	// most DotPitch millimeters apart.
+	DotPitch float32
+}
+
+// Symbology is a kind of 2D code.
+type Symbology int
+
+const (
+	// QRCode is the QR code symbology.
+	QRCode Symbology = iota
+	DataMatrix
+	MicroQR
+	// BestSymbology selects the symbology with the smallest
+	// symbol, which may not be a QR code.
+	BestSymbology
+)
+
+// symbol engraves content as a 2D code of a symbology.
+func symbol(strokeWidth float32, s Symbology, content []byte) (engrave.Command, error) {
+	const scale = 2
+	switch s {
+	case QRCode:
+		return engrave.QR(strokeWidth, scale, qrcode.Medium, content), nil
+	case DataMatrix:
+		return engrave.DataMatrix(strokeWidth, scale, content)
+	case MicroQR:
+		return engrave.MicroQR(strokeWidth, scale, qrcode.Medium, content)
+	case BestSymbology:
+		var best engrave.Command
+		var bestArea float32
+		for _, s := range []Symbology{QRCode, DataMatrix, MicroQR} {
+			c, err := symbol(strokeWidth, s, content)
+			if err != nil {
+				continue
+			}
+			_, sz := dims(c)
+			if a := sz[0] * sz[1]; best == nil || a < bestArea {
+				best, bestArea = c, a
+			}
+		}
+		return best, nil
+	}
+	return nil, fmt.Errorf("unknown symbology %d", s)
 }
 
 type Plate struct {
-	Size  PlateSize
-	Sides []engrave.Command
+	Template Template
+	Sides    []engrave.Command
 }
 
 type measureProgram struct {
@@ -101,25 +163,89 @@ var ErrDescriptorTooLarge = errors.New("output descriptor is too large to backup
 const outerMargin = 3
 const innerMargin = 10
 
+// fits reports whether the plate sides are within the template margins
+// and clear of its keep-out regions.
+func fits(t Template, sides []engrave.Command) bool {
+	bounds := measure(engrave.Commands(sides))
+	safetyMargin := image.Pt(t.Margin, t.Margin)
+	if !bounds.In(image.Rectangle{Min: safetyMargin, Max: t.Size.Sub(safetyMargin)}) {
+		return false
+	}
+	keepOut := &keepOutProgram{KeepOut: t.KeepOut}
+	for _, s := range sides {
+		s.Engrave(keepOut)
+	}
+	return !keepOut.Hit
+}
+
 func Engrave(strokeWidth float32, plate PlateDesc) (Plate, error) {
-	for _, sz := range []PlateSize{SmallPlate, SquarePlate, LargePlate} {
-		p := Plate{Size: sz}
+	p, err := layout(strokeWidth, plate)
+	if err != nil {
+		return Plate{}, err
+	}
+	// Reduce the travel between strokes, starting from the engraver
+	// origin.
+	for i, s := range p.Sides {
+		if plate.DotPitch > 0 {
+			s = engrave.Dotted(plate.DotPitch, s)
+		}
+		p.Sides[i] = engrave.Optimize(s)
+	}
+	return p, nil
+}
+
+// layout engraves the plate sides in layout order.
+func layout(strokeWidth float32, plate PlateDesc) (Plate, error) {
+	var share slip39.Share
+	if len(plate.SLIP39) > 0 {
+		s, err := plate.SLIP39.Share()
+		if err != nil {
+			return Plate{}, fmt.Errorf("backup: %w", err)
+		}
+		share = s
+	}
+	var codex32Share codex32.Share
+	if plate.Codex32 != "" {
+		s, err := codex32.Parse(plate.Codex32)
+		if err != nil {
+			return Plate{}, fmt.Errorf("backup: %w", err)
+		}
+		codex32Share = s
+	}
+	templates := plate.Templates
+	if len(templates) == 0 {
+		templates = SeedHammerTemplates()
+		if plate.Codex32 != "" {
+			// Codex32 shares are short enough to never need a large plate.
+			templates = templates[:2]
+		}
+	}
+	for _, t := range templates {
+		p := Plate{Template: t}
 		seedOnly := plate.Descriptor.Type == urtypes.UnknownScript
 		switch {
+		case len(plate.SLIP39) > 0:
+			p.Sides = slip39Sides(plate.Title, plate.Font, share, plate.SLIP39, t)
+		case plate.Codex32 != "":
+			p.Sides = append(p.Sides, codex32Side(plate.Title, plate.Font, codex32Share, t))
 		case seedOnly && len(plate.Mnemonic) > 12:
-			p.Sides = append(p.Sides, seedBackSide(plate.Title, plate.Font, plate.Mnemonic, sz.Bounds().Size()))
+			p.Sides = append(p.Sides, seedBackSide(plate.Title, plate.Font, plate.Mnemonic, t.Size))
+			p.Sides = append(p.Sides, frontSide(strokeWidth, plate, t))
 		case !seedOnly:
 			urs := splitUR(plate.Descriptor, plate.KeyIdx)
-			p.Sides = append(p.Sides, descriptorSide(strokeWidth, plate.Font, urs, p.Size))
+			side, err := descriptorSide(strokeWidth, plate.Font, plate.Symbology, urs, t)
+			if err != nil {
+				return Plate{}, fmt.Errorf("backup: %w", err)
+			}
+			p.Sides = append(p.Sides, side)
+			p.Sides = append(p.Sides, frontSide(strokeWidth, plate, t))
+		default:
+			p.Sides = append(p.Sides, frontSide(strokeWidth, plate, t))
 		}
-		p.Sides = append(p.Sides, frontSide(strokeWidth, plate, p.Size))
-		bounds := measure(engrave.Commands(p.Sides))
-		dims := p.Size.Bounds().Size()
-		safetyMargin := image.Pt(outerMargin, outerMargin)
-		if !bounds.In(image.Rectangle{Min: safetyMargin, Max: dims.Sub(safetyMargin)}) {
+		if !fits(t, p.Sides) {
 			continue
 		}
-		off := p.Size.Bounds().Min
+		off := t.Offset
 		for i, s := range p.Sides {
 			p.Sides[i] = engrave.Offset(float32(off.X), float32(off.Y), s)
 		}
@@ -128,13 +254,23 @@ func Engrave(strokeWidth float32, plate PlateDesc) (Plate, error) {
 	return Plate{}, ErrDescriptorTooLarge
 }
 
+//go:generate go run gen_schemes.go
+
+// shareScheme is a fragment assignment for an m-of-n backup.
+type shareScheme struct {
+	seqLen int
+	// shares[i][j] lists the fragments XOR'ed into
+	// part j of share i.
+	shares [][][]int
+}
+
 // splitUR searches for the appropriate seqNum in the [UR] encoding
 // that makes m-of-n backups recoverable regardless of
 // which m-sized subset is used. To achieve that, we're exploiting the
 // fact that the UR encoding of a fragment can contain multiple fragments,
 // XOR'ed together.
 //
-// Schemes are implemented for backups where m == n - 1 and for 3-of-5.
+// Hand crafted schemes are implemented for backups where m == n - 1 and for 3-of-5.
 //
 // For m == n - 1, the data is split into m parts (seqLen in UR parlor), and m shares have parts
 // assigned as follows:
@@ -167,11 +303,18 @@ func Engrave(strokeWidth float32, plate PlateDesc) (Plate, error) {
 // That is, every share is assigned a part and the combination of the n+1 part with the neighbour
 // parts.
 //
+// For every other m-of-n with m > 1 and n <= 15, the assignment is looked up in
+// the shareSchemes table generated by gen_schemes.go. The generator searches for
+// assignments where every m-subset of shares can be decoded by repeatedly finding
+// a part with exactly one unknown fragment, and picks the one that stores the
+// smallest fraction of the data on each share.
+//
 // [UR]: https://github.com/BlockchainCommons/Research/blob/master/papers/bcr-2020-005-ur.md
 func splitUR(desc urtypes.OutputDescriptor, keyIdx int) (urs []string) {
 	var shares [][]int
 	var seqLen int
 	m, n := desc.Threshold, len(desc.Keys)
+	scheme, generated := shareSchemes[[2]int{m, n}]
 	switch {
 	case n-m <= 1:
 		// Optimal: 1 part per share, seqLen m.
@@ -195,9 +338,14 @@ func splitUR(desc urtypes.OutputDescriptor, keyIdx int) (urs []string) {
 			(keyIdx + 1) % n,
 		}
 		shares = [][]int{{keyIdx}, second}
+	case m > 1 && generated:
+		// Generated: the smallest known assignment found by gen_schemes.go.
+		seqLen = scheme.seqLen
+		shares = scheme.shares[keyIdx]
 	default:
 		// Fallback: every share contains the complete data. It's only optimal
-		// for 1-of-n backups.
+		// for 1-of-n backups, and used for m-of-n backups without a generated
+		// scheme.
 		seqLen = 1
 		shares = [][]int{{0}}
 	}
@@ -250,23 +398,29 @@ func Recoverable(desc urtypes.OutputDescriptor) bool {
 	return true
 }
 
+// passphraseMarker follows the title on plates with
+// PlateDesc.MarkPassphrase set.
+const passphraseMarker = "+PASSPHRASE"
+
 const plateFontSize = 5.
 const plateFontSizeUR = 4.1
 const plateSmallFontSize = 3.5
 
-func frontSide(strokeWidth float32, plate PlateDesc, size PlateSize) engrave.Command {
+func frontSide(strokeWidth float32, plate PlateDesc, t Template) engrave.Command {
 	var cmds engrave.Commands
 	cmd := func(c engrave.Command) {
 		cmds = append(cmds, c)
 	}
-	plateDimsI := size.Bounds().Size()
-	plateDims := f32.Vec2{float32(plateDimsI.X), float32(plateDimsI.Y)}
+	plateDims := f32.Vec2{float32(t.Size.X), float32(t.Size.Y)}
+	// Plates wider than tall don't have room for the meta data above and
+	// below the columns.
+	compact := t.Size.Y < t.Size.X
 
 	maxCol1 := 16
 	maxCol2 := 4
 	seedOnly := plate.Descriptor.Type == urtypes.UnknownScript
 	switch {
-	case seedOnly && size == SmallPlate:
+	case seedOnly && compact:
 		// 12 words on this side, the rest on the other.
 		maxCol1 = 12
 		maxCol2 = 0
@@ -282,8 +436,8 @@ func frontSide(strokeWidth float32, plate PlateDesc, size PlateSize) engrave.Com
 	const margin = outerMargin
 	const metaMargin = 4
 	page := fmt.Sprintf("%d/%d", plate.KeyIdx+1, len(plate.Descriptor.Keys))
-	switch size {
-	case SmallPlate:
+	switch {
+	case compact:
 		pagec, _ := dims(engrave.String(plate.Font, plateSmallFontSize, page))
 		cmd(engrave.Offset(margin, plateDims[1]-innerMargin, engrave.Rotate(-math.Pi/2, pagec)))
 		mfp := fmt.Sprintf("%.8x", plate.Descriptor.Keys[plate.KeyIdx].MasterFingerprint)
@@ -317,42 +471,53 @@ func frontSide(strokeWidth float32, plate PlateDesc, size PlateSize) engrave.Com
 	cx, cy := float32(60), plateDims[1]/2
 	cmd(engrave.Offset(cx-sz[0]/2, cy-sz[1]/2, qr))
 
-	if size != SmallPlate {
+	if !compact {
 		// Engrave bottom of column 2.
 		col2, col2b := dims(wordColumn(plate.Font, plate.Mnemonic, endCol2, len(plate.Mnemonic)))
 		cmd(engrave.Offset(44, (plateDims[1]+col1b[1])/2-col2b[1], col2))
 	}
 
 	// Engrave title.
-	switch size {
-	case SmallPlate:
-		title, sz := dims(engrave.Rotate(-math.Pi/2, engrave.String(plate.Font, plateSmallFontSize, plate.Title)))
+	title := plate.Title
+	if plate.MarkPassphrase {
+		title = strings.TrimSpace(title + " " + passphraseMarker)
+	}
+	switch {
+	case compact:
+		title, sz := dims(engrave.Rotate(-math.Pi/2, engrave.String(plate.Font, plateSmallFontSize, title)))
 		cmd(engrave.Offset(plateDims[0]-margin-sz[0], (plateDims[1]+sz[1])/2, title))
 	default:
 		offy := (plateDims[1]+col1b[1])/2 + metaMargin
-		title, sz := dims(engrave.String(plate.Font, plateSmallFontSize, plate.Title))
+		title, sz := dims(engrave.String(plate.Font, plateSmallFontSize, title))
 		cmd(engrave.Offset((plateDims[0]-sz[0])/2, offy, title))
 	}
-	if size == LargePlate {
+	if off := t.band() - plateDims[1]/2; off != 0 {
 		// Avoid the middle holes.
-		return engrave.Offset(0, 24.5, cmds)
+		return engrave.Offset(0, off, cmds)
 	}
 	return cmds
 }
 
 func wordColumn(font *font.Face, mnemonic bip39.Mnemonic, start, end int) engrave.Command {
+	var words []string
+	for _, w := range mnemonic[start:end] {
+		words = append(words, bip39.LabelFor(w))
+	}
+	return labelColumn(font, words, start)
+}
+
+// labelColumn lays out words in a column, numbered from first+1.
+func labelColumn(font *font.Face, words []string, first int) engrave.Command {
 	var b strings.Builder
-	for i := start; i < end; i++ {
-		w := mnemonic[i]
-		word := strings.ToUpper(bip39.LabelFor(w))
-		fmt.Fprintf(&b, "%2d:%-8s\n", i+1, word)
+	for i, w := range words {
+		fmt.Fprintf(&b, "%2d:%-8s\n", first+i+1, strings.ToUpper(w))
 	}
 	cmd := engrave.String(font, plateFontSize, b.String())
 	cmd.LineHeight = .8
 	return cmd
 }
 
-func descriptorSide(strokeWidth float32, fnt *font.Face, urs []string, size PlateSize) engrave.Command {
+func descriptorSide(strokeWidth float32, fnt *font.Face, sym Symbology, urs []string, t Template) (engrave.Command, error) {
 	var cmds engrave.Commands
 	cmd := func(c engrave.Command) {
 		cmds = append(cmds, c)
@@ -362,8 +527,7 @@ func descriptorSide(strokeWidth float32, fnt *font.Face, urs []string, size Plat
 		return engrave.String(fnt, fontSize, s)
 	}
 
-	plateDimsI := size.Bounds().Size()
-	plateDims := f32.Vec2{float32(plateDimsI.X), float32(plateDimsI.Y)}
+	plateDims := f32.Vec2{float32(t.Size.X), float32(t.Size.Y)}
 	// Compute character width, assuming the font is fixed width.
 	charWidth, _, ok := fnt.Decode('W')
 	if !ok {
@@ -371,17 +535,33 @@ func descriptorSide(strokeWidth float32, fnt *font.Face, urs []string, size Plat
 	}
 	charWidth *= fontSize
 	fontHeight := fnt.Metrics.Height * fontSize
-	margin := float32(outerMargin)
-	if size == LargePlate {
-		margin = innerMargin
+	margin := t.sideMargin()
+	offy := float32(t.Margin)
+	// holeChars returns the number of characters covered by the
+	// screw holes at either end of a line.
+	holeChars := func(inset float32) int {
+		if inset <= margin {
+			return 0
+		}
+		return int(math.Ceil(float64(inset-margin) / float64(charWidth)))
+	}
+	// Start the QR codes below the top right screw hole.
+	holeLines := 0
+	for {
+		y := offy + float32(holeLines)*fontHeight
+		if _, right := t.insets(y, y+fontHeight); right <= margin || y >= plateDims[1] {
+			break
+		}
+		holeLines++
 	}
-	holeChars := int(math.Ceil(float64(innerMargin-margin) / float64(charWidth)))
-	holeLines := int(math.Ceil(float64(innerMargin-margin) / float64(fontHeight)))
 	width := plateDims[0] - 2*margin
 	charPerLine := int(width / charWidth)
-	offy := float32(outerMargin)
 	for i, ur := range urs {
-		qr, qrsz := dims(engrave.QR(strokeWidth, 2, qrcode.Medium, []byte(ur)))
+		qr, err := symbol(strokeWidth, sym, []byte(ur))
+		if err != nil {
+			return nil, err
+		}
+		qr, qrsz := dims(qr)
 		const qrBorder = 2
 		charPerQRLine := int((width - 2*qrBorder - qrsz[0]) / charWidth)
 		qrLines := int(math.Ceil(float64((qrsz[1] + 2*qrBorder) / fontHeight)))
@@ -394,18 +574,16 @@ func descriptorSide(strokeWidth float32, fnt *font.Face, urs []string, size Plat
 			if isQRLine {
 				n = charPerQRLine
 			}
-			// Avoid screw holes on the smaller plates on the first and last lines.
-			holeLine := offy+float32(lineno)*fontHeight < innerMargin ||
-				offy+float32(lineno+1)*fontHeight > plateDims[1]-innerMargin
-			if holeLine {
-				if !isQRLine {
-					// End of line.
-					n -= holeChars
-				}
-				// Beginning of line.
-				n -= holeChars
-				offx = float32(holeChars) * charWidth
+			// Avoid screw holes on the first and last lines.
+			y := offy + float32(lineno)*fontHeight
+			left, right := t.insets(y, y+fontHeight)
+			if !isQRLine {
+				// End of line.
+				n -= holeChars(right)
 			}
+			// Beginning of line.
+			n -= holeChars(left)
+			offx = float32(holeChars(left)) * charWidth
 			if n < 1 {
 				n = 1
 			}
@@ -427,7 +605,7 @@ func descriptorSide(strokeWidth float32, fnt *font.Face, urs []string, size Plat
 		}
 	}
 
-	return cmds
+	return cmds, nil
 }
 
 func seedBackSide(title string, font *font.Face, plate bip39.Mnemonic, size image.Point) engrave.Command {
@@ -443,3 +621,88 @@ func seedBackSide(title string, font *font.Face, plate bip39.Mnemonic, size imag
 	cmd(engrave.Offset(44, y, col2))
 	return cmds
 }
+
+// slip39Sides lays out a SLIP-39 share with up to 20 words on the front
+// side and any remaining words on the back side.
+func slip39Sides(title string, fnt *font.Face, share slip39.Share, m slip39.Mnemonic, t Template) []engrave.Command {
+	const frontWords = 20
+	var words []string
+	for _, w := range m {
+		words = append(words, slip39.LabelFor(w))
+	}
+	plateDims := f32.Vec2{float32(t.Size.X), float32(t.Size.Y)}
+	columns := func(start, end int) (engrave.Commands, f32.Vec2) {
+		mid := start + (end-start+1)/2
+		col1, col1b := dims(labelColumn(fnt, words[start:mid], start))
+		col2 := labelColumn(fnt, words[mid:end], mid)
+		y := (plateDims[1] - col1b[1]) / 2
+		return engrave.Commands{
+			engrave.Offset(innerMargin, y, col1),
+			engrave.Offset(44, y, col2),
+		}, col1b
+	}
+	var sides []engrave.Command
+	end := len(words)
+	if end > frontWords {
+		end = frontWords
+		back, _ := columns(frontWords, len(words))
+		sides = append(sides, back)
+	}
+	front, colb := columns(0, end)
+
+	// Engrave version, share index and identifier.
+	const version = "v1"
+	const metaMargin = 4
+	offy := (plateDims[1]-colb[1])/2 - metaMargin
+	idx, sz := dims(engrave.String(fnt, plateSmallFontSize, fmt.Sprintf("%d.%d", share.GroupIndex+1, share.MemberIndex+1)))
+	front = append(front, engrave.Offset(innerMargin, offy-sz[1], idx))
+	id, sz := dims(engrave.String(fnt, plateSmallFontSize, fmt.Sprintf("%.4x", share.Identifier)))
+	front = append(front, engrave.Offset((plateDims[0]-sz[0])/2, offy-sz[1], id))
+	txt, sz := dims(engrave.String(fnt, plateSmallFontSize, version))
+	front = append(front, engrave.Offset(plateDims[0]-sz[0]-innerMargin, offy-sz[1], txt))
+
+	// Engrave title.
+	offy = (plateDims[1]+colb[1])/2 + metaMargin
+	titlec, sz := dims(engrave.String(fnt, plateSmallFontSize, title))
+	front = append(front, engrave.Offset((plateDims[0]-sz[0])/2, offy, titlec))
+	return append(sides, front)
+}
+
+// codex32Side lays out a codex32 share in rows of 4 character groups, as
+// recommended by BIP-93 for hand verification.
+func codex32Side(title string, fnt *font.Face, share codex32.Share, t Template) engrave.Command {
+	const groupsPerRow = 4
+	str := share.String()
+	var b strings.Builder
+	for i := 0; i < len(str); i += 4 {
+		end := i + 4
+		if end > len(str) {
+			end = len(str)
+		}
+		b.WriteString(str[i:end])
+		switch {
+		case end == len(str):
+		case (i/4+1)%groupsPerRow == 0:
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(' ')
+		}
+	}
+	plateDims := f32.Vec2{float32(t.Size.X), float32(t.Size.Y)}
+	rows := engrave.String(fnt, plateFontSize, b.String())
+	rows.LineHeight = .8
+	rowsc, rowsb := dims(rows)
+	var cmds engrave.Commands
+	cmds = append(cmds, engrave.Offset((plateDims[0]-rowsb[0])/2, (plateDims[1]-rowsb[1])/2, rowsc))
+
+	// Engrave version and title.
+	const version = "v1"
+	const metaMargin = 4
+	offy := (plateDims[1]-rowsb[1])/2 - metaMargin
+	txt, sz := dims(engrave.String(fnt, plateSmallFontSize, version))
+	cmds = append(cmds, engrave.Offset(plateDims[0]-sz[0]-innerMargin, offy-sz[1], txt))
+	offy = (plateDims[1]+rowsb[1])/2 + metaMargin
+	titlec, sz := dims(engrave.String(fnt, plateSmallFontSize, title))
+	cmds = append(cmds, engrave.Offset((plateDims[0]-sz[0])/2, offy, titlec))
+	return cmds
+}
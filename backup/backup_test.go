@@ -2,6 +2,7 @@ package backup
 
 import (
 	"bytes"
+	"crypto/rand"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,16 +10,21 @@ import (
 	"image/png"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
+	"golang.org/x/image/math/f32"
 	"seedhammer.com/bc/urtypes"
 	"seedhammer.com/bip32"
 	"seedhammer.com/bip39"
+	"seedhammer.com/codex32"
 	"seedhammer.com/engrave"
 	"seedhammer.com/font/sh"
 	"seedhammer.com/mjolnir"
+	"seedhammer.com/slip39"
 )
 
 var update = flag.Bool("update", false, "update golden files")
@@ -104,7 +110,7 @@ func TestEngrave(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			bounds := plate.Size.Bounds()
+			bounds := plate.Template.Bounds()
 			bounds = image.Rectangle{
 				Min: bounds.Min.Mul(ppmm),
 				Max: bounds.Max.Mul(ppmm),
@@ -126,8 +132,10 @@ func TestEngrave(t *testing.T) {
 				got.Pix[i] = p
 			}
 			if *update {
+				// Encode as gray, because alpha images encode as RGBA.
+				gray := &image.Gray{Pix: got.Pix, Stride: got.Stride, Rect: got.Rect}
 				var buf bytes.Buffer
-				if err := png.Encode(&buf, got); err != nil {
+				if err := png.Encode(&buf, gray); err != nil {
 					t.Fatal(err)
 				}
 				if err := os.WriteFile(golden, buf.Bytes(), 0o640); err != nil {
@@ -167,6 +175,60 @@ func TestEngrave(t *testing.T) {
 	}
 }
 
+func TestOptimizedTravel(t *testing.T) {
+	desc := urtypes.OutputDescriptor{
+		Type:      urtypes.P2WSH,
+		Threshold: 2,
+		Keys:      make([]urtypes.KeyDescriptor, 3),
+	}
+	plateDesc := genTestPlate(t, desc, desc.DerivationPath(), 24, 0)
+	before, err := layout(mjolnir.StrokeWidth, plateDesc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range before.Sides {
+		b, a := before.Sides[i], after.Sides[i]
+		tb, ta := engrave.Travel(b), engrave.Travel(a)
+		t.Logf("side %d travel before: %.0f mm, after: %.0f mm", i, tb, ta)
+		if ta >= tb/2 {
+			t.Errorf("side %d: travel %f mm not sufficiently reduced from %f mm", i, ta, tb)
+		}
+		if !reflect.DeepEqual(lines(b), lines(a)) {
+			t.Errorf("side %d: optimized lines differ", i)
+		}
+	}
+}
+
+// lines counts the line segments of an engraving regardless of
+// direction.
+func lines(c engrave.Command) map[[2]f32.Vec2]int {
+	l := &lineCounter{lines: make(map[[2]f32.Vec2]int)}
+	c.Engrave(l)
+	return l.lines
+}
+
+type lineCounter struct {
+	pos   f32.Vec2
+	lines map[[2]f32.Vec2]int
+}
+
+func (l *lineCounter) Move(p f32.Vec2) {
+	l.pos = p
+}
+
+func (l *lineCounter) Line(p f32.Vec2) {
+	a, b := l.pos, p
+	if b[0] < a[0] || b[0] == a[0] && b[1] < a[1] {
+		a, b = b, a
+	}
+	l.lines[[2]f32.Vec2{a, b}]++
+	l.pos = p
+}
+
 func TestSplitUR(t *testing.T) {
 	maxShares := 15
 	if testing.Short() {
@@ -187,6 +249,28 @@ func TestSplitUR(t *testing.T) {
 	}
 }
 
+func TestSplitURFallback(t *testing.T) {
+	// Backups with more shares than the generated schemes cover
+	// store the complete descriptor on every share.
+	for _, m := range []int{2, 3} {
+		const n = 16
+		desc := urtypes.OutputDescriptor{
+			Type:      urtypes.P2WSH,
+			Threshold: m,
+			Keys:      make([]urtypes.KeyDescriptor, n),
+		}
+		genTestPlate(t, desc, desc.DerivationPath(), 12, 0)
+		for k := range desc.Keys {
+			if urs := splitUR(desc, k); len(urs) != 1 {
+				t.Errorf("%d-of-%d: share %d has %d parts, want 1", m, n, k, len(urs))
+			}
+		}
+		if !Recoverable(desc) {
+			t.Errorf("%d-of-%d: failed to recover", m, n)
+		}
+	}
+}
+
 func genTestPlate(t *testing.T, desc urtypes.OutputDescriptor, path []uint32, seedlen int, keyIdx int) PlateDesc {
 	var mnemonic bip39.Mnemonic
 	for i := range desc.Keys {
@@ -225,3 +309,384 @@ func genTestPlate(t *testing.T, desc urtypes.OutputDescriptor, path []uint32, se
 		Descriptor: desc,
 	}
 }
+
+func TestShareSchemes(t *testing.T) {
+	for mn, s := range shareSchemes {
+		m, n := mn[0], mn[1]
+		if len(s.shares) != n {
+			t.Errorf("%d-of-%d: got %d shares, want %d", m, n, len(s.shares), n)
+			continue
+		}
+		for i, share := range s.shares {
+			// A share must store less than the complete descriptor, or
+			// the seqLen 1 fallback would be as good.
+			if len(share) >= s.seqLen {
+				t.Errorf("%d-of-%d: share %d stores %d of %d fragments", m, n, i, len(share), s.seqLen)
+			}
+			for _, part := range share {
+				for _, f := range part {
+					if f < 0 || f >= s.seqLen {
+						t.Errorf("%d-of-%d: share %d has fragment %d out of range", m, n, i, f)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEngraveSLIP39(t *testing.T) {
+	tests := []struct {
+		secretLen int
+		sides     int
+		size      PlateSize
+	}{
+		{16, 1, SquarePlate},
+		{32, 2, SquarePlate},
+	}
+	for _, test := range tests {
+		secret := make([]byte, test.secretLen)
+		groups, err := slip39.Split(rand.Reader, secret, "", 0, 1, []slip39.Group{{Threshold: 2, Count: 3}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		plateDesc := PlateDesc{
+			Title:  "Satoshi Stash",
+			Font:   &sh.Fontsh,
+			SLIP39: groups[0][1].Mnemonic(),
+		}
+		plate, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plate.Sides) != test.sides {
+			t.Errorf("%d byte secret: got %d sides, want %d", test.secretLen, len(plate.Sides), test.sides)
+		}
+		if got, want := plate.Template.Name, test.size.Template().Name; got != want {
+			t.Errorf("%d byte secret: got plate %s, want %s", test.secretLen, got, want)
+		}
+	}
+}
+
+func TestEngraveCodex32(t *testing.T) {
+	for _, seedLen := range []int{16, 32} {
+		shares, err := codex32.Split(rand.Reader, "cash", make([]byte, seedLen), 2, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plateDesc := PlateDesc{
+			Title:   "Satoshi Stash",
+			Font:    &sh.Fontsh,
+			Codex32: shares[0].String(),
+		}
+		plate, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := plate.Template.Name, SmallPlate.Template().Name; got != want {
+			t.Errorf("%d byte seed: got plate %s, want %s", seedLen, got, want)
+		}
+	}
+	plateDesc := PlateDesc{
+		Font:    &sh.Fontsh,
+		Codex32: "MS10TESTSXXXXXXXXXXXXXXXXXXXXXXXXXX4NZVCA9CMCZLQ",
+	}
+	if _, err := Engrave(mjolnir.StrokeWidth, plateDesc); !errors.Is(err, codex32.ErrChecksum) {
+		t.Errorf("got error %v, want %v", err, codex32.ErrChecksum)
+	}
+}
+
+func TestEngravePassphraseMarker(t *testing.T) {
+	for _, script := range []urtypes.Script{urtypes.UnknownScript, urtypes.P2WSH} {
+		for _, seedLen := range []int{12, 24} {
+			desc := urtypes.OutputDescriptor{
+				Type:      script,
+				Threshold: 1,
+				Keys:      make([]urtypes.KeyDescriptor, 1),
+			}
+			plateDesc := genTestPlate(t, desc, desc.DerivationPath(), seedLen, 0)
+			// Untitled, like the plates engraved by the controller.
+			plateDesc.Title = ""
+			plain, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plateDesc.MarkPassphrase = true
+			marked, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := marked.Template.Name, plain.Template.Name; got != want {
+				t.Errorf("%v %d words: marked plate %s, want %s", script, seedLen, got, want)
+			}
+			if reflect.DeepEqual(marked.Sides, plain.Sides) {
+				t.Errorf("%v %d words: plate not marked", script, seedLen)
+			}
+		}
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	const data = `[
+		{
+			"name": "Card",
+			"size": [86, 54],
+			"offset": [97, 0],
+			"margin": 2,
+			"keepout": [[0, 0, 8, 8], [78, 0, 86, 8], [0, 46, 8, 54], [78, 46, 86, 54]]
+		},
+		{
+			"name": "Tall",
+			"size": [90, 150],
+			"offset": [90, 10],
+			"margin": 4,
+			"keepout": [[0, 40, 12, 52], [78, 40, 90, 52]]
+		}
+	]`
+	templates, err := LoadTemplates(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		threshold int
+		keys      int
+		script    urtypes.Script
+		seedLen   int
+		template  string
+	}{
+		{1, 1, urtypes.UnknownScript, 12, "Card"},
+		{1, 1, urtypes.P2WSH, 12, "Card"},
+		{1, 1, urtypes.P2WSH, 24, "Tall"},
+		{3, 5, urtypes.P2WSH, 24, "Tall"},
+	}
+	for _, test := range tests {
+		desc := urtypes.OutputDescriptor{
+			Type:      test.script,
+			Threshold: test.threshold,
+			Keys:      make([]urtypes.KeyDescriptor, test.keys),
+		}
+		plateDesc := genTestPlate(t, desc, desc.DerivationPath(), test.seedLen, 0)
+		plateDesc.Templates = templates
+		plate, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl := plate.Template
+		if tmpl.Name != test.template {
+			t.Errorf("%d-of-%d %d words: got template %s, want %s", test.threshold, test.keys, test.seedLen, tmpl.Name, test.template)
+		}
+		safe := tmpl.Bounds().Inset(tmpl.Margin)
+		keepOut := &keepOutProgram{}
+		for _, k := range tmpl.KeepOut {
+			keepOut.KeepOut = append(keepOut.KeepOut, k.Add(tmpl.Offset))
+		}
+		for _, s := range plate.Sides {
+			if b := measure(s); !b.In(safe) {
+				t.Errorf("%s: engraving %v outside safe area %v", tmpl.Name, b, safe)
+			}
+			s.Engrave(keepOut)
+		}
+		if keepOut.Hit {
+			t.Errorf("%s: engraving inside keep-out region", tmpl.Name)
+		}
+	}
+
+	desc := urtypes.OutputDescriptor{
+		Type:      urtypes.P2WSH,
+		Threshold: 1,
+		Keys:      make([]urtypes.KeyDescriptor, 1),
+	}
+	plateDesc := genTestPlate(t, desc, desc.DerivationPath(), 12, 0)
+	plateDesc.Templates = []Template{{Name: "Tiny", Size: image.Pt(40, 40)}}
+	if _, err := Engrave(mjolnir.StrokeWidth, plateDesc); !errors.Is(err, ErrDescriptorTooLarge) {
+		t.Errorf("got error %v, want %v", err, ErrDescriptorTooLarge)
+	}
+
+	invalid := []string{
+		`[{"name": "Empty"}]`,
+		`[{"name": "Hole", "size": [85, 55], "keepout": [[80, 50, 90, 60]]}]`,
+		`[{"name": "Margin", "size": [85, 55], "margin": 30}]`,
+		`[{"name": "Unknown", "size": [85, 55], "holes": []}]`,
+	}
+	for _, data := range invalid {
+		if _, err := LoadTemplates(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: loaded invalid template", data)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	newDesc := func(m, n, keyOffset int) urtypes.OutputDescriptor {
+		desc := urtypes.OutputDescriptor{
+			Type:      urtypes.P2WSH,
+			Threshold: m,
+			Keys:      make([]urtypes.KeyDescriptor, n),
+		}
+		genTestPlate(t, desc, desc.DerivationPath(), 12, 0)
+		// Differentiate descriptors by their first key.
+		desc.Keys[0].MasterFingerprint += uint32(keyOffset)
+		return desc
+	}
+	desc := newDesc(2, 3, 0)
+	other := newDesc(2, 3, 1)
+	share := func(d urtypes.OutputDescriptor, k int) string {
+		urs := splitUR(d, k)
+		if len(urs) != 1 {
+			t.Fatalf("%d-of-%d share has %d URs", d.Threshold, len(d.Keys), len(urs))
+		}
+		return urs[0]
+	}
+	tests := []struct {
+		name   string
+		urs    []string
+		err    error
+		report Recovery
+	}{
+		{
+			name:   "minimal",
+			urs:    []string{share(desc, 0), share(desc, 2)},
+			report: Recovery{Used: []int{0, 1}},
+		},
+		{
+			name:   "redundant",
+			urs:    []string{share(desc, 0), share(desc, 1), share(desc, 2), share(desc, 1)},
+			report: Recovery{Used: []int{2, 3}, Redundant: []int{0, 1}},
+		},
+		{
+			name:   "inconsistent",
+			urs:    []string{share(desc, 0), share(other, 0), "UR:CRYPTO-OUTPUT/INVALID", share(desc, 1)},
+			report: Recovery{Used: []int{0, 3}, Inconsistent: []int{2, 1}},
+		},
+		{
+			name:   "missing",
+			urs:    []string{share(desc, 0), share(other, 1), "UR:CRYPTO-OUTPUT/INVALID"},
+			err:    ErrMissingShares,
+			report: Recovery{Inconsistent: []int{2}},
+		},
+		{
+			name: "conflicting",
+			urs:  []string{share(desc, 0), share(other, 0), share(desc, 1), share(other, 1)},
+			err:  ErrConflictingShares,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := Recover(test.urs)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got error %v, want %v", err, test.err)
+			}
+			if err != nil {
+				// Incomplete shares may be completed by more shares.
+				if !reflect.DeepEqual(r.Inconsistent, test.report.Inconsistent) {
+					t.Errorf("got inconsistent shares %v, want %v", r.Inconsistent, test.report.Inconsistent)
+				}
+				return
+			}
+			if !reflect.DeepEqual(r.Descriptor, desc) {
+				t.Error("recovered descriptor doesn't match")
+			}
+			r.Descriptor = urtypes.OutputDescriptor{}
+			if !reflect.DeepEqual(r, test.report) {
+				t.Errorf("got report %+v, want %+v", r, test.report)
+			}
+		})
+	}
+}
+
+func TestSymbology(t *testing.T) {
+	desc := urtypes.OutputDescriptor{
+		Type:      urtypes.P2WSH,
+		Threshold: 2,
+		Keys:      make([]urtypes.KeyDescriptor, 3),
+	}
+	plateDesc := genTestPlate(t, desc, desc.DerivationPath(), 24, 0)
+	sides := make(map[Symbology]map[[2]f32.Vec2]int)
+	for _, s := range []Symbology{QRCode, DataMatrix, BestSymbology} {
+		plateDesc.Symbology = s
+		plate, err := layout(mjolnir.StrokeWidth, plateDesc)
+		if err != nil {
+			t.Fatalf("symbology %d: %v", s, err)
+		}
+		sides[s] = lines(plate.Sides[0])
+	}
+	if reflect.DeepEqual(sides[QRCode], sides[DataMatrix]) {
+		t.Error("Data Matrix descriptor side is identical to QR")
+	}
+	if !reflect.DeepEqual(sides[BestSymbology], sides[QRCode]) &&
+		!reflect.DeepEqual(sides[BestSymbology], sides[DataMatrix]) {
+		t.Error("best symbology matches neither QR nor Data Matrix")
+	}
+	// The descriptor doesn't fit Micro QR symbols.
+	plateDesc.Symbology = MicroQR
+	if _, err := Engrave(mjolnir.StrokeWidth, plateDesc); !errors.Is(err, engrave.ErrTooLarge) {
+		t.Errorf("got error %v for Micro QR descriptor, want %v", err, engrave.ErrTooLarge)
+	}
+}
+
+func TestDotPitch(t *testing.T) {
+	desc := urtypes.OutputDescriptor{
+		Type:      urtypes.P2WSH,
+		Threshold: 2,
+		Keys:      make([]urtypes.KeyDescriptor, 3),
+	}
+	plateDesc := genTestPlate(t, desc, desc.DerivationPath(), 24, 0)
+	plateDesc.DotPitch = .5
+	plate, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range plate.Sides {
+		c := new(dotCounter)
+		s.Engrave(c)
+		if c.dots == 0 || c.lines > 0 {
+			t.Errorf("side %d: %d dots and %d lines, want only dots", i, c.dots, c.lines)
+		}
+	}
+}
+
+// dotCounter is a DotProgram that counts dots and lines.
+type dotCounter struct {
+	dots, lines int
+}
+
+func (c *dotCounter) Move(p f32.Vec2) {}
+
+func (c *dotCounter) Line(p f32.Vec2) {
+	c.lines++
+}
+
+func (c *dotCounter) Dot(p f32.Vec2) {
+	c.dots++
+}
+
+func TestVerify(t *testing.T) {
+	desc := urtypes.OutputDescriptor{
+		Type:      urtypes.P2WSH,
+		Threshold: 2,
+		Keys:      make([]urtypes.KeyDescriptor, 3),
+	}
+	plateDesc := genTestPlate(t, desc, desc.DerivationPath(), 24, 1)
+	plate, err := Engrave(mjolnir.StrokeWidth, plateDesc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(mjolnir.StrokeWidth, plateDesc, plate); err != nil {
+		t.Error(err)
+	}
+	if err := Verify(mjolnir.StrokeWidth/2, plateDesc, plate); !errors.Is(err, ErrUnreadable) {
+		t.Errorf("thin strokes verified with error %v, want %v", err, ErrUnreadable)
+	}
+	other := plateDesc
+	other.KeyIdx = 0
+	if err := Verify(mjolnir.StrokeWidth, other, plate); !errors.Is(err, ErrUnreadable) {
+		t.Errorf("plate verified against another share with error %v, want %v", err, ErrUnreadable)
+	}
+	dm := plateDesc
+	dm.Symbology = DataMatrix
+	plate, err = Engrave(mjolnir.StrokeWidth, dm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(mjolnir.StrokeWidth, dm, plate); !errors.Is(err, ErrUnverifiable) {
+		t.Errorf("Data Matrix plate verified with error %v, want %v", err, ErrUnverifiable)
+	}
+}
@@ -0,0 +1,206 @@
+//go:build ignore
+
+// gen_schemes searches for fountain fragment assignments that make m-of-n
+// descriptor backups recoverable from any m shares, and outputs them as a
+// table for splitUR.
+//
+// A scheme splits the descriptor into seqLen fragments and assigns each share
+// a few parts, where a part is the XOR of a subset of the fragments. A scheme
+// is accepted only if every m-subset of shares can be decoded by peeling: by
+// repeatedly finding a part with exactly one unknown fragment. Peeling is the
+// subset of the UR fountain decoding algorithm that works regardless of the
+// order in which parts are scanned.
+//
+// The search is a randomized local search over part assignments, seeded
+// deterministically so the output is reproducible. Schemes are ranked by the
+// fraction of the descriptor stored on each share, and then by the number of
+// parts per share.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"math/bits"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+var output = flag.String("o", "schemes.go", "output file")
+
+const (
+	// maxShares is the largest number of shares searched.
+	maxShares = 15
+	// maxParts is the maximum number of parts per share.
+	maxParts = 3
+	// maxDegree is the maximum number of fragments in a part. It is kept
+	// small to bound the time fountain.SeqNumFor spends searching for a
+	// matching sequence number.
+	maxDegree = 3
+	// iterations is the number of local search steps per attempt.
+	iterations = 3000
+)
+
+type scheme struct {
+	seqLen int
+	// shares[i][j] is the bit set of fragments XOR'ed into
+	// part j of share i.
+	shares [][]uint32
+}
+
+func main() {
+	flag.Parse()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_schemes.go; DO NOT EDIT.\n\npackage backup\n\n")
+	fmt.Fprintf(&b, "var shareSchemes = map[[2]int]shareScheme{\n")
+	for n := 1; n <= maxShares; n++ {
+		for m := 2; m <= n-2; m++ {
+			if m == 3 && n == 5 {
+				// Covered by the hand crafted scheme.
+				continue
+			}
+			s, ok := solve(m, n)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "gen_schemes: no scheme found for %d-of-%d\n", m, n)
+				os.Exit(1)
+			}
+			fmt.Fprintf(&b, "{%d, %d}: {seqLen: %d, shares: [][][]int{", m, n, s.seqLen)
+			for _, share := range s.shares {
+				fmt.Fprintf(&b, "{")
+				for _, part := range share {
+					fmt.Fprintf(&b, "{")
+					for _, f := range fragments(part) {
+						fmt.Fprintf(&b, "%d,", f)
+					}
+					fmt.Fprintf(&b, "},")
+				}
+				fmt.Fprintf(&b, "},")
+			}
+			fmt.Fprintf(&b, "}},\n")
+		}
+	}
+	fmt.Fprintf(&b, "}\n")
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gen_schemes: %v\n", err)
+		os.Exit(2)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "gen_schemes: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func fragments(part uint32) []int {
+	var frags []int
+	for part != 0 {
+		f := bits.TrailingZeros32(part)
+		part &^= 1 << f
+		frags = append(frags, f)
+	}
+	sort.Ints(frags)
+	return frags
+}
+
+// solve searches for the m-of-n scheme that stores the smallest fraction
+// of the data on each share.
+func solve(m, n int) (scheme, bool) {
+	var best scheme
+	found := false
+	for k := 2; k <= maxParts; k++ {
+		// Larger seqLens are harder to satisfy. Stop at the first failure.
+		for seqLen := k + 1; seqLen <= k*m; seqLen++ {
+			s, ok := search(m, n, k, seqLen)
+			if !ok {
+				break
+			}
+			if !found || k*best.seqLen < len(best.shares[0])*seqLen {
+				best = s
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+func search(m, n, k, seqLen int) (scheme, bool) {
+	rng := rand.New(rand.NewSource(int64(m<<16 | n<<8 | k<<5 | seqLen)))
+	var subsets []uint32
+	for c := uint32(1); c < 1<<n; c++ {
+		if bits.OnesCount32(c) == m {
+			subsets = append(subsets, c)
+		}
+	}
+	s := scheme{seqLen: seqLen, shares: make([][]uint32, n)}
+	for i := range s.shares {
+		s.shares[i] = make([]uint32, k)
+		for j := range s.shares[i] {
+			s.shares[i][j] = randomPart(rng, seqLen)
+		}
+	}
+	failures := s.failures(subsets, -1)
+	for i := 0; i < iterations && failures > 0; i++ {
+		share, part := rng.Intn(n), rng.Intn(k)
+		old := s.shares[share][part]
+		before := s.failures(subsets, share)
+		s.shares[share][part] = randomPart(rng, seqLen)
+		after := s.failures(subsets, share)
+		// Accept improvements, sideways moves and, rarely, a worse
+		// assignment to escape local minima.
+		if after <= before || rng.Intn(100) == 0 {
+			failures += after - before
+		} else {
+			s.shares[share][part] = old
+		}
+	}
+	return s, failures == 0
+}
+
+func randomPart(rng *rand.Rand, seqLen int) uint32 {
+	degree := 1 + rng.Intn(maxDegree)
+	if degree > seqLen {
+		degree = seqLen
+	}
+	var p uint32
+	for bits.OnesCount32(p) < degree {
+		p |= 1 << rng.Intn(seqLen)
+	}
+	return p
+}
+
+// failures counts the subsets that can't be decoded. If share is not
+// negative, only subsets that include it are considered.
+func (s scheme) failures(subsets []uint32, share int) int {
+	count := 0
+	var parts []uint32
+	for _, sub := range subsets {
+		if share >= 0 && sub&(1<<share) == 0 {
+			continue
+		}
+		parts = parts[:0]
+		for c := sub; c != 0; c &= c - 1 {
+			parts = append(parts, s.shares[bits.TrailingZeros32(c)]...)
+		}
+		if !peelable(parts, s.seqLen) {
+			count++
+		}
+	}
+	return count
+}
+
+func peelable(parts []uint32, seqLen int) bool {
+	var known uint32
+	for progress := true; progress; {
+		progress = false
+		for _, p := range parts {
+			if rem := p &^ known; bits.OnesCount32(rem) == 1 {
+				known |= rem
+				progress = true
+			}
+		}
+	}
+	return known == 1<<seqLen-1
+}
@@ -0,0 +1,192 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"seedhammer.com/bc/ur"
+	"seedhammer.com/bc/urtypes"
+)
+
+// Recovery describes the shares used by Recover.
+type Recovery struct {
+	Descriptor urtypes.OutputDescriptor
+	// Used lists the indices of the shares needed to recover
+	// Descriptor.
+	Used []int
+	// Redundant lists the indices of shares consistent with
+	// Descriptor, but not needed to recover it.
+	Redundant []int
+	// Inconsistent lists the indices of shares that are invalid or
+	// don't belong to Descriptor.
+	Inconsistent []int
+}
+
+var (
+	ErrMissingShares     = errors.New("backup: not enough shares to recover the descriptor")
+	ErrConflictingShares = errors.New("backup: shares belong to different descriptors")
+)
+
+// Recover decodes the output descriptor from the UR strings of a set of
+// plates. If the descriptor can't be recovered, the returned Recovery lists
+// the invalid shares, if any.
+func Recover(urs []string) (Recovery, error) {
+	r, shares, err := recoverDescriptor(urs)
+	if err != nil {
+		return r, err
+	}
+	// Remove shares not needed for recovery.
+	used := shares
+	for _, i := range shares {
+		rest := without(used, i)
+		if _, ok, err := decodeShares(urs, rest); ok && err == nil {
+			used = rest
+			r.Redundant = append(r.Redundant, i)
+		}
+	}
+	r.Used = used
+	return r, nil
+}
+
+// recoverDescriptor is like Recover, but doesn't separate the used
+// from the redundant shares. Instead, it returns every share
+// consistent with the descriptor.
+func recoverDescriptor(urs []string) (Recovery, []int, error) {
+	var r Recovery
+	// Group shares that can be decoded together.
+	type group struct {
+		shares []int
+		dec    *ur.Decoder
+	}
+	var groups []group
+	for i, s := range urs {
+		if err := new(ur.Decoder).Add(s); err != nil {
+			r.Inconsistent = append(r.Inconsistent, i)
+			continue
+		}
+		added := false
+		for j, g := range groups {
+			first := urs[g.shares[0]]
+			if singlePart(s) || singlePart(first) {
+				if !strings.EqualFold(s, first) {
+					continue
+				}
+			} else if err := g.dec.Add(s); err != nil {
+				// The decoder is unchanged by incompatible shares.
+				continue
+			}
+			groups[j].shares = append(g.shares, i)
+			added = true
+			break
+		}
+		if !added {
+			d := new(ur.Decoder)
+			d.Add(s)
+			groups = append(groups, group{shares: []int{i}, dec: d})
+		}
+	}
+	var recovered [][]int
+	var descs []urtypes.OutputDescriptor
+	// Incomplete groups are only inconsistent with a recovered
+	// descriptor. Otherwise, they may be completed by more shares.
+	var incomplete []int
+	for _, gr := range groups {
+		g := gr.shares
+		desc, ok, err := decodeResult(gr.dec)
+		if err != nil {
+			// Look for the shares that are corrupt.
+			var valid []int
+			for _, i := range g {
+				if _, ok, err := decodeShares(urs, without(g, i)); ok && err == nil {
+					r.Inconsistent = append(r.Inconsistent, i)
+				} else {
+					valid = append(valid, i)
+				}
+			}
+			if len(valid) == len(g) {
+				r.Inconsistent = append(r.Inconsistent, g...)
+				continue
+			}
+			g = valid
+			desc, ok, err = decodeShares(urs, g)
+			if err != nil {
+				r.Inconsistent = append(r.Inconsistent, g...)
+				continue
+			}
+		}
+		if !ok {
+			incomplete = append(incomplete, g...)
+			continue
+		}
+		merged := false
+		for j, d := range descs {
+			if reflect.DeepEqual(d, desc) {
+				recovered[j] = append(recovered[j], g...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			descs = append(descs, desc)
+			recovered = append(recovered, g)
+		}
+	}
+	switch len(descs) {
+	case 0:
+		return r, nil, ErrMissingShares
+	case 1:
+	default:
+		return r, nil, ErrConflictingShares
+	}
+	r.Descriptor = descs[0]
+	r.Inconsistent = append(r.Inconsistent, incomplete...)
+	return r, recovered[0], nil
+}
+
+// singlePart reports whether a UR contains its complete message.
+func singlePart(s string) bool {
+	return len(strings.SplitN(s, "/", 3)) == 2
+}
+
+// decodeShares decodes a group of compatible shares. It reports
+// false if the shares are insufficient.
+func decodeShares(urs []string, shares []int) (urtypes.OutputDescriptor, bool, error) {
+	d := new(ur.Decoder)
+	for _, i := range shares {
+		if err := d.Add(urs[i]); err != nil {
+			return urtypes.OutputDescriptor{}, false, err
+		}
+	}
+	return decodeResult(d)
+}
+
+func decodeResult(d *ur.Decoder) (urtypes.OutputDescriptor, bool, error) {
+	typ, enc, err := d.Result()
+	if err != nil {
+		return urtypes.OutputDescriptor{}, false, err
+	}
+	if enc == nil {
+		return urtypes.OutputDescriptor{}, false, nil
+	}
+	v, err := urtypes.Parse(typ, enc)
+	if err != nil {
+		return urtypes.OutputDescriptor{}, false, err
+	}
+	desc, ok := v.(urtypes.OutputDescriptor)
+	if !ok {
+		return urtypes.OutputDescriptor{}, false, fmt.Errorf("backup: %s is not an output descriptor", typ)
+	}
+	return desc, true, nil
+}
+
+func without(shares []int, share int) []int {
+	var res []int
+	for _, s := range shares {
+		if s != share {
+			res = append(res, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,83 @@
+// Code generated by gen_schemes.go; DO NOT EDIT.
+
+package backup
+
+var shareSchemes = map[[2]int]shareScheme{
+	{2, 4}:   {seqLen: 4, shares: [][][]int{{{1}, {0, 1, 3}}, {{3}, {0, 2, 3}}, {{0, 1, 2}, {2}}, {{0}, {1, 2, 3}}}},
+	{2, 5}:   {seqLen: 6, shares: [][][]int{{{0, 1, 2}, {1}, {1, 4, 5}}, {{3}, {0, 1}, {1, 2, 4}}, {{0}, {2, 3}, {0, 1, 5}}, {{2, 5}, {3, 4, 5}, {5}}, {{1, 4}, {0, 3}, {0, 5}}}},
+	{2, 6}:   {seqLen: 5, shares: [][][]int{{{0, 4}, {0, 1, 3}, {2, 3}}, {{0, 1, 4}, {0}, {1, 2, 4}}, {{3}, {3, 4}, {0, 1, 2}}, {{1, 4}, {1}, {1, 2, 3}}, {{2}, {0, 3, 4}, {4}}, {{1}, {0, 1}, {3}}}},
+	{3, 6}:   {seqLen: 5, shares: [][][]int{{{0}, {2}}, {{3, 4}, {0, 1, 2}}, {{3}, {0, 3}}, {{1, 2, 4}, {1}}, {{1, 2, 3}, {1, 4}}, {{0, 1, 3}, {4}}}},
+	{4, 6}:   {seqLen: 7, shares: [][][]int{{{4}, {1, 3}}, {{6}, {0, 3, 6}}, {{0, 2, 4}, {1}}, {{1, 2}, {4, 5, 6}}, {{3, 5}, {4, 6}}, {{2, 5}, {0}}}},
+	{2, 7}:   {seqLen: 5, shares: [][][]int{{{0, 1, 2}, {0, 3, 4}, {3}}, {{1, 3}, {1, 2, 4}, {4}}, {{0, 1}, {2, 3, 4}, {2}}, {{0}, {1}, {0, 1, 3}}, {{0, 1, 4}, {0}, {0, 2}}, {{0, 3, 4}, {4}, {2}}, {{0, 2, 4}, {1}, {2, 3}}}},
+	{3, 7}:   {seqLen: 7, shares: [][][]int{{{1, 5, 6}, {0, 1, 3}, {0}}, {{0, 3}, {1, 4, 6}, {2, 5}}, {{1}, {1, 5}, {3, 4, 6}}, {{3}, {1, 2, 5}, {0, 4}}, {{1, 2}, {4}, {0, 6}}, {{3, 5, 6}, {5}, {0, 2}}, {{4}, {3}, {2, 4}}}},
+	{4, 7}:   {seqLen: 6, shares: [][][]int{{{0, 2, 3}, {2, 4, 5}}, {{1}, {1, 3, 4}}, {{3}, {0, 1, 3}}, {{0}, {4}}, {{1, 2, 3}, {0, 3, 5}}, {{1, 2, 5}, {5}}, {{1, 4, 5}, {2}}}},
+	{5, 7}:   {seqLen: 13, shares: [][][]int{{{3, 4, 9}, {3, 10, 11}, {5, 9}}, {{2, 9, 10}, {3, 6, 7}, {5, 12}}, {{6, 10, 11}, {0, 10, 12}, {4}}, {{1, 8, 9}, {0}, {2}}, {{7, 8}, {2, 5}, {6}}, {{0, 1, 12}, {4, 10}, {7, 12}}, {{3}, {1, 3}, {6, 8, 11}}}},
+	{2, 8}:   {seqLen: 5, shares: [][][]int{{{0, 2}, {0, 4}, {2, 3}}, {{1}, {0}, {1, 2, 3}}, {{3}, {1, 2}, {1, 4}}, {{1, 2, 4}, {2}, {0, 1, 3}}, {{0, 3, 4}, {1}, {1, 3}}, {{0}, {3, 4}, {0, 1, 2}}, {{2}, {2, 4}, {0, 1, 4}}, {{0, 2}, {4}, {0, 1, 3}}}},
+	{3, 8}:   {seqLen: 4, shares: [][][]int{{{3}, {0, 2, 3}}, {{2}, {1, 3}}, {{0, 1, 3}, {1}}, {{0, 1, 3}, {2, 3}}, {{1}, {2}}, {{3}, {0, 2, 3}}, {{1, 2, 3}, {0}}, {{0}, {0, 1}}}},
+	{4, 8}:   {seqLen: 8, shares: [][][]int{{{1, 7}, {5}, {3, 4}}, {{6, 7}, {0, 7}, {0, 3}}, {{7}, {0, 4, 5}, {2}}, {{1, 2, 4}, {0, 2, 6}, {1, 6}}, {{1, 3, 5}, {6}, {3}}, {{7}, {1, 2, 4}, {0, 5}}, {{5, 7}, {4, 6}, {0, 2, 3}}, {{3}, {1, 6, 7}, {1, 2, 3}}}},
+	{5, 8}:   {seqLen: 11, shares: [][][]int{{{1, 6}, {0, 5, 9}, {4, 5, 8}}, {{1, 7}, {0, 5}, {3, 5, 10}}, {{2, 3, 6}, {1, 8}, {0, 9, 10}}, {{0}, {2}, {2, 4}}, {{0, 5, 8}, {2, 7}, {10}}, {{1, 3, 9}, {1, 4}, {1, 10}}, {{4}, {2, 7, 9}, {6}}, {{5}, {6, 7, 8}, {1, 3, 4}}}},
+	{6, 8}:   {seqLen: 10, shares: [][][]int{{{9}, {3, 6, 9}}, {{2}, {0, 8}}, {{0, 1, 9}, {3}}, {{4, 5, 8}, {8}}, {{1, 5, 9}, {1, 6, 7}}, {{5, 7}, {0, 4, 8}}, {{1, 2, 3}, {4, 8}}, {{4, 6}, {2, 7}}}},
+	{2, 9}:   {seqLen: 5, shares: [][][]int{{{0, 3, 4}, {3}, {0, 1, 2}}, {{1, 2, 4}, {2}, {1, 3}}, {{0, 1}, {0}, {2, 4}}, {{0, 2}, {1, 4}, {0, 3}}, {{1}, {1, 2, 3}, {0, 1, 4}}, {{1, 3}, {1, 2}, {0}}, {{4}, {1, 3, 4}, {0, 2, 4}}, {{0, 1}, {3}, {3, 4}}, {{0, 3}, {4}, {2}}}},
+	{3, 9}:   {seqLen: 4, shares: [][][]int{{{1, 2, 3}, {0, 2}}, {{3}, {1, 3}}, {{1, 2}, {0, 3}}, {{0, 2}, {3}}, {{0, 3}, {1}}, {{1, 2}, {0}}, {{1, 3}, {2}}, {{0}, {0, 2, 3}}, {{0, 1}, {2}}}},
+	{4, 9}:   {seqLen: 8, shares: [][][]int{{{2}, {5, 6, 7}, {6}}, {{4, 6, 7}, {1}, {0, 4}}, {{1, 3, 5}, {3, 5, 6}, {0, 2, 4}}, {{4, 6}, {1, 5}, {0}}, {{3, 4}, {5}, {1, 2, 7}}, {{6}, {2, 3}, {7}}, {{3}, {1, 3, 4}, {0, 7}}, {{0, 4, 5}, {3}, {0, 2}}, {{2, 5, 6}, {0, 1, 7}, {3, 6}}}},
+	{5, 9}:   {seqLen: 10, shares: [][][]int{{{1, 2}, {0, 9}, {3}}, {{0}, {1, 5, 9}, {0, 4, 5}}, {{0, 1, 7}, {2, 4, 5}, {3, 5, 7}}, {{1, 3, 6}, {2, 7, 9}, {8}}, {{7}, {3, 5, 8}, {0, 2, 6}}, {{0, 5}, {1}, {3, 4, 6}}, {{2, 8, 9}, {1, 6, 7}, {5, 6}}, {{9}, {4}, {6, 7, 8}}, {{6}, {0, 7}, {4, 8, 9}}}},
+	{6, 9}:   {seqLen: 13, shares: [][][]int{{{12}, {3, 5, 6}, {7, 8, 9}}, {{4, 5}, {1, 3, 12}, {1, 11}}, {{0, 8}, {0, 4, 10}, {2, 9, 12}}, {{1}, {5}, {0, 7, 10}}, {{0, 11}, {4, 7, 9}, {2, 7, 11}}, {{8, 10, 11}, {1, 2, 3}, {6}}, {{2, 5, 8}, {0}, {3, 7, 11}}, {{1, 5, 8}, {1, 6}, {4}}, {{8, 9, 10}, {9}, {6, 7, 12}}}},
+	{7, 9}:   {seqLen: 17, shares: [][][]int{{{5, 14, 15}, {6}, {3, 9, 11}}, {{3, 5, 12}, {1, 9, 12}, {9}}, {{1, 10, 15}, {8, 11, 13}, {0, 4, 16}}, {{2, 6, 8}, {7, 11}, {11}}, {{0, 15}, {5, 16}, {15}}, {{2, 14}, {2, 4, 10}, {5, 13, 15}}, {{10, 12}, {6, 7, 9}, {14}}, {{0, 1, 3}, {2, 7, 12}, {13}}, {{8, 16}, {0, 5, 12}, {4, 9, 11}}}},
+	{2, 10}:  {seqLen: 4, shares: [][][]int{{{0, 1, 2}, {1, 3}, {0}}, {{3}, {1}, {0, 2, 3}}, {{1, 3}, {3}, {0}}, {{1}, {0, 2}, {0}}, {{3}, {0, 3}, {0, 1, 2}}, {{2}, {1}, {3}}, {{1, 2}, {1, 3}, {0, 3}}, {{2}, {1, 3}, {0}}, {{2}, {3}, {0, 2, 3}}, {{0, 2, 3}, {1, 2}, {2}}}},
+	{3, 10}:  {seqLen: 6, shares: [][][]int{{{1}, {4, 5}, {1, 2, 5}}, {{1, 4}, {3}, {0, 1}}, {{3}, {1, 3}, {0, 5}}, {{1, 4, 5}, {0, 2, 3}, {2}}, {{2, 4, 5}, {5}, {3, 4}}, {{0, 4}, {1, 3}, {1, 2, 3}}, {{5}, {0, 2}, {1, 5}}, {{4, 5}, {0, 1}, {2, 3}}, {{0}, {1, 4}, {2, 3, 5}}, {{4}, {0, 2, 5}, {0, 2, 3}}}},
+	{4, 10}:  {seqLen: 5, shares: [][][]int{{{2}, {1}}, {{2}, {0, 1, 3}}, {{4}, {0, 2}}, {{1, 3, 4}, {3}}, {{1, 2, 4}, {1}}, {{0, 1}, {2, 3, 4}}, {{2, 3}, {0, 4}}, {{3}, {0, 2, 3}}, {{0}, {1, 3, 4}}, {{1, 3}, {0, 1, 4}}}},
+	{5, 10}:  {seqLen: 10, shares: [][][]int{{{0, 4, 7}, {8}, {1, 3, 5}}, {{4, 5, 8}, {2, 5}, {3}}, {{3, 4, 7}, {7}, {4, 5, 9}}, {{1, 3, 9}, {6}, {0, 2, 4}}, {{9}, {2, 6}, {0, 5}}, {{4, 6}, {2, 8}, {1, 4, 6}}, {{2, 6, 8}, {0, 2, 9}, {3, 5, 7}}, {{2}, {1, 7, 9}, {0, 6, 8}}, {{1, 3, 7}, {0}, {3, 6, 9}}, {{1}, {4, 7, 8}, {1, 5, 9}}}},
+	{6, 10}:  {seqLen: 8, shares: [][][]int{{{0, 1, 2}, {6, 7}}, {{2, 5, 7}, {3}}, {{4, 5}, {0, 3, 7}}, {{1, 5, 7}, {1, 2, 4}}, {{0, 3, 6}, {4}}, {{1, 5}, {2, 3, 6}}, {{3, 4}, {0}}, {{1, 6}, {2}}, {{1}, {0, 4, 5}}, {{6}, {0, 1, 7}}}},
+	{7, 10}:  {seqLen: 15, shares: [][][]int{{{8, 12, 14}, {1, 2, 9}, {0, 8}}, {{2, 10}, {3, 9, 11}, {6, 7, 8}}, {{0, 2, 4}, {3, 5}, {8, 12, 13}}, {{1, 10, 13}, {7}, {12}}, {{7, 13}, {5, 7}, {3, 7, 10}}, {{3, 6}, {3, 10}, {5, 10, 14}}, {{4, 9, 10}, {6}, {11, 12, 14}}, {{2}, {10, 13}, {1, 4, 14}}, {{0, 11}, {5, 6, 9}, {7}}, {{4, 8}, {0, 1, 5}, {11}}}},
+	{8, 10}:  {seqLen: 20, shares: [][][]int{{{10, 18}, {0, 2, 4}, {1, 4}}, {{0, 3, 4}, {7, 18}, {8, 15}}, {{17, 18}, {3, 9, 11}, {2, 13}}, {{6, 11, 17}, {16}, {8}}, {{12, 15}, {10, 13, 19}, {5, 7, 16}}, {{12, 17, 19}, {9, 13}, {2}}, {{5, 14}, {3, 7}, {4, 9, 18}}, {{10}, {1, 8, 14}, {6, 16, 19}}, {{1, 11}, {14, 18}, {0, 15}}, {{4}, {5, 10, 12}, {6}}}},
+	{2, 11}:  {seqLen: 4, shares: [][][]int{{{1, 3}, {0, 1}, {0, 2, 3}}, {{0}, {1}, {2}}, {{2}, {1}, {2, 3}}, {{1}, {0, 1, 3}, {1, 2, 3}}, {{0}, {1, 2}, {1, 3}}, {{0}, {3}, {0, 2}}, {{3}, {1, 2}, {0, 1}}, {{0, 1, 3}, {0}, {1}}, {{0, 3}, {1}, {1, 2}}, {{0, 1}, {3}, {2, 3}}, {{0}, {1, 2, 3}, {0, 1}}}},
+	{3, 11}:  {seqLen: 5, shares: [][][]int{{{0, 1, 3}, {2, 3, 4}, {0}}, {{0}, {0, 4}, {0, 1, 2}}, {{1, 2, 3}, {0, 2, 4}, {2}}, {{3}, {1}, {2}}, {{0, 2, 4}, {3}, {0}}, {{1}, {2, 3}, {2, 4}}, {{2}, {0, 1, 3}, {0, 2, 4}}, {{0, 2, 4}, {1, 2}, {1, 2, 4}}, {{0, 3, 4}, {0}, {0, 1}}, {{2}, {0, 1, 3}, {2, 3}}, {{2, 3, 4}, {3}, {0, 2, 3}}}},
+	{4, 11}:  {seqLen: 7, shares: [][][]int{{{2}, {2, 3}, {0, 4, 5}}, {{1}, {0, 6}, {3, 4}}, {{0, 5, 6}, {2}, {0, 1, 3}}, {{1, 4, 5}, {1, 2}, {0, 2, 6}}, {{2, 5, 6}, {5}, {3, 5}}, {{3, 5, 6}, {0}, {1, 2, 5}}, {{2, 3}, {5}, {1, 4, 5}}, {{0, 3, 5}, {1, 2, 4}, {1, 6}}, {{5}, {4}, {0, 1, 4}}, {{1, 3, 6}, {0, 3}, {4}}, {{1}, {1, 2, 6}, {2, 4, 6}}}},
+	{5, 11}:  {seqLen: 9, shares: [][][]int{{{0, 1, 6}, {5, 8}, {3}}, {{4, 6, 7}, {8}, {0, 2, 7}}, {{6, 8}, {5}, {0, 3, 4}}, {{1, 4, 6}, {5, 6}, {0}}, {{1, 3}, {0, 5}, {2, 3, 4}}, {{3, 7}, {2}, {1, 7, 8}}, {{6, 7}, {1, 2, 8}, {1, 5}}, {{3, 4}, {5, 6, 7}, {6}}, {{2, 5}, {3, 5, 7}, {0, 1, 5}}, {{1}, {3, 7, 8}, {0, 2, 4}}, {{1, 4, 7}, {3, 6, 8}, {2}}}},
+	{6, 11}:  {seqLen: 11, shares: [][][]int{{{3, 5, 8}, {0}, {4, 9, 10}}, {{4, 8}, {5, 6, 7}, {0, 2, 10}}, {{1, 2, 7}, {0, 3, 5}, {5}}, {{3, 7}, {1, 3}, {8}}, {{5, 7, 9}, {4}, {1, 2, 10}}, {{2, 4}, {1}, {0, 1, 6}}, {{1, 7, 8}, {0, 9}, {0, 4, 5}}, {{9}, {2, 3}, {1, 6}}, {{5, 6, 10}, {9, 10}, {3}}, {{4, 8, 10}, {6}, {0, 2, 9}}, {{4, 7}, {6, 10}, {3, 5, 8}}}},
+	{7, 11}:  {seqLen: 9, shares: [][][]int{{{0, 2}, {8}}, {{0, 5, 8}, {3, 4}}, {{3, 6}, {1}}, {{0, 1, 3}, {6}}, {{2}, {6, 7, 8}}, {{7}, {2, 3, 6}}, {{0, 2, 5}, {1, 4}}, {{5}, {0, 7}}, {{1}, {4, 8}}, {{3, 4}, {1, 5, 7}}, {{2, 4, 7}, {5, 6, 8}}}},
+	{8, 11}:  {seqLen: 17, shares: [][][]int{{{10}, {3, 4, 10}, {6, 13, 16}}, {{1, 12}, {0, 8}, {3}}, {{2, 12, 16}, {9, 10, 15}, {3, 11, 13}}, {{2, 7}, {5, 9}, {0, 6, 13}}, {{2, 4, 8}, {11, 12, 15}, {0, 5, 14}}, {{8, 10, 16}, {13}, {1, 15}}, {{5}, {9, 14}, {0, 6, 13}}, {{1, 4, 16}, {3, 7, 13}, {1, 6, 14}}, {{6}, {15}, {2, 7, 8}}, {{2, 9}, {7, 10}, {2, 4, 11}}, {{1, 11, 14}, {12}, {1, 3, 5}}}},
+	{9, 11}:  {seqLen: 14, shares: [][][]int{{{1, 8, 12}, {4}}, {{3, 6}, {0, 5}}, {{1, 9, 10}, {1}}, {{1, 6}, {2, 3, 13}}, {{0, 13}, {1, 9, 10}}, {{4, 7, 11}, {5, 12}}, {{11, 13}, {7, 9, 11}}, {{2, 8, 10}, {6, 11}}, {{2, 6}, {3, 8}}, {{0}, {4, 5}}, {{7}, {8, 9, 12}}}},
+	{2, 12}:  {seqLen: 4, shares: [][][]int{{{0, 2, 3}, {1, 2, 3}, {2}}, {{3}, {0}, {0, 1, 2}}, {{1, 3}, {2}, {0, 2}}, {{1}, {1, 2}, {0, 2, 3}}, {{1, 3}, {3}, {2}}, {{0, 1, 3}, {0}, {0, 1, 2}}, {{1}, {3}, {0, 2}}, {{1}, {3}, {0, 3}}, {{1}, {0, 3}, {0, 1, 2}}, {{2, 3}, {2}, {0, 2}}, {{0, 1, 2}, {1, 2}, {2}}, {{0, 1, 2}, {0, 1}, {3}}}},
+	{3, 12}:  {seqLen: 5, shares: [][][]int{{{4}, {1, 2}, {0, 1, 4}}, {{1, 3}, {0, 1}, {3, 4}}, {{3, 4}, {0, 2, 4}, {3}}, {{0, 3, 4}, {0}, {1, 2}}, {{0, 2, 3}, {1, 2}, {1}}, {{3}, {2, 3}, {0, 1, 4}}, {{1, 3}, {0, 2}, {1}}, {{1}, {2, 4}, {3, 4}}, {{3}, {0, 1, 4}, {1}}, {{1}, {1, 2}, {4}}, {{0, 2, 4}, {2, 3}, {1, 3, 4}}, {{0}, {4}, {2, 3}}}},
+	{4, 12}:  {seqLen: 7, shares: [][][]int{{{0, 1}, {0, 2, 6}, {5}}, {{5}, {1, 2, 3}, {0, 2, 6}}, {{3, 4}, {0}, {0, 6}}, {{2, 5}, {1, 5}, {0, 1, 3}}, {{1, 6}, {2, 4}, {3, 5, 6}}, {{4}, {3, 4, 5}, {0, 1, 6}}, {{2, 5}, {4}, {2, 3, 4}}, {{0, 2}, {0}, {1, 4, 5}}, {{6}, {0, 5, 6}, {2, 4}}, {{1, 5, 6}, {0, 4}, {3}}, {{0, 4}, {2, 3, 6}, {1}}, {{1, 2, 3}, {4, 6}, {2}}}},
+	{5, 12}:  {seqLen: 8, shares: [][][]int{{{7}, {0, 7}, {3, 6, 7}}, {{0, 2, 5}, {0, 4}, {5, 6}}, {{0, 1, 5}, {3, 4, 7}, {6}}, {{0, 3, 4}, {1, 5}, {2, 3}}, {{2, 3, 7}, {6, 7}, {1}}, {{7}, {0, 1, 2}, {4}}, {{1, 4, 7}, {0, 3, 4}, {2, 5}}, {{1, 6}, {5, 6, 7}, {4}}, {{3}, {4, 6}, {5}}, {{2, 6}, {3}, {0, 3, 5}}, {{3, 4, 7}, {1, 2, 4}, {5}}, {{1, 6}, {0, 5}, {0, 2, 7}}}},
+	{6, 12}:  {seqLen: 10, shares: [][][]int{{{0, 6, 9}, {2, 5, 8}, {1}}, {{4, 6, 8}, {0, 3}, {1, 2, 9}}, {{0, 6}, {2, 8, 9}, {1, 4, 7}}, {{2}, {3, 4, 7}, {5, 6}}, {{6}, {0, 2, 9}, {1, 7, 9}}, {{0}, {5, 7}, {4, 9}}, {{2, 3, 9}, {4}, {3, 6, 8}}, {{6}, {4, 5, 6}, {0, 2, 7}}, {{2, 3}, {0, 1, 5}, {0, 7}}, {{3, 8, 9}, {8}, {1, 5, 7}}, {{7}, {2, 3, 5}, {7, 8}}, {{7, 8}, {4}, {1, 2, 3}}}},
+	{7, 12}:  {seqLen: 8, shares: [][][]int{{{3, 4}, {1, 2, 7}}, {{4, 5}, {1, 6, 7}}, {{4}, {0, 5, 6}}, {{2, 3}, {0, 1, 3}}, {{0, 4}, {2, 3, 7}}, {{1}, {2, 4, 6}}, {{1, 5, 6}, {0, 1, 3}}, {{0}, {2, 5}}, {{3, 4, 6}, {1, 7}}, {{2}, {2, 6}}, {{5}, {2, 3, 7}}, {{7}, {0, 5}}}},
+	{8, 12}:  {seqLen: 10, shares: [][][]int{{{8}, {2, 4, 8}}, {{0, 4, 8}, {1, 2}}, {{0, 6}, {2, 5, 7}}, {{2, 7}, {3, 5, 9}}, {{9}, {0, 6, 8}}, {{5, 6, 8}, {0, 7, 9}}, {{4}, {2, 6, 9}}, {{3}, {1, 5, 9}}, {{3, 4, 7}, {5}}, {{7}, {1, 3, 8}}, {{0, 1}, {6}}, {{2, 6, 7}, {1, 3, 4}}}},
+	{9, 12}:  {seqLen: 12, shares: [][][]int{{{3, 5}, {0, 11}}, {{3, 8}, {9}}, {{0, 4, 9}, {8, 9}}, {{10}, {1, 6, 8}}, {{4}, {5, 10, 11}}, {{2, 3, 6}, {7, 11}}, {{1, 2, 10}, {7}}, {{4, 10}, {6}}, {{1, 5, 9}, {2, 4, 7}}, {{0, 2}, {5, 10}}, {{9, 11}, {0, 6}}, {{1, 3}, {7, 8}}}},
+	{10, 12}: {seqLen: 16, shares: [][][]int{{{4, 12}, {1, 11, 14}}, {{0, 9, 14}, {2, 3, 5}}, {{0, 3, 15}, {7, 10, 11}}, {{12}, {10}}, {{1, 5, 13}, {5, 8, 9}}, {{0, 6, 9}, {8}}, {{0, 6}, {2, 5, 13}}, {{3, 12}, {0}}, {{0, 7, 14}, {6, 10, 15}}, {{1}, {4, 7, 12}}, {{8, 15}, {5, 11}}, {{2, 4}, {13}}}},
+	{2, 13}:  {seqLen: 4, shares: [][][]int{{{1, 2}, {2}, {0, 2, 3}}, {{1, 2}, {2}, {0, 1, 2}}, {{0, 2, 3}, {1}, {3}}, {{0}, {1, 3}, {0, 1, 2}}, {{1, 3}, {1, 2}, {0, 1}}, {{1}, {0}, {0, 2, 3}}, {{2}, {3}, {0, 1, 2}}, {{0}, {2, 3}, {3}}, {{0, 1, 2}, {0, 1, 3}, {1}}, {{2}, {1, 3}, {0, 2}}, {{0}, {3}, {0, 1, 2}}, {{1, 2, 3}, {0, 1, 3}, {3}}, {{0}, {1, 3}, {3}}}},
+	{3, 13}:  {seqLen: 5, shares: [][][]int{{{1, 2, 3}, {4}, {0, 2, 3}}, {{2}, {0, 2}, {0, 2, 3}}, {{1, 4}, {0, 4}, {3, 4}}, {{1, 2}, {0, 3, 4}, {1}}, {{0, 1, 3}, {3}, {2, 4}}, {{2}, {0}, {0, 1, 4}}, {{1, 3, 4}, {0, 1}, {2, 3}}, {{1, 3}, {3}, {1, 2, 4}}, {{3, 4}, {1, 4}, {1}}, {{1, 2}, {2}, {0, 2, 3}}, {{0, 1, 4}, {1, 2, 3}, {0}}, {{0, 2, 4}, {1, 2}, {3}}, {{4}, {0, 2, 4}, {1}}}},
+	{4, 13}:  {seqLen: 4, shares: [][][]int{{{3}, {1, 3}}, {{1}, {0, 1, 3}}, {{1}, {0, 1, 2}}, {{0}, {1, 3}}, {{0, 2, 3}, {3}}, {{0, 3}, {1, 2, 3}}, {{2}, {0, 3}}, {{3}, {1, 2}}, {{2}, {0, 1}}, {{2, 3}, {1, 3}}, {{0, 2}, {0}}, {{1, 2, 3}, {0}}, {{0, 1}, {2, 3}}}},
+	{5, 13}:  {seqLen: 8, shares: [][][]int{{{0, 3, 6}, {4, 5}, {2}}, {{7}, {1}, {2, 3, 4}}, {{0, 4, 5}, {1, 4, 7}, {2}}, {{0, 1, 2}, {2, 3, 4}, {4}}, {{1, 7}, {3, 5, 6}, {0, 1}}, {{0, 1, 7}, {6}, {3, 4, 7}}, {{1, 3}, {2, 4, 6}, {4, 5}}, {{4, 7}, {6}, {0}}, {{2, 5, 7}, {3}, {0, 3, 4}}, {{2, 5}, {0, 3, 7}, {3, 6, 7}}, {{0, 1, 2}, {5, 6, 7}, {5}}, {{2, 3, 7}, {1}, {0, 5, 6}}, {{1, 4}, {5, 6}, {5}}}},
+	{6, 13}:  {seqLen: 6, shares: [][][]int{{{2, 4, 5}, {0}}, {{1, 2}, {3, 4}}, {{1, 4}, {3}}, {{0, 1, 5}, {2, 3}}, {{2, 4, 5}, {3}}, {{0, 2, 4}, {4}}, {{1, 5}, {0, 3}}, {{1}, {0, 4, 5}}, {{2, 3, 5}, {2, 4}}, {{5}, {1, 4, 5}}, {{0, 1, 3}, {2, 5}}, {{0, 1, 2}, {1}}, {{0, 3, 4}, {5}}}},
+	{7, 13}:  {seqLen: 12, shares: [][][]int{{{2, 4, 11}, {3, 5, 8}, {6}}, {{1, 8, 10}, {0}, {2, 9}}, {{6, 8, 9}, {2, 11}, {2, 4, 7}}, {{0, 1}, {2, 7, 8}, {3, 9}}, {{1, 4, 10}, {7}, {0, 3, 11}}, {{5, 8, 9}, {4, 5, 11}, {1, 8}}, {{0, 2, 7}, {2, 6}, {3, 5, 8}}, {{0, 6}, {5}, {2, 4, 10}}, {{10}, {0, 2, 9}, {7, 11}}, {{4, 6, 10}, {11}, {0, 5}}, {{2}, {1, 6, 9}, {3, 7, 10}}, {{3, 4, 5}, {1, 7, 8}, {9}}, {{5, 9}, {2, 3, 6}, {1, 10, 11}}}},
+	{8, 13}:  {seqLen: 9, shares: [][][]int{{{0, 4, 7}, {3}}, {{2, 4, 8}, {1}}, {{3, 5, 6}, {1, 7, 8}}, {{3, 4, 8}, {1, 5, 6}}, {{0, 2, 6}, {6}}, {{1, 3, 7}, {0, 4}}, {{7}, {1, 2, 6}}, {{0, 2, 3}, {5, 7, 8}}, {{2}, {6}}, {{5}, {2, 8}}, {{3, 6, 7}, {0, 7, 8}}, {{7}, {4, 5}}, {{0, 1, 5}, {4}}}},
+	{9, 13}:  {seqLen: 17, shares: [][][]int{{{13, 15}, {0, 5}, {3, 4, 7}}, {{4, 13}, {8, 12, 16}, {2, 6}}, {{6, 10, 14}, {5, 9, 12}, {4, 15, 16}}, {{1, 3, 8}, {2, 9, 16}, {4}}, {{2}, {3}, {1, 9, 11}}, {{6, 12}, {5, 12, 15}, {0, 10, 16}}, {{1, 7, 13}, {4, 8, 9}, {0, 10, 11}}, {{2, 10, 13}, {14}, {6, 11, 15}}, {{5, 8, 13}, {0, 11, 12}, {7}}, {{1}, {2, 9}, {11, 16}}, {{3, 11, 14}, {6}, {1, 5, 12}}, {{7, 8}, {12, 14}, {10}}, {{0, 14, 15}, {9}, {3, 7}}}},
+	{10, 13}: {seqLen: 20, shares: [][][]int{{{4, 5}, {2, 8}, {11, 12, 15}}, {{1, 6, 10}, {14, 17}, {9, 17, 19}}, {{17}, {7, 8, 16}, {9, 18}}, {{0, 5, 7}, {1, 9, 15}, {10}}, {{4}, {5, 13}, {3, 14, 17}}, {{4, 6, 16}, {18}, {2, 3, 17}}, {{2, 10, 19}, {7, 15, 17}, {8, 10, 11}}, {{16, 18}, {3, 13, 15}, {14}}, {{1, 12}, {3, 16}, {6, 8, 12}}, {{12, 19}, {7, 10}, {0, 1, 19}}, {{5, 11}, {13, 19}, {0}}, {{1, 10, 13}, {13, 18}, {9, 14}}, {{2, 15, 18}, {6, 11, 16}, {0, 4, 12}}}},
+	{11, 13}: {seqLen: 18, shares: [][][]int{{{17}, {5, 6, 10}}, {{14, 17}, {0, 1, 3}}, {{11, 13, 14}, {2, 6, 7}}, {{10, 11}, {7, 11, 12}}, {{4, 13}, {3}}, {{2, 3, 15}, {0}}, {{10}, {6, 8, 16}}, {{12, 17}, {0, 5}}, {{5, 15}, {8, 11, 17}}, {{2, 4}, {9}}, {{4, 16}, {4, 10, 14}}, {{1, 8, 9}, {16}}, {{1, 12, 13}, {7, 9, 15}}}},
+	{2, 14}:  {seqLen: 4, shares: [][][]int{{{0, 2}, {1}, {0}}, {{0, 1, 3}, {3}, {0, 2}}, {{1, 3}, {0}, {2}}, {{0}, {3}, {2, 3}}, {{0, 1}, {0, 2}, {0, 3}}, {{3}, {0, 1, 2}, {1}}, {{2}, {0, 1}, {1, 2, 3}}, {{1, 3}, {0, 1, 3}, {3}}, {{2, 3}, {0, 1, 2}, {3}}, {{1}, {1, 2}, {3}}, {{2}, {0, 1, 3}, {1, 2}}, {{0}, {2, 3}, {1, 2}}, {{3}, {0, 3}, {1, 2, 3}}, {{0, 1, 2}, {0, 1, 3}, {1}}}},
+	{3, 14}:  {seqLen: 5, shares: [][][]int{{{0, 2}, {4}, {1, 3, 4}}, {{2, 3}, {1, 4}, {0, 1, 3}}, {{2}, {2, 3, 4}, {1}}, {{0}, {2, 3}, {0, 1}}, {{3}, {0}, {0, 2, 3}}, {{4}, {3}, {0, 1}}, {{0, 3}, {1, 2, 4}, {4}}, {{1, 2, 4}, {0}, {2, 3, 4}}, {{3, 4}, {2}, {1}}, {{2, 3}, {0, 1, 4}, {1}}, {{0, 4}, {1, 2}, {0}}, {{1, 3, 4}, {0}, {3}}, {{0, 2, 4}, {0, 2}, {1}}, {{2, 4}, {0, 1}, {0, 3}}}},
+	{4, 14}:  {seqLen: 4, shares: [][][]int{{{2}, {0, 1, 2}}, {{1}, {2, 3}}, {{1, 2}, {0, 3}}, {{0, 3}, {1}}, {{1, 2}, {0}}, {{3}, {0, 2}}, {{2, 3}, {0}}, {{1}, {3}}, {{0, 2}, {1, 3}}, {{2}, {1, 2, 3}}, {{0, 1}, {3}}, {{0, 2}, {2, 3}}, {{0}, {0, 1, 2}}, {{0, 1, 3}, {2}}}},
+	{5, 14}:  {seqLen: 8, shares: [][][]int{{{1, 6}, {2, 3}, {4, 7}}, {{5, 7}, {0, 2, 3}, {1, 4, 6}}, {{0, 2, 5}, {7}, {0}}, {{2, 6}, {5}, {0, 3, 7}}, {{1, 2, 7}, {0}, {0, 3, 5}}, {{7}, {1, 3, 6}, {2, 4, 5}}, {{4, 5, 6}, {1}, {0, 1, 5}}, {{3, 4}, {0}, {2, 6}}, {{2, 3, 6}, {0, 4, 7}, {2}}, {{1, 2, 5}, {1, 7}, {0, 4}}, {{0, 2, 3}, {0, 1, 4}, {6}}, {{5, 6}, {0, 1}, {0, 3, 4}}, {{1, 5}, {3}, {4, 7}}, {{3, 7}, {1, 5}, {6}}}},
+	{6, 14}:  {seqLen: 9, shares: [][][]int{{{1, 4, 5}, {0, 2}, {0, 4, 8}}, {{1}, {5}, {0, 7, 8}}, {{2, 4}, {7}, {0, 5, 6}}, {{1, 3}, {0, 3, 7}, {2, 6, 8}}, {{0}, {7}, {3, 5, 7}}, {{2}, {2, 3, 4}, {0, 6}}, {{2, 5, 7}, {6, 8}, {0}}, {{4}, {0}, {4, 6, 8}}, {{0, 3, 7}, {1, 2}, {4, 8}}, {{1, 3, 6}, {4, 5}, {8}}, {{2, 5}, {1, 3, 4}, {1, 6, 7}}, {{6}, {1, 2, 7}, {3, 4, 5}}, {{1, 4, 5}, {3}, {2, 7, 8}}, {{2, 5, 6}, {1, 3, 8}, {5}}}},
+	{7, 14}:  {seqLen: 11, shares: [][][]int{{{4, 5}, {2}, {1, 10}}, {{2, 7, 8}, {5, 10}, {9}}, {{1, 3, 7}, {5}, {0, 1, 4}}, {{4, 9}, {2, 8}, {3, 6, 10}}, {{0, 3, 6}, {1, 3, 6}, {7, 9}}, {{0, 8, 9}, {4, 6}, {1, 2, 5}}, {{2, 3, 4}, {1, 7}, {8, 10}}, {{8, 9}, {10}, {1, 2, 5}}, {{2, 5, 7}, {0, 2, 4}, {3}}, {{6, 8, 9}, {1}, {0, 3, 10}}, {{1, 5}, {3, 4, 7}, {3, 6, 8}}, {{6}, {3, 5, 7}, {0, 9}}, {{0, 6, 10}, {7}, {2, 3, 9}}, {{4, 6, 7}, {4, 8, 10}, {0}}}},
+	{8, 14}:  {seqLen: 13, shares: [][][]int{{{9, 10}, {4}, {1, 2}}, {{0, 2}, {3, 11}, {6, 9, 12}}, {{10, 11}, {6, 8, 12}, {3}}, {{6, 7, 9}, {2, 3, 4}, {8}}, {{2, 4}, {1, 10, 12}, {0, 5, 11}}, {{8, 11}, {2, 12}, {5, 10}}, {{5, 6, 9}, {1, 7}, {5, 8, 12}}, {{3, 4, 11}, {0, 7, 9}, {10}}, {{7, 10}, {1, 8, 10}, {1, 4, 11}}, {{5, 9}, {3, 10, 12}, {0, 6}}, {{4, 7, 8}, {0, 1, 6}, {2}}, {{3, 5, 11}, {1}, {2, 7, 10}}, {{0, 3, 9}, {6, 8}, {4, 5}}, {{1, 4, 12}, {0, 5, 7}, {9}}}},
+	{9, 14}:  {seqLen: 15, shares: [][][]int{{{0, 6, 14}, {4, 5, 9}, {10}}, {{6}, {2, 7, 9}, {3, 6, 11}}, {{8}, {1, 6}, {7, 10}}, {{9}, {5}, {8, 11, 12}}, {{4, 5, 14}, {12}, {1, 8, 13}}, {{5, 8, 9}, {2, 12, 13}, {3, 8}}, {{3, 5}, {8, 10, 11}, {11, 13}}, {{0, 3}, {11, 13}, {1}}, {{0}, {2, 6}, {1, 3, 14}}, {{7, 9, 11}, {1, 3, 4}, {6, 13}}, {{2, 5, 13}, {0, 13, 14}, {6, 7, 12}}, {{4, 7, 12}, {3, 8, 9}, {0, 2, 10}}, {{14}, {4, 7, 10}, {0, 1, 12}}, {{13}, {2, 10, 14}, {4, 11, 14}}}},
+	{10, 14}: {seqLen: 12, shares: [][][]int{{{0, 8, 9}, {2, 3, 10}}, {{1, 4, 11}, {6}}, {{1, 3, 10}, {3}}, {{0, 11}, {1, 5, 8}}, {{4, 6}, {2, 4, 7}}, {{3}, {2, 5, 7}}, {{0, 7, 9}, {2}}, {{0}, {6, 8}}, {{10, 11}, {1, 4}}, {{5}, {6, 7, 8}}, {{0, 4, 6}, {2, 5}}, {{8, 11}, {5, 9, 10}}, {{3, 4, 11}, {9, 10}}, {{1, 3, 7}, {9}}}},
+	{11, 14}: {seqLen: 23, shares: [][][]int{{{1}, {7}, {8, 16, 20}}, {{2, 10, 12}, {17, 22}, {1, 13, 18}}, {{0, 6, 19}, {9, 10, 20}, {2, 5, 18}}, {{5, 11, 20}, {0, 5, 13}, {1, 6, 14}}, {{3, 10, 12}, {14, 15}, {19}}, {{18, 19}, {7, 21, 22}, {15, 16, 19}}, {{7, 8, 17}, {21}, {6, 17, 18}}, {{0, 1, 5}, {11, 15}, {9, 16, 17}}, {{9, 19, 22}, {0, 3, 6}, {4, 5}}, {{2, 8, 9}, {3, 4, 7}, {5, 11, 21}}, {{15}, {8, 12}, {9, 17, 20}}, {{6}, {10, 11}, {4, 18, 21}}, {{13}, {3}, {2, 14, 16}}, {{4, 13}, {12, 14}, {22}}}},
+	{12, 14}: {seqLen: 20, shares: [][][]int{{{2, 6, 15}, {4, 17}}, {{2}, {1, 13, 14}}, {{1}, {6, 12, 18}}, {{4}, {0}}, {{13}, {14, 16}}, {{1, 10, 12}, {11}}, {{3, 5}, {3, 9, 12}}, {{8, 9}, {0, 3, 14}}, {{5, 7, 17}, {4, 8, 16}}, {{15, 16}, {13, 17, 18}}, {{5, 15}, {0, 3, 19}}, {{3, 11}, {5, 6, 10}}, {{0, 7}, {2, 8, 19}}, {{7, 10, 18}, {9, 11, 19}}}},
+	{2, 15}:  {seqLen: 4, shares: [][][]int{{{2}, {0, 2, 3}, {1}}, {{1, 3}, {2, 3}, {2}}, {{0, 2, 3}, {1}, {0, 1}}, {{1, 2, 3}, {3}, {0, 3}}, {{3}, {0, 1}, {0, 2}}, {{1, 3}, {0, 1}, {1, 2}}, {{1}, {1, 2, 3}, {0, 2}}, {{1, 2}, {0}, {0, 1, 3}}, {{3}, {1}, {0, 2, 3}}, {{3}, {1}, {0, 3}}, {{3}, {0, 1, 2}, {2}}, {{0}, {2}, {1, 2, 3}}, {{0, 1}, {0, 2, 3}, {2}}, {{1, 2}, {0, 1}, {2}}, {{3}, {0, 3}, {2, 3}}}},
+	{3, 15}:  {seqLen: 5, shares: [][][]int{{{0, 3}, {1, 3, 4}, {2, 4}}, {{3}, {0, 2, 4}, {0, 1, 3}}, {{1, 3}, {0, 3, 4}, {2, 4}}, {{1}, {0, 2, 3}, {0}}, {{2, 4}, {2}, {2, 3}}, {{2}, {0, 2, 4}, {1, 3}}, {{1, 2}, {0, 3}, {4}}, {{1, 4}, {2, 3}, {0}}, {{3, 4}, {0, 2}, {1}}, {{3}, {4}, {0, 1, 4}}, {{4}, {0, 1, 2}, {0}}, {{4}, {0, 2, 4}, {1, 4}}, {{3}, {2, 3, 4}, {1, 3, 4}}, {{2}, {0, 2, 3}, {0, 4}}, {{1}, {0, 1, 4}, {3}}}},
+	{4, 15}:  {seqLen: 6, shares: [][][]int{{{0, 3, 5}, {1, 2, 4}, {4}}, {{1}, {0, 1, 3}, {0, 5}}, {{1}, {0, 3, 4}, {1, 3}}, {{4, 5}, {2, 4}, {5}}, {{2}, {0, 1, 5}, {3, 4}}, {{1, 2, 3}, {0}, {4}}, {{3, 4, 5}, {2, 4, 5}, {1, 4}}, {{3}, {0}, {2}}, {{3, 4, 5}, {1}, {0, 2, 4}}, {{0, 3, 4}, {0, 2, 5}, {0}}, {{1, 2, 4}, {0, 3, 5}, {0}}, {{0, 2}, {0, 1, 5}, {5}}, {{1, 4, 5}, {3, 4}, {3}}, {{1, 2, 5}, {0, 1, 4}, {1}}, {{1, 3}, {5}, {0, 2, 4}}}},
+	{5, 15}:  {seqLen: 7, shares: [][][]int{{{3}, {0, 1, 5}, {2, 4}}, {{4}, {1, 5}, {0, 6}}, {{1}, {0, 1, 4}, {2, 5}}, {{6}, {4, 6}, {1, 4}}, {{1, 2, 4}, {3, 5}, {3, 4, 6}}, {{3}, {3, 4, 5}, {0}}, {{0}, {0, 2, 6}, {1, 3, 4}}, {{2, 3}, {0, 4, 6}, {0, 5}}, {{1}, {3, 6}, {0, 2, 5}}, {{1, 4, 6}, {0}, {3, 5}}, {{2, 5}, {4}, {3, 6}}, {{0, 1}, {2}, {1, 3, 5}}, {{1, 3}, {0, 3, 6}, {2}}, {{0, 3, 5}, {2, 6}, {3}}, {{2, 3, 4}, {6}, {1, 5}}}},
+	{6, 15}:  {seqLen: 8, shares: [][][]int{{{5, 6}, {1}, {4, 7}}, {{1, 4, 6}, {3, 4, 5}, {7}}, {{0, 3}, {1, 6, 7}, {2, 5, 6}}, {{2, 3}, {0, 2, 5}, {1, 3}}, {{4, 6}, {0}, {1, 7}}, {{4, 5}, {6}, {2}}, {{3, 4, 6}, {2, 5, 6}, {0, 4}}, {{3, 7}, {1, 4}, {2, 3, 6}}, {{1, 2, 4}, {1}, {0, 1, 3}}, {{0}, {2, 4, 7}, {3}}, {{2, 5, 7}, {2}, {1, 6}}, {{2, 3}, {6}, {0, 2, 7}}, {{0}, {4, 5, 7}, {1, 3, 7}}, {{4, 5, 6}, {0, 5}, {4}}, {{1, 6}, {0, 7}, {2, 3, 5}}}},
+	{7, 15}:  {seqLen: 10, shares: [][][]int{{{2, 6, 7}, {0, 1, 9}, {1, 3}}, {{0, 8, 9}, {4, 7}, {2, 5, 7}}, {{3, 6, 7}, {8}, {2, 4}}, {{8}, {1}, {2, 7, 9}}, {{2}, {0, 5, 9}, {2, 4, 6}}, {{0, 8}, {3, 5, 6}, {2, 8}}, {{4, 5, 6}, {1, 7, 8}, {0}}, {{7}, {0, 1, 5}, {4}}, {{6, 7}, {3, 5, 9}, {0, 1}}, {{0, 3, 4}, {1, 2}, {1, 9}}, {{4, 7, 9}, {3, 4, 8}, {1, 2, 6}}, {{3}, {1, 6, 8}, {4, 8, 9}}, {{5, 6, 8}, {0, 1}, {1}}, {{7}, {2, 5, 9}, {3, 4, 8}}, {{1, 6}, {5}, {3, 5}}}},
+	{8, 15}:  {seqLen: 12, shares: [][][]int{{{1, 2, 5}, {0, 10}, {6, 8, 9}}, {{3, 5, 11}, {2, 6, 8}, {4}}, {{7}, {0, 1, 4}, {3, 5, 10}}, {{5, 9, 10}, {0, 4, 11}, {2}}, {{1, 11}, {10}, {4, 8}}, {{5, 7, 8}, {1, 6, 9}, {8}}, {{2}, {0, 7, 9}, {3, 6, 10}}, {{3}, {4, 7, 8}, {4, 6, 9}}, {{2, 10}, {8}, {11}}, {{5, 9, 10}, {3}, {0, 8}}, {{0, 2}, {3, 11}, {3, 7, 9}}, {{0, 6, 7}, {1, 2, 4}, {0, 5}}, {{0, 7, 11}, {3, 6}, {1, 7, 10}}, {{7, 8, 10}, {1, 3}, {4, 11}}, {{2, 5, 9}, {1}, {4, 6, 11}}}},
+	{9, 15}:  {seqLen: 14, shares: [][][]int{{{6, 10}, {0, 2, 5}, {0, 9, 11}}, {{2, 5}, {2, 9, 11}, {1, 9}}, {{0, 4, 7}, {5, 9}, {2}}, {{3, 7, 9}, {4}, {5, 13}}, {{6}, {1, 3, 10}, {7, 9}}, {{8, 12}, {6, 10, 13}, {2, 3, 11}}, {{5, 6, 13}, {3, 9, 12}, {0, 1}}, {{12}, {5, 9, 11}, {1, 3, 6}}, {{0, 7, 12}, {4, 8, 12}, {12}}, {{4, 7, 13}, {7, 12}, {4, 8, 11}}, {{2, 6, 11}, {4, 5, 8}, {3}}, {{7}, {6, 8, 10}, {0, 1, 13}}, {{10, 12, 13}, {0, 6}, {3, 4, 8}}, {{1, 2, 8}, {10}, {11, 12, 13}}, {{1, 9, 13}, {0, 2, 4}, {7, 10}}}},
+	{10, 15}: {seqLen: 11, shares: [][][]int{{{2, 5, 10}, {0}}, {{0, 2, 3}, {5, 6, 7}}, {{2, 7, 9}, {2, 4, 10}}, {{1, 6}, {3, 5, 10}}, {{7}, {2, 4, 10}}, {{1, 4, 8}, {6, 7, 9}}, {{2, 4}, {0, 3, 9}}, {{4, 6, 9}, {2}}, {{0, 3}, {1, 8}}, {{4, 7, 8}, {0, 8}}, {{3}, {1, 4, 10}}, {{5, 6, 8}, {1}}, {{0, 5, 8}, {9}}, {{1, 10}, {3, 6}}, {{8}, {5, 7, 9}}}},
+	{11, 15}: {seqLen: 13, shares: [][][]int{{{1}, {0, 6, 11}}, {{3, 8, 11}, {0, 7, 9}}, {{9, 11, 12}, {1, 2, 10}}, {{7, 8, 10}, {5}}, {{6}, {12}}, {{4, 10}, {3, 11}}, {{2, 3, 11}, {1, 3, 9}}, {{5, 11}, {5, 7, 8}}, {{5, 6, 9}, {0}}, {{1, 5, 12}, {0, 2, 8}}, {{0, 3}, {2, 4, 7}}, {{4, 7}, {10, 12}}, {{2, 7, 9}, {10}}, {{4}, {6, 8, 12}}, {{1, 3}, {4, 5, 6}}}},
+	{12, 15}: {seqLen: 25, shares: [][][]int{{{6, 20, 23}, {5, 11, 14}, {14}}, {{9, 24}, {4, 11, 17}, {0, 10}}, {{8, 24}, {1, 7, 13}, {10, 11, 23}}, {{14, 16, 20}, {0, 6}, {12}}, {{12, 14, 23}, {10, 18}, {7}}, {{0}, {8, 19}, {2, 18, 22}}, {{9, 13, 19}, {16, 17}, {2, 3, 8}}, {{2, 5, 23}, {4, 12, 19}, {7, 11}}, {{14, 19, 24}, {22}, {0, 10, 13}}, {{15, 22}, {1, 9, 24}, {5, 7, 20}}, {{2, 9, 21}, {5, 18, 19}, {3, 4, 22}}, {{8, 15}, {17, 20, 21}, {1, 6, 16}}, {{3, 6, 13}, {19}, {3, 15, 17}}, {{3, 21}, {18}, {15, 16, 22}}, {{0, 1}, {12, 21}, {4, 7, 19}}}},
+	{13, 15}: {seqLen: 21, shares: [][][]int{{{1, 7, 9}, {12, 18}}, {{5}, {1, 4, 18}}, {{3, 9, 19}, {2, 8}}, {{12, 13}, {20}}, {{2, 11, 19}, {1, 11}}, {{3, 8, 17}, {12, 15, 20}}, {{2, 10, 14}, {6, 14, 18}}, {{0}, {5, 6, 18}}, {{8}, {9, 11}}, {{13, 14}, {0, 19}}, {{3, 7, 15}, {14}}, {{4, 8, 17}, {5, 10}}, {{4, 7, 10}, {6, 11, 16}}, {{16, 17, 19}, {17}}, {{2, 15, 16}, {0, 13, 20}}}},
+}
@@ -0,0 +1,249 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+
+	"golang.org/x/image/math/f32"
+)
+
+// Template describes a plate blank and its placement on the
+// engraver bed. All dimensions are in millimeters.
+type Template struct {
+	Name string
+	// Size is the width and height of the plate.
+	Size image.Point
+	// Offset is the position of the plate on the bed.
+	Offset image.Point
+	// Margin is the minimum distance from engravings to the plate
+	// edges.
+	Margin int
+	// KeepOut lists the regions of the plate that must not be engraved,
+	// such as screw holes.
+	KeepOut []image.Rectangle
+}
+
+// Bounds returns the area of the bed covered by the plate.
+func (t Template) Bounds() image.Rectangle {
+	return image.Rectangle{Max: t.Size}.Add(t.Offset)
+}
+
+// cornerHole is the size of the corner screw hole regions of the
+// SeedHammer plates.
+const cornerHole = 9
+
+// Template returns the template of a SeedHammer plate.
+func (p PlateSize) Template() Template {
+	w, h := p.dims()
+	x, y := p.offset()
+	t := Template{
+		Size:   image.Pt(w, h),
+		Offset: image.Pt(x, y),
+		Margin: outerMargin,
+		// Screw holes, including clearance, in every corner.
+		KeepOut: []image.Rectangle{
+			image.Rect(0, 0, cornerHole, cornerHole),
+			image.Rect(w-cornerHole, 0, w, cornerHole),
+			image.Rect(0, h-cornerHole, cornerHole, h),
+			image.Rect(w-cornerHole, h-cornerHole, w, h),
+		},
+	}
+	switch p {
+	case SmallPlate:
+		t.Name = "SH01"
+	case SquarePlate:
+		t.Name = "SH02"
+	case LargePlate:
+		t.Name = "SH03"
+		// And in the middle of the long sides.
+		t.KeepOut = append(t.KeepOut,
+			image.Rect(0, 39, innerMargin, 49),
+			image.Rect(w-innerMargin, 39, w, 49),
+		)
+	}
+	return t
+}
+
+// SeedHammerTemplates returns the templates of the SeedHammer
+// plates, from smallest to largest.
+func SeedHammerTemplates() []Template {
+	return []Template{
+		SmallPlate.Template(),
+		SquarePlate.Template(),
+		LargePlate.Template(),
+	}
+}
+
+// templateJSON is the data file representation of a Template.
+type templateJSON struct {
+	Name    string   `json:"name"`
+	Size    [2]int   `json:"size"`
+	Offset  [2]int   `json:"offset"`
+	Margin  int      `json:"margin"`
+	KeepOut [][4]int `json:"keepout"`
+}
+
+// LoadTemplates decodes a JSON list of templates such as
+//
+//	[{
+//		"name": "Steel card",
+//		"size": [85, 54],
+//		"offset": [97, 0],
+//		"margin": 3,
+//		"keepout": [[0, 0, 10, 10]]
+//	}]
+//
+// where keep-out regions are given as [x0, y0, x1, y1] relative to
+// the top left corner of the plate.
+func LoadTemplates(r io.Reader) ([]Template, error) {
+	var data []templateJSON
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("backup: templates: %w", err)
+	}
+	var templates []Template
+	for _, d := range data {
+		t := Template{
+			Name:   d.Name,
+			Size:   image.Pt(d.Size[0], d.Size[1]),
+			Offset: image.Pt(d.Offset[0], d.Offset[1]),
+			Margin: d.Margin,
+		}
+		for _, k := range d.KeepOut {
+			t.KeepOut = append(t.KeepOut, image.Rect(k[0], k[1], k[2], k[3]))
+		}
+		if err := t.validate(); err != nil {
+			return nil, fmt.Errorf("backup: template %q: %w", t.Name, err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+func (t Template) validate() error {
+	if t.Size.X <= 0 || t.Size.Y <= 0 {
+		return fmt.Errorf("invalid size %v", t.Size)
+	}
+	if t.Margin < 0 || 2*t.Margin >= t.Size.X || 2*t.Margin >= t.Size.Y {
+		return fmt.Errorf("invalid margin %d", t.Margin)
+	}
+	if t.Offset.X < 0 || t.Offset.Y < 0 {
+		return fmt.Errorf("invalid offset %v", t.Offset)
+	}
+	plate := image.Rectangle{Max: t.Size}
+	for _, k := range t.KeepOut {
+		if k.Empty() || !k.In(plate) {
+			return fmt.Errorf("keep-out region %v outside plate", k)
+		}
+	}
+	return nil
+}
+
+// edgeRegion reports whether a keep-out region touches the top or
+// bottom edge of the plate. Such regions, typically corner holes, are
+// avoided line by line; other regions narrow or shift the layout.
+func (t Template) edgeRegion(k image.Rectangle) bool {
+	return k.Min.Y <= 0 || k.Max.Y >= t.Size.Y
+}
+
+// sideMargin returns the horizontal margin that clears every keep-out
+// region not touching the top or bottom edge.
+func (t Template) sideMargin() float32 {
+	m := t.Margin
+	for _, k := range t.KeepOut {
+		if t.edgeRegion(k) {
+			continue
+		}
+		d := k.Max.X
+		if k.Min.X > t.Size.X-k.Max.X {
+			d = t.Size.X - k.Min.X
+		}
+		if d > m {
+			m = d
+		}
+	}
+	return float32(m)
+}
+
+// insets returns the distances from the left and right plate edges
+// covered by the edge keep-out regions overlapping the vertical
+// range [y0, y1).
+func (t Template) insets(y0, y1 float32) (left, right float32) {
+	for _, k := range t.KeepOut {
+		if !t.edgeRegion(k) || y1 <= float32(k.Min.Y) || float32(k.Max.Y) <= y0 {
+			continue
+		}
+		if k.Min.X <= t.Size.X-k.Max.X {
+			if d := float32(k.Max.X); d > left {
+				left = d
+			}
+		} else {
+			if d := float32(t.Size.X - k.Min.X); d > right {
+				right = d
+			}
+		}
+	}
+	return
+}
+
+// band returns the vertical center of the tallest horizontal band of
+// the plate free of keep-out regions that don't touch the top or bottom
+// edge.
+func (t Template) band() float32 {
+	best, center := -1, float32(t.Size.Y)/2
+	for y := 0; y < t.Size.Y; {
+		end := t.Size.Y
+		blocked := false
+		for _, k := range t.KeepOut {
+			if t.edgeRegion(k) {
+				continue
+			}
+			if k.Min.Y <= y && y < k.Max.Y {
+				// Skip past the region.
+				y, blocked = k.Max.Y, true
+				break
+			}
+			if k.Min.Y > y && k.Min.Y < end {
+				end = k.Min.Y
+			}
+		}
+		if blocked {
+			continue
+		}
+		if end-y > best {
+			best, center = end-y, float32(y+end)/2
+		}
+		y = end
+	}
+	return center
+}
+
+// keepOutProgram detects lines that intersect keep-out regions.
+type keepOutProgram struct {
+	KeepOut []image.Rectangle
+	Hit     bool
+	pos     f32.Vec2
+}
+
+func (k *keepOutProgram) Move(p f32.Vec2) {
+	k.pos = p
+}
+
+func (k *keepOutProgram) Line(p f32.Vec2) {
+	minp, maxp := k.pos, p
+	for i := range minp {
+		if minp[i] > maxp[i] {
+			minp[i], maxp[i] = maxp[i], minp[i]
+		}
+	}
+	k.pos = p
+	for _, r := range k.KeepOut {
+		if minp[0] < float32(r.Max.X) && float32(r.Min.X) < maxp[0] &&
+			minp[1] < float32(r.Max.Y) && float32(r.Min.Y) < maxp[1] {
+			k.Hit = true
+		}
+	}
+}
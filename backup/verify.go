@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+
+	"seedhammer.com/bc/urtypes"
+	"seedhammer.com/engrave"
+	"seedhammer.com/seedqr"
+	"seedhammer.com/zbar"
+)
+
+// ErrUnreadable is returned by Verify when the QR codes of a plate
+// can't be decoded.
+var ErrUnreadable = errors.New("backup: engraved QR codes are unreadable")
+
+// ErrUnverifiable is returned by Verify for plates with descriptor
+// symbols other than QR codes, which neither Verify nor the SeedHammer
+// scanner can decode.
+var ErrUnverifiable = errors.New("backup: only QR codes can be verified")
+
+// verifyConditions lists the resolutions in pixels per millimeter and
+// the engraved stroke widths, relative to the nominal width, that
+// Verify simulates. Strokes narrower than nominal leave gaps between
+// the lines of QR modules and are not simulated.
+var verifyConditions = []struct {
+	ppmm   float32
+	stroke float32
+}{
+	{6, 1},
+	{10, 1},
+	{8, 1.2},
+	{12, 1.3},
+}
+
+// Verify rasterizes the sides of a plate engraved from desc under
+// several conditions and decodes their QR codes. It returns an error
+// wrapping ErrUnreadable unless the decoded payloads match the SeedQR
+// and UR content of the plate. It returns ErrUnverifiable if the
+// descriptor symbols of the plate are not QR codes.
+func Verify(strokeWidth float32, desc PlateDesc, plate Plate) error {
+	if hasDescriptorSide(desc) && desc.Symbology != QRCode {
+		return ErrUnverifiable
+	}
+	want := qrPayloads(desc)
+	for _, c := range verifyConditions {
+		var got [][]byte
+		for _, s := range plate.Sides {
+			res, err := scanSide(plate.Template, s, c.ppmm, strokeWidth*c.stroke)
+			if err != nil {
+				return fmt.Errorf("backup: %w", err)
+			}
+			got = append(got, res...)
+		}
+		if !equalPayloads(got, want) {
+			return fmt.Errorf("%w: decoded %d of %d at %g pixels/mm and stroke width %.2f mm",
+				ErrUnreadable, len(got), len(want), c.ppmm, strokeWidth*c.stroke)
+		}
+	}
+	return nil
+}
+
+// qrPayloads returns the content of the QR codes on the plate engraved
+// from desc.
+func qrPayloads(desc PlateDesc) [][]byte {
+	if len(desc.SLIP39) > 0 || desc.Codex32 != "" {
+		return nil
+	}
+	payloads := [][]byte{seedqr.CompactQR(desc.Mnemonic)}
+	if hasDescriptorSide(desc) {
+		for _, ur := range splitUR(desc.Descriptor, desc.KeyIdx) {
+			payloads = append(payloads, []byte(ur))
+		}
+	}
+	return payloads
+}
+
+// hasDescriptorSide reports whether the plate engraved from desc
+// includes a descriptor side.
+func hasDescriptorSide(desc PlateDesc) bool {
+	return len(desc.SLIP39) == 0 && desc.Codex32 == "" &&
+		desc.Descriptor.Type != urtypes.UnknownScript
+}
+
+func scanSide(t Template, side engrave.Command, ppmm, strokeWidth float32) ([][]byte, error) {
+	b := t.Bounds()
+	bounds := image.Rect(
+		int(float32(b.Min.X)*ppmm), int(float32(b.Min.Y)*ppmm),
+		int(float32(b.Max.X)*ppmm), int(float32(b.Max.Y)*ppmm),
+	)
+	img := image.NewGray(bounds)
+	draw.Draw(img, bounds, image.White, image.Point{}, draw.Src)
+	r := engrave.NewRasterizer(img, bounds, strokeWidth*ppmm)
+	engrave.Scale(ppmm, ppmm, side).Engrave(r)
+	r.Rasterize()
+	return zbar.ScanImage(img)
+}
+
+func equalPayloads(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sorted := func(p [][]byte) [][]byte {
+		p = append([][]byte(nil), p...)
+		sort.Slice(p, func(i, j int) bool {
+			return bytes.Compare(p[i], p[j]) < 0
+		})
+		return p
+	}
+	a, b = sorted(a), sorted(b)
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -21,6 +21,46 @@ func Encode(_type string, message []byte, seqNum, seqLen int) string {
 	return fmt.Sprintf("ur:%s/%d-%d/%s", _type, seqNum, seqLen, bytewords.Encode(data))
 }
 
+// Encoder encodes a message as an endless sequence of multi-part
+// URs suitable for animated QR codes.
+type Encoder struct {
+	typ     string
+	message []byte
+	seqLen  int
+	seqNum  int
+}
+
+// NewEncoder returns an encoder that splits message into fragments of
+// at most maxFragmentLen bytes.
+func NewEncoder(_type string, message []byte, maxFragmentLen int) *Encoder {
+	if maxFragmentLen < 1 {
+		panic("ur: invalid maximum fragment length")
+	}
+	seqLen := (len(message) + maxFragmentLen - 1) / maxFragmentLen
+	if seqLen < 1 {
+		seqLen = 1
+	}
+	return &Encoder{
+		typ:     _type,
+		message: message,
+		seqLen:  seqLen,
+	}
+}
+
+// SeqLen returns the number of fragments of the message.
+func (e *Encoder) SeqLen() int {
+	return e.seqLen
+}
+
+// Next returns the next part of the message. The first SeqLen parts
+// each contain a single fragment, and are followed by an endless
+// sequence of parts that mix fragments. Messages that fit in a
+// single fragment are encoded as single-part URs.
+func (e *Encoder) Next() string {
+	e.seqNum++
+	return Encode(e.typ, e.message, e.seqNum, e.seqLen)
+}
+
 type Decoder struct {
 	typ  string
 	data []byte
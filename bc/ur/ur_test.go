@@ -117,3 +117,59 @@ func TestDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestEncoder(t *testing.T) {
+	msg := make([]byte, 1000)
+	for i := range msg {
+		msg[i] = byte(i * 7)
+	}
+	tests := []struct {
+		maxFragmentLen int
+		seqLen         int
+	}{
+		{2000, 1},
+		{1000, 1},
+		{999, 2},
+		{100, 10},
+		{90, 12},
+	}
+	for _, test := range tests {
+		e := NewEncoder("bytes", msg, test.maxFragmentLen)
+		if got := e.SeqLen(); got != test.seqLen {
+			t.Errorf("max fragment length %d: %d fragments, want %d", test.maxFragmentLen, got, test.seqLen)
+			continue
+		}
+		for i := 1; i <= e.SeqLen(); i++ {
+			if got, want := e.Next(), Encode("bytes", msg, i, e.SeqLen()); got != want {
+				t.Errorf("part %d is %s, want %s", i, got, want)
+			}
+		}
+		if e.SeqLen() == 1 {
+			if got, want := e.Next(), Encode("bytes", msg, 1, 1); got != want {
+				t.Errorf("repeated single part is %s, want %s", got, want)
+			}
+			continue
+		}
+		// Decode from mixed parts only.
+		var d Decoder
+		for n := 0; ; n++ {
+			if n == 100*e.SeqLen() {
+				t.Fatalf("max fragment length %d: failed to decode after %d mixed parts", test.maxFragmentLen, n)
+			}
+			if err := d.Add(e.Next()); err != nil {
+				t.Fatal(err)
+			}
+			typ, got, err := d.Result()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == nil {
+				continue
+			}
+			if typ != "bytes" || !reflect.DeepEqual(got, msg) {
+				t.Errorf("max fragment length %d: decoded %s %x, want %x", test.maxFragmentLen, typ, got, msg)
+			}
+			break
+		}
+	}
+}
@@ -141,7 +141,17 @@ func (o OutputDescriptor) DerivationPath() Path {
 //
 // [BCR-2020-010]: https://github.com/BlockchainCommons/Research/blob/master/papers/bcr-2020-010-output-desc.md
 func (o OutputDescriptor) Encode() []byte {
-	var v any
+	enc, err := encMode.Marshal(o.toCBOR(false))
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// toCBOR returns the tagged descriptor. If cosigner is set, a single
+// key is tagged as a cosigner of a multisig script.
+func (o OutputDescriptor) toCBOR(cosigner bool) cbor.Tag {
+	var v cbor.Tag
 	if len(o.Keys) > 1 {
 		m := struct {
 			Threshold int        `cbor:"1,keyasint,omitempty"`
@@ -168,6 +178,12 @@ func (o OutputDescriptor) Encode() []byte {
 			Number:  tagHDKey,
 			Content: o.Keys[0].toCBOR(),
 		}
+		if cosigner {
+			v = cbor.Tag{
+				Number:  tagCosigner,
+				Content: v,
+			}
+		}
 	}
 	var tags []uint64
 	switch o.Type {
@@ -194,7 +210,38 @@ func (o OutputDescriptor) Encode() []byte {
 			Content: v,
 		}
 	}
-	enc, err := encMode.Marshal(v)
+	return v
+}
+
+// Account is a set of output descriptors for keys derived from
+// a single master key.
+type Account struct {
+	MasterFingerprint uint32
+	Descriptors       []OutputDescriptor
+}
+
+// Encode the account in the format described by [BCR-2020-015].
+// Single key descriptors of multisig scripts are encoded as
+// cosigners.
+//
+// [BCR-2020-015]: https://github.com/BlockchainCommons/Research/blob/master/papers/bcr-2020-015-account.md
+func (a Account) Encode() []byte {
+	acc := account{
+		MasterFingerprint: a.MasterFingerprint,
+	}
+	for _, d := range a.Descriptors {
+		cosigner := false
+		switch d.Type {
+		case P2SH, P2SH_P2WSH, P2WSH:
+			cosigner = len(d.Keys) == 1
+		}
+		enc, err := encMode.Marshal(d.toCBOR(cosigner))
+		if err != nil {
+			panic(err)
+		}
+		acc.Descriptors = append(acc.Descriptors, enc)
+	}
+	enc, err := encMode.Marshal(acc)
 	if err != nil {
 		panic(err)
 	}
@@ -283,6 +330,11 @@ type seed struct {
 	Payload []byte `cbor:"1,keyasint"`
 }
 
+type account struct {
+	MasterFingerprint uint32            `cbor:"1,keyasint"`
+	Descriptors       []cbor.RawMessage `cbor:"2,keyasint"`
+}
+
 type multi struct {
 	Threshold int               `cbor:"1,keyasint"`
 	Keys      []cbor.RawMessage `cbor:"2,keyasint"`
@@ -316,6 +368,7 @@ const (
 
 	tagMulti       = 406
 	tagSortedMulti = 407
+	tagCosigner    = 410
 )
 
 var encMode cbor.EncMode
@@ -353,6 +406,8 @@ func Parse(typ string, enc []byte) (any, error) {
 		value, decErr = parseOutputDescriptor(decMode, enc)
 	case "crypto-hdkey":
 		value, decErr = parseHDKey(enc)
+	case "crypto-account":
+		value, decErr = parseAccount(enc)
 	case "bytes":
 		var content []byte
 		if err := decMode.Unmarshal(enc, &content); err != nil {
@@ -427,6 +482,24 @@ func parseHDKey(enc []byte) (KeyDescriptor, error) {
 	}, nil
 }
 
+func parseAccount(enc []byte) (Account, error) {
+	var acc account
+	if err := decMode.Unmarshal(enc, &acc); err != nil {
+		return Account{}, fmt.Errorf("ur: crypto-account decoding failed: %w", err)
+	}
+	a := Account{
+		MasterFingerprint: acc.MasterFingerprint,
+	}
+	for _, d := range acc.Descriptors {
+		desc, err := parseOutputDescriptor(decMode, d)
+		if err != nil {
+			return Account{}, err
+		}
+		a.Descriptors = append(a.Descriptors, desc)
+	}
+	return a, nil
+}
+
 func parseOutputDescriptor(mode cbor.DecMode, enc []byte) (OutputDescriptor, error) {
 	var tags []uint64
 	for {
@@ -471,11 +544,21 @@ func parseOutputDescriptor(mode cbor.DecMode, enc []byte) (OutputDescriptor, err
 	if len(tags) == 0 {
 		return OutputDescriptor{}, errors.New("ur: missing descriptor script tag")
 	}
+	cosigner := tags[0] == tagCosigner
+	if cosigner {
+		tags = tags[1:]
+		if len(tags) == 0 {
+			return OutputDescriptor{}, errors.New("ur: missing cosigner key tag")
+		}
+	}
 	funcNumber := tags[0]
 	tags = tags[1:]
 	if len(tags) > 0 {
 		return OutputDescriptor{}, errors.New("ur: extra tags")
 	}
+	if cosigner && funcNumber != tagHDKey {
+		return OutputDescriptor{}, fmt.Errorf("ur: invalid cosigner key tag: %d", funcNumber)
+	}
 	switch funcNumber {
 	case tagHDKey: // singlesig
 		k, err := parseHDKey(enc)
@@ -3,6 +3,7 @@ package urtypes
 import (
 	"encoding/hex"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
@@ -200,6 +201,59 @@ func TestOutputDescriptor(t *testing.T) {
 	}
 }
 
+func TestAccount(t *testing.T) {
+	mk, err := hdkeychain.NewMaster(make([]byte, 32), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := func(path ...uint32) KeyDescriptor {
+		k := mk
+		for _, p := range path {
+			k, err = k.Derive(hdkeychain.HardenedKeyStart + p)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		k, err = k.Neuter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var devPath Path
+		for _, p := range path {
+			devPath = append(devPath, hdkeychain.HardenedKeyStart+p)
+		}
+		return KeyDescriptor{
+			MasterFingerprint: 0x12345678,
+			DerivationPath:    devPath,
+			Key:               *k,
+		}
+	}
+	acc := Account{
+		MasterFingerprint: 0x12345678,
+		Descriptors: []OutputDescriptor{
+			{Type: P2WSH, Threshold: 1, Keys: []KeyDescriptor{key(48, 0, 0, 2)}},
+			{Type: P2SH_P2WSH, Threshold: 1, Keys: []KeyDescriptor{key(48, 0, 0, 1)}},
+			{Type: P2WPKH, Threshold: 1, Keys: []KeyDescriptor{key(84, 0, 0)}},
+			{Type: P2TR, Threshold: 1, Keys: []KeyDescriptor{key(86, 0, 0)}},
+		},
+	}
+	enc := acc.Encode()
+	// The multisig keys must be tagged as cosigners.
+	encHex := hex.EncodeToString(enc)
+	for _, prefix := range []string{"d90191d9019ad9012f", "d90190d90191d9019ad9012f", "d90194d9012f", "d90199d9012f"} {
+		if !strings.Contains(encHex, prefix) {
+			t.Errorf("encoding %s doesn't contain %s", encHex, prefix)
+		}
+	}
+	got, err := Parse("crypto-account", enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, acc) {
+		t.Errorf("account decoded to\n%#v\nwanted\n%#v", got, acc)
+	}
+}
+
 func TestBytes(t *testing.T) {
 	tests := []struct {
 		enc  string
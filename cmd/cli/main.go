@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"image"
 	"image/png"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
@@ -20,6 +22,7 @@ import (
 	"seedhammer.com/bip39"
 	"seedhammer.com/engrave"
 	"seedhammer.com/font/sh"
+	"seedhammer.com/grbl"
 	"seedhammer.com/mjolnir"
 )
 
@@ -27,10 +30,18 @@ var (
 	serialDev = flag.String("device", "", "serial device")
 	dryrun    = flag.Bool("n", false, "dry run")
 	output    = flag.String("o", "plates", "output plates to directory")
+	format    = flag.String("format", "png", "output format: png, svg, dxf, gcode or job")
+	feed      = flag.Float64("feed", 300, "G-code engraving feed rate in mm/min")
+	depth     = flag.Float64("depth", 0.1, "G-code engraving depth in mm")
+	useGRBL   = flag.Bool("grbl", false, "engrave with a GRBL machine")
+	dotPitch  = flag.Float64("dots", 0, "engrave dots spaced at most this many mm apart instead of lines")
 	side      = flag.String("side", "front", "plate side, front or back")
 	shares    = flag.Int("shares", 3, "number of shares in total")
 	seedonly  = flag.Bool("seedonly", false, "seed-only mode")
 	mnemonic  = flag.String("mnemonic", "flip begin artist fringe online release swift genre wool general transfer arm", "mnemonic")
+	templates = flag.String("templates", "", "load plate templates from JSON file")
+	material  = flag.String("material", mjolnir.Stainless304.Name, "plate material profile")
+	traceFile = flag.String("trace", "", "record the serial stream of the engraver to file")
 )
 
 func main() {
@@ -48,6 +59,29 @@ func main() {
 		os.Exit(1)
 	}
 	plateDesc := genPlate(m)
+	plateDesc.DotPitch = float32(*dotPitch)
+	if *templates != "" {
+		plateDesc.Templates, err = loadTemplates(*templates)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	switch *format {
+	case "png", "svg", "dxf", "gcode", "job":
+	default:
+		fmt.Fprintf(os.Stderr, "-format must be one of png, svg, dxf, gcode, job\n")
+		os.Exit(1)
+	}
+	prof, ok := mjolnir.LookupProfile(*material)
+	if !ok {
+		var names []string
+		for _, p := range mjolnir.Profiles {
+			names = append(names, fmt.Sprintf("%q", p.Name))
+		}
+		fmt.Fprintf(os.Stderr, "-material must be one of %s\n", strings.Join(names, ", "))
+		os.Exit(1)
+	}
 	if *serialDev != "" {
 		var s int
 		switch *side {
@@ -59,13 +93,13 @@ func main() {
 			fmt.Fprintf(os.Stderr, "-side must be 'front' or 'back'\n")
 			os.Exit(1)
 		}
-		err = hammer(plateDesc, s, *serialDev)
+		err = hammer(plateDesc, prof, s, *serialDev)
 	} else {
 		if err := os.MkdirAll(*output, 0o755); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			os.Exit(1)
 		}
-		err = dump(plateDesc, *output)
+		err = dump(plateDesc, prof, *output, *format)
 	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -73,8 +107,16 @@ func main() {
 	}
 }
 
-func dump(plateDesc backup.PlateDesc, output string) error {
-	const ppmm = 10
+func loadTemplates(name string) ([]backup.Template, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return backup.LoadTemplates(f)
+}
+
+func dump(plateDesc backup.PlateDesc, prof mjolnir.Profile, output, format string) error {
 	for i := range plateDesc.Descriptor.Keys {
 		desc := plateDesc
 		desc.KeyIdx = i
@@ -82,22 +124,16 @@ func dump(plateDesc backup.PlateDesc, output string) error {
 		if err != nil {
 			return err
 		}
-		bounds := plate.Size.Bounds()
-		bounds = image.Rectangle{
-			Min: bounds.Min.Mul(ppmm),
-			Max: bounds.Max.Mul(ppmm),
-		}
-		for s := range plate.Sides {
-			img := image.NewNRGBA(bounds)
-			r := engrave.NewRasterizer(img, img.Bounds(), mjolnir.StrokeWidth*ppmm)
-			se := engrave.Scale(ppmm, ppmm, plate.Sides[s])
-			se.Engrave(r)
-			r.Rasterize()
+		for s, side := range plate.Sides {
 			buf := new(bytes.Buffer)
-			if err := png.Encode(buf, img); err != nil {
+			if err := export(buf, plate.Template, prof, side, format); err != nil {
 				return err
 			}
-			file := filepath.Join(output, fmt.Sprintf("plate-%d-side-%d.png", i, s))
+			ext := format
+			if ext == "gcode" {
+				ext = "nc"
+			}
+			file := filepath.Join(output, fmt.Sprintf("plate-%d-side-%d.%s", i, s, ext))
 			if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
 				return err
 			}
@@ -106,6 +142,65 @@ func dump(plateDesc backup.PlateDesc, output string) error {
 	return nil
 }
 
+// export writes a plate side in an output format. Vector formats
+// use coordinates relative to the top left corner of the plate. Jobs
+// use engraver coordinates and record the material profile, the plate
+// area and the dry run setting.
+func export(w io.Writer, tmpl backup.Template, prof mjolnir.Profile, side engrave.Command, format string) error {
+	switch format {
+	case "png":
+		const ppmm = 10
+		bounds := tmpl.Bounds()
+		bounds = image.Rectangle{
+			Min: bounds.Min.Mul(ppmm),
+			Max: bounds.Max.Mul(ppmm),
+		}
+		img := image.NewNRGBA(bounds)
+		r := engrave.NewRasterizer(img, img.Bounds(), mjolnir.StrokeWidth*ppmm)
+		se := engrave.Scale(ppmm, ppmm, side)
+		se.Engrave(r)
+		r.Rasterize()
+		return png.Encode(w, img)
+	case "job":
+		job := engrave.Record(side)
+		job.Material = prof.Name
+		job.MoveSpeed, job.PrintSpeed = prof.MoveSpeed, prof.PrintSpeed
+		job.PenDown, job.PenUp = prof.PenDown, prof.PenUp
+		job.Passes = prof.Passes
+		job.DryRun = *dryrun
+		job.Plate = tmpl.Bounds()
+		data, err := job.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	bounds := image.Rectangle{Max: tmpl.Size}
+	side = engrave.Offset(-float32(tmpl.Offset.X), -float32(tmpl.Offset.Y), side)
+	var prog interface {
+		engrave.Program
+		Close() error
+	}
+	switch format {
+	case "svg":
+		prog = engrave.NewSVG(w, bounds, mjolnir.StrokeWidth)
+	case "dxf":
+		prog = engrave.NewDXF(w, bounds)
+	case "gcode":
+		prog = engrave.NewGCode(w, bounds, engrave.GCodeConfig{
+			Feed:       float32(*feed),
+			PlungeFeed: float32(*feed) / 3,
+			Depth:      float32(*depth),
+			SafeZ:      2,
+		})
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	side.Engrave(prog)
+	return prog.Close()
+}
+
 func genPlate(m0 bip39.Mnemonic) backup.PlateDesc {
 	var threshold int
 	switch *shares {
@@ -168,7 +263,20 @@ func genPlate(m0 bip39.Mnemonic) backup.PlateDesc {
 	return plate
 }
 
-func hammer(plateDesc backup.PlateDesc, side int, dev string) error {
+// logEvents writes an engraving event to w for every batch and
+// whenever the engraving changes stage.
+func logEvents(w io.Writer, events <-chan mjolnir.Event) {
+	last := mjolnir.Stage(-1)
+	for e := range events {
+		if e.Stage == last && e.Stage != mjolnir.Buffering {
+			continue
+		}
+		last = e.Stage
+		fmt.Fprintf(w, "engrave: %v\n", e)
+	}
+}
+
+func hammer(plateDesc backup.PlateDesc, prof mjolnir.Profile, side int, dev string) error {
 	plate, err := backup.Engrave(mjolnir.StrokeWidth, plateDesc)
 	if err != nil {
 		return err
@@ -176,16 +284,67 @@ func hammer(plateDesc backup.PlateDesc, side int, dev string) error {
 	if side >= len(plate.Sides) {
 		return fmt.Errorf("no such side: %d", side)
 	}
-	s, err := mjolnir.Open(dev)
+	var prog interface {
+		engrave.Program
+		Prepare()
+	}
+	var run func(s io.ReadWriter, cancel <-chan struct{}) error
+	open := mjolnir.Open
+	if *useGRBL {
+		p := &grbl.Program{
+			DryRun: *dryrun,
+			Feed:   float32(*feed),
+			Depth:  float32(*depth),
+		}
+		prog = p
+		run = func(s io.ReadWriter, cancel <-chan struct{}) error {
+			return grbl.Engrave(s, p, nil, nil, cancel)
+		}
+		open = grbl.Open
+	} else {
+		p := &mjolnir.Program{
+			DryRun:  *dryrun,
+			Profile: prof,
+		}
+		prog = p
+		run = func(s io.ReadWriter, cancel <-chan struct{}) (err error) {
+			if *traceFile != "" {
+				f, err := os.Create(*traceFile)
+				if err != nil {
+					return err
+				}
+				tr := mjolnir.NewTracer(s, f)
+				defer func() {
+					if terr := tr.Err(); err == nil {
+						err = terr
+					}
+					if cerr := f.Close(); err == nil {
+						err = cerr
+					}
+				}()
+				s = tr
+			}
+			events := make(chan mjolnir.Event, 16)
+			logged := make(chan struct{})
+			go func() {
+				defer close(logged)
+				logEvents(os.Stderr, events)
+			}()
+			err = mjolnir.Engrave(s, p, events, nil, cancel)
+			close(events)
+			<-logged
+			return err
+		}
+	}
+	s, err := open(dev)
 	if err != nil {
 		return err
 	}
 	defer s.Close()
 
-	prog := &mjolnir.Program{
-		DryRun: *dryrun,
-	}
-	plate.Sides[side].Engrave(prog)
+	// Record the side to avoid generating it twice.
+	job := engrave.Record(plate.Sides[side])
+	job.Engrave(prog)
 	prog.Prepare()
 	quit := make(chan os.Signal, 1)
 	cancel := make(chan struct{})
@@ -199,8 +358,8 @@ func hammer(plateDesc backup.PlateDesc, side int, dev string) error {
 		os.Exit(1)
 	}()
 	go func() {
-		engraveErr <- mjolnir.Engrave(s, prog, nil, cancel)
+		engraveErr <- run(s, cancel)
 	}()
-	plate.Sides[side].Engrave(prog)
+	job.Engrave(prog)
 	return <-engraveErr
 }
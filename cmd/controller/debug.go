@@ -3,19 +3,28 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"runtime/pprof"
 	"strings"
+	"sync"
+	"time"
 
+	"seedhammer.com/grbl"
+	"seedhammer.com/gui"
 	"seedhammer.com/input"
 	"seedhammer.com/mjolnir"
 )
 
 const Debug = true
 
-type Platform struct{}
+type Platform struct {
+	// grbl selects the GRBL simulator instead of mjolnir.
+	grbl bool
+}
 
 var inputCh chan<- input.Event
 
@@ -24,12 +33,64 @@ func (p *Platform) Input(ch chan<- input.Event) error {
 	return input.Open(ch)
 }
 
-func (p *Platform) Engraver() (io.ReadWriteCloser, error) {
-	return mjolnir.NewSimulator(), nil
+func (p *Platform) Engraver() (gui.Engraver, error) {
+	if p.grbl {
+		return gui.NewGRBLEngraver(grbl.NewSimulator()), nil
+	}
+	return gui.NewMjolnirEngraver(p.trace(mjolnir.NewSimulator())), nil
+}
+
+// trace records the serial stream of dev, and dumps it to the microSD
+// card when dev is closed, for decoding by the trace command. Only
+// debug builds record traces, because the stream reveals the engraved
+// seed.
+func (p *Platform) trace(dev io.ReadWriteCloser) io.ReadWriteCloser {
+	buf := new(lockedBuffer)
+	return &tracedDevice{
+		Tracer:   mjolnir.NewTracer(dev, buf),
+		dev:      dev,
+		buf:      buf,
+		platform: p,
+	}
+}
+
+type tracedDevice struct {
+	*mjolnir.Tracer
+	dev      io.Closer
+	buf      *lockedBuffer
+	platform *Platform
+}
+
+func (t *tracedDevice) Close() error {
+	err := t.dev.Close()
+	path := fmt.Sprintf("/sdcard/trace-%s.bin", time.Now().Format("20060102-150405"))
+	if derr := t.platform.Dump(path, bytes.NewReader(t.buf.Bytes())); derr != nil {
+		log.Printf("debug: dump trace: %v", derr)
+	}
+	return err
+}
+
+// lockedBuffer is a buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// Bytes returns a copy of the buffer contents.
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
 }
 
-func newPlatform() *Platform {
-	return new(Platform)
+func newPlatform(engraver string) *Platform {
+	return &Platform{grbl: engraver == "grbl"}
 }
 
 func debugCommand(cmd string) error {
@@ -25,7 +25,11 @@ func run() error {
 	if err := Init(); err != nil {
 		return err
 	}
-	ver, err := readVersion()
+	ver, err := readCmdline("sh_version")
+	if err != nil {
+		return err
+	}
+	engraver, err := readCmdline("sh_engraver")
 	if err != nil {
 		return err
 	}
@@ -34,22 +38,22 @@ func run() error {
 		return err
 	}
 	defer lcd.Close()
-	a := gui.NewApp(newPlatform(), lcd, ver)
+	a := gui.NewApp(newPlatform(engraver), lcd, ver)
 	a.Debug = Debug
 	for {
 		a.Frame()
 	}
 }
 
-// readVersion reads the version from the kernel command line.
-func readVersion() (string, error) {
+// readCmdline reads a parameter from the kernel command line.
+func readCmdline(key string) (string, error) {
 	cmdline, err := os.ReadFile("/proc/cmdline")
 	if err != nil {
 		return "", err
 	}
 	for _, kv := range strings.Split(string(cmdline), " ") {
 		k, v, ok := strings.Cut(kv, "=")
-		if ok && k == "sh_version" {
+		if ok && k == key {
 			return v, nil
 		}
 	}
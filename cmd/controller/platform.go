@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"seedhammer.com/camera"
+	"seedhammer.com/gui"
 )
 
 var sdcard = make(chan bool, 1)
@@ -13,6 +14,13 @@ func (p *Platform) Camera(dims image.Point, frames chan camera.Frame, out <-chan
 	return camera.Open(dims, frames, out)
 }
 
+func (p *Platform) EngraverModel() gui.EngraverModel {
+	if p.grbl {
+		return gui.GRBLModel
+	}
+	return gui.MjolnirModel
+}
+
 func (p *Platform) Now() time.Time {
 	return time.Now()
 }
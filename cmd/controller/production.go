@@ -6,6 +6,8 @@ import (
 	"errors"
 	"io"
 
+	"seedhammer.com/grbl"
+	"seedhammer.com/gui"
 	"seedhammer.com/input"
 	"seedhammer.com/mjolnir"
 )
@@ -16,20 +18,34 @@ func dbgInit() error {
 	return nil
 }
 
-type Platform struct{}
+type Platform struct {
+	// grbl selects the GRBL driver instead of mjolnir.
+	grbl bool
+}
 
 func (p *Platform) Input(ch chan<- input.Event) error {
 	return input.Open(ch)
 }
 
-func (p *Platform) Engraver() (io.ReadWriteCloser, error) {
-	return mjolnir.Open("")
+func (p *Platform) Engraver() (gui.Engraver, error) {
+	if p.grbl {
+		dev, err := grbl.Open("")
+		if err != nil {
+			return nil, err
+		}
+		return gui.NewGRBLEngraver(dev), nil
+	}
+	dev, err := mjolnir.Open("")
+	if err != nil {
+		return nil, err
+	}
+	return gui.NewMjolnirEngraver(dev), nil
 }
 
 func (p *Platform) Dump(path string, r io.Reader) error {
 	return errors.New("not available in production")
 }
 
-func newPlatform() *Platform {
-	return new(Platform)
+func newPlatform(engraver string) *Platform {
+	return &Platform{grbl: engraver == "grbl"}
 }
@@ -0,0 +1,261 @@
+// command job inspects, compares and replays recorded engraving jobs,
+// such as those written by the cli tool with -format job.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"seedhammer.com/engrave"
+	"seedhammer.com/grbl"
+	"seedhammer.com/mjolnir"
+)
+
+var (
+	serialDev = flag.String("device", "", "serial device")
+	dryrun    = flag.Bool("n", false, "dry run, even if the job engraves")
+	useGRBL   = flag.Bool("grbl", false, "replay to a GRBL machine")
+	material  = flag.String("material", "", "override the material profile of the job")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: job [flags] inspect FILE...\n")
+	fmt.Fprintf(os.Stderr, "       job compare FILE1 FILE2\n")
+	fmt.Fprintf(os.Stderr, "       job [flags] replay FILE\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 2 {
+		usage()
+	}
+	var err error
+	switch cmd, files := args[0], args[1:]; cmd {
+	case "inspect":
+		for _, f := range files {
+			if err = inspect(os.Stdout, f); err != nil {
+				break
+			}
+		}
+	case "compare":
+		if len(files) != 2 {
+			usage()
+		}
+		var equal bool
+		equal, err = compare(os.Stdout, files[0], files[1])
+		if err == nil && !equal {
+			os.Exit(1)
+		}
+	case "replay":
+		if len(files) != 1 {
+			usage()
+		}
+		err = replay(files[0], *serialDev)
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "job: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func readJob(name string) (*engrave.Job, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	job := new(engrave.Job)
+	if err := job.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return job, nil
+}
+
+func inspect(w io.Writer, name string) error {
+	job, err := readJob(name)
+	if err != nil {
+		return err
+	}
+	var min, max [2]float32
+	for i, s := range job.Steps {
+		for j, v := range s.P {
+			if i == 0 || v < min[j] {
+				min[j] = v
+			}
+			if i == 0 || v > max[j] {
+				max[j] = v
+			}
+		}
+	}
+	stats := engrave.Measure(job)
+	prog := &mjolnir.Program{
+		DryRun:  job.DryRun,
+		Profile: recordedProfile(job),
+	}
+	fmt.Fprintf(w, "%s:\n", name)
+	fmt.Fprintf(w, "\thash:     %x\n", job.Hash())
+	fmt.Fprintf(w, "\tsteps:    %d\n", len(job.Steps))
+	fmt.Fprintf(w, "\tmaterial: %s, %d passes\n", job.Material, job.Passes)
+	fmt.Fprintf(w, "\tspeeds:   move %g, print %g\n", job.MoveSpeed, job.PrintSpeed)
+	fmt.Fprintf(w, "\tdelays:   pen down %d ms, pen up %d ms\n", job.PenDown, job.PenUp)
+	fmt.Fprintf(w, "\tdry run:  %v\n", job.DryRun)
+	fmt.Fprintf(w, "\tplate:    %v\n", job.Plate)
+	fmt.Fprintf(w, "\tend:      (%g,%g)\n", job.End[0], job.End[1])
+	fmt.Fprintf(w, "\tbounds:   (%g,%g)-(%g,%g)\n", min[0], min[1], max[0], max[1])
+	fmt.Fprintf(w, "\tlines:    %d in %d strokes, %.0f mm\n", stats.Lines, stats.Strokes, stats.LineLength)
+	fmt.Fprintf(w, "\tmoves:    %.0f mm\n", stats.MoveLength)
+	fmt.Fprintf(w, "\testimate: %v\n", prog.Estimate(stats))
+	return nil
+}
+
+// compare reports whether two jobs are identical, and describes
+// their first difference otherwise.
+func compare(w io.Writer, name1, name2 string) (bool, error) {
+	j1, err := readJob(name1)
+	if err != nil {
+		return false, err
+	}
+	j2, err := readJob(name2)
+	if err != nil {
+		return false, err
+	}
+	if j1.Hash() == j2.Hash() {
+		return true, nil
+	}
+	var diffs []string
+	if p1, p2 := recordedProfile(j1), recordedProfile(j2); p1 != p2 {
+		diffs = append(diffs, fmt.Sprintf("profiles differ: %+v != %+v", p1, p2))
+	}
+	if j1.DryRun != j2.DryRun {
+		diffs = append(diffs, fmt.Sprintf("dry runs differ: %v != %v", j1.DryRun, j2.DryRun))
+	}
+	if j1.Plate != j2.Plate {
+		diffs = append(diffs, fmt.Sprintf("plates differ: %v != %v", j1.Plate, j2.Plate))
+	}
+	if j1.MoveSpeed != j2.MoveSpeed || j1.PrintSpeed != j2.PrintSpeed {
+		diffs = append(diffs, fmt.Sprintf("speeds differ: move %g, print %g != move %g, print %g",
+			j1.MoveSpeed, j1.PrintSpeed, j2.MoveSpeed, j2.PrintSpeed))
+	}
+	if j1.End != j2.End {
+		diffs = append(diffs, fmt.Sprintf("end positions differ: %v != %v", j1.End, j2.End))
+	}
+	for i := 0; i < len(j1.Steps) || i < len(j2.Steps); i++ {
+		if i >= len(j1.Steps) || i >= len(j2.Steps) {
+			diffs = append(diffs, fmt.Sprintf("step counts differ: %d != %d", len(j1.Steps), len(j2.Steps)))
+			break
+		}
+		if s1, s2 := j1.Steps[i], j2.Steps[i]; s1 != s2 {
+			diffs = append(diffs, fmt.Sprintf("step %d differs: %v != %v", i, s1, s2))
+			break
+		}
+	}
+	fmt.Fprintf(w, "%s and %s differ:\n\t%s\n", name1, name2, strings.Join(diffs, "\n\t"))
+	return false, nil
+}
+
+// recordedProfile returns the material profile recorded in job.
+func recordedProfile(job *engrave.Job) mjolnir.Profile {
+	return mjolnir.Profile{
+		Name:       job.Material,
+		MoveSpeed:  job.MoveSpeed,
+		PrintSpeed: job.PrintSpeed,
+		PenDown:    job.PenDown,
+		PenUp:      job.PenUp,
+		Passes:     job.Passes,
+	}
+}
+
+// checkPlate returns an error if a step of job lies outside its plate.
+func checkPlate(job *engrave.Job) error {
+	if job.Plate.Empty() {
+		return nil
+	}
+	min, max := job.Plate.Min, job.Plate.Max
+	for i, s := range job.Steps {
+		if s.P[0] < float32(min.X) || s.P[0] > float32(max.X) ||
+			s.P[1] < float32(min.Y) || s.P[1] > float32(max.Y) {
+			return fmt.Errorf("step %d at (%g,%g) is outside the plate %v", i, s.P[0], s.P[1], job.Plate)
+		}
+	}
+	return nil
+}
+
+func replay(name, dev string) error {
+	job, err := readJob(name)
+	if err != nil {
+		return err
+	}
+	if err := checkPlate(job); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	prof := recordedProfile(job)
+	if *material != "" {
+		p, ok := mjolnir.LookupProfile(*material)
+		if !ok {
+			return fmt.Errorf("unknown material: %s", *material)
+		}
+		prof = p
+	}
+	dryRun := job.DryRun || *dryrun
+	var prog interface {
+		engrave.Program
+		Prepare()
+	}
+	var run func(s io.ReadWriter, cancel <-chan struct{}) error
+	open := mjolnir.Open
+	if *useGRBL {
+		p := &grbl.Program{
+			DryRun: dryRun,
+			End:    job.End,
+		}
+		prog = p
+		run = func(s io.ReadWriter, cancel <-chan struct{}) error {
+			return grbl.Engrave(s, p, nil, nil, cancel)
+		}
+		open = grbl.Open
+	} else {
+		p := &mjolnir.Program{
+			DryRun:  dryRun,
+			Profile: prof,
+			End:     job.End,
+		}
+		prog = p
+		run = func(s io.ReadWriter, cancel <-chan struct{}) error {
+			return mjolnir.Engrave(s, p, nil, nil, cancel)
+		}
+	}
+	s, err := open(dev)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	job.Engrave(prog)
+	prog.Prepare()
+	quit := make(chan os.Signal, 1)
+	cancel := make(chan struct{})
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	engraveErr := make(chan error)
+	go func() {
+		<-quit
+		signal.Reset(os.Interrupt)
+		close(cancel)
+		<-engraveErr
+		os.Exit(1)
+	}()
+	go func() {
+		engraveErr <- run(s, cancel)
+	}()
+	job.Engrave(prog)
+	return <-engraveErr
+}
@@ -86,7 +86,7 @@ func engrave(dev string, coords []f32.Vec2) error {
 		os.Exit(1)
 	}()
 	go func() {
-		engraveErr <- mjolnir.Engrave(s, prog, nil, cancel)
+		engraveErr <- mjolnir.Engrave(s, prog, nil, nil, cancel)
 	}()
 	design()
 	return <-engraveErr
@@ -0,0 +1,73 @@
+// command trace decodes serial traces of MarkgWay engravers, such as
+// those recorded by the cli tool with -trace, and reports protocol
+// violations.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"seedhammer.com/mjolnir"
+)
+
+var raw = flag.Bool("raw", false, "dump the recorded reads and writes instead of decoding them")
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: trace [flags] FILE\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+	}
+	violations, err := run(os.Stdout, flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace: %v\n", err)
+		os.Exit(1)
+	}
+	if violations > 0 {
+		fmt.Fprintf(os.Stderr, "trace: %d protocol violations\n", violations)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, name string) (int, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	t, err := mjolnir.ReadTrace(f)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	fmt.Fprintf(w, "trace started %s\n", t.Start.Format("2006-01-02 15:04:05.000"))
+	if *raw {
+		for _, r := range t.Records {
+			dir := "<"
+			if r.Op == mjolnir.TraceWrite {
+				dir = ">"
+			}
+			fmt.Fprintf(w, "%10.3fs %s % x", r.Time.Seconds(), dir, r.Data)
+			if r.Err != "" {
+				fmt.Fprintf(w, " (error: %s)", r.Err)
+			}
+			fmt.Fprintln(w)
+		}
+		return 0, nil
+	}
+	violations := 0
+	for _, e := range t.Decode() {
+		if e.Violation != "" {
+			violations++
+		}
+		fmt.Fprintln(w, e)
+	}
+	return violations, nil
+}
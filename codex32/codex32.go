@@ -0,0 +1,356 @@
+// Package codex32 implements the [BIP-93] codex32 encoding and
+// secret sharing of BIP-32 master seeds.
+//
+// Only short codex32 strings are supported, which limits master
+// seeds to at most 46 bytes.
+//
+// [BIP-93]: https://github.com/bitcoin/bips/blob/master/bip-0093.mediawiki
+package codex32
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Alphabet is the bech32 character set, ordered by value.
+const Alphabet = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	hrp = "ms"
+	// headerLen is the number of characters in the threshold,
+	// identifier and share index.
+	headerLen   = 6
+	checksumLen = 13
+	// maxDataLen is the longest data part of a short codex32 string.
+	maxDataLen = 93
+	// secretIndex is the share index of the secret.
+	secretIndex = 's'
+	minSeedLen  = 16
+	maxSeedLen  = (maxDataLen - headerLen - checksumLen) * 5 / 8
+	// shareIndices lists the indices assigned to shares, in order.
+	shareIndices = "acdefghjklmnpqrtuvwxyz023456789"
+)
+
+var (
+	ErrChecksum   = errors.New("codex32: invalid checksum")
+	ErrShareCount = errors.New("codex32: insufficient number of shares")
+)
+
+// Share is a decoded codex32 string.
+type Share struct {
+	// Threshold is the number of shares required to recover the secret,
+	// or 0 for an unshared secret.
+	Threshold int
+	ID        string
+	Index     byte
+	// data is the data part, without the checksum.
+	data []byte
+}
+
+// New encodes seed as an unshared codex32 secret with the
+// identifier id.
+func New(id string, seed []byte) (Share, error) {
+	return newShare(0, id, secretIndex, seed)
+}
+
+func newShare(threshold int, id string, index byte, payload []byte) (Share, error) {
+	if len(payload) < minSeedLen || len(payload) > maxSeedLen {
+		return Share{}, fmt.Errorf("codex32: seed length %d out of range", len(payload))
+	}
+	id = strings.ToLower(id)
+	if len(id) != 4 {
+		return Share{}, fmt.Errorf("codex32: identifier %q is not 4 characters", id)
+	}
+	header := fmt.Sprintf("%d%s%c", threshold, id, index)
+	var data []byte
+	for i := 0; i < len(header); i++ {
+		v := strings.IndexByte(Alphabet, header[i])
+		if v == -1 {
+			return Share{}, fmt.Errorf("codex32: invalid character %q", header[i])
+		}
+		data = append(data, byte(v))
+	}
+	data = append(data, toBase32(payload)...)
+	return Share{Threshold: threshold, ID: id, Index: index, data: data}, nil
+}
+
+// Split encodes seed as n shares, of which k are required to
+// recover it. The random shares are read from rng.
+func Split(rng io.Reader, id string, seed []byte, k, n int) ([]Share, error) {
+	if k < 2 || k > 9 || n < k || n > len(shareIndices) {
+		return nil, fmt.Errorf("codex32: invalid threshold %d of %d", k, n)
+	}
+	secret, err := newShare(k, id, secretIndex, seed)
+	if err != nil {
+		return nil, err
+	}
+	// The secret and k-1 random shares determine the remaining shares.
+	base := []Share{secret}
+	for i := 0; i < k-1; i++ {
+		payload := make([]byte, len(seed))
+		if _, err := io.ReadFull(rng, payload); err != nil {
+			return nil, err
+		}
+		s, err := newShare(k, id, shareIndices[i], payload)
+		if err != nil {
+			return nil, err
+		}
+		base = append(base, s)
+	}
+	shares := append([]Share(nil), base[1:]...)
+	for i := k - 1; i < n; i++ {
+		shares = append(shares, interpolate(base, shareIndices[i]))
+	}
+	return shares, nil
+}
+
+// Combine recovers the secret from k shares.
+func Combine(shares []Share) (Share, error) {
+	if len(shares) == 0 {
+		return Share{}, ErrShareCount
+	}
+	first := shares[0]
+	if first.Threshold == 0 {
+		return first, nil
+	}
+	for _, s := range shares {
+		if s.Index == secretIndex {
+			return s, nil
+		}
+	}
+	if len(shares) < first.Threshold {
+		return Share{}, ErrShareCount
+	}
+	shares = shares[:first.Threshold]
+	seen := make(map[byte]bool)
+	for _, s := range shares {
+		if s.Threshold != first.Threshold || s.ID != first.ID || len(s.data) != len(first.data) {
+			return Share{}, errors.New("codex32: shares belong to different secrets")
+		}
+		if seen[s.Index] {
+			return Share{}, fmt.Errorf("codex32: duplicate share index %q", s.Index)
+		}
+		seen[s.Index] = true
+	}
+	return interpolate(shares, secretIndex), nil
+}
+
+// Parse decodes and verifies a codex32 string.
+func Parse(s string) (Share, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return Share{}, errors.New("codex32: mixed case")
+	}
+	s = strings.ToLower(s)
+	prefix := hrp + "1"
+	if !strings.HasPrefix(s, prefix) {
+		return Share{}, errors.New("codex32: missing ms1 prefix")
+	}
+	s = s[len(prefix):]
+	if len(s) > maxDataLen {
+		return Share{}, errors.New("codex32: long strings are not supported")
+	}
+	data := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(Alphabet, s[i])
+		if v == -1 {
+			return Share{}, fmt.Errorf("codex32: invalid character %q", s[i])
+		}
+		data[i] = byte(v)
+	}
+	payloadLen := len(data) - headerLen - checksumLen
+	if payloadLen*5/8 < minSeedLen || payloadLen*5%8 > 4 {
+		return Share{}, fmt.Errorf("codex32: invalid length %d", len(s))
+	}
+	if polymod(data) != residue {
+		return Share{}, ErrChecksum
+	}
+	threshold := s[0]
+	if threshold != '0' && (threshold < '2' || threshold > '9') {
+		return Share{}, fmt.Errorf("codex32: invalid threshold %q", threshold)
+	}
+	sh := Share{
+		Threshold: int(threshold - '0'),
+		ID:        s[1:5],
+		Index:     s[5],
+		data:      data[:len(data)-checksumLen],
+	}
+	if sh.Threshold == 0 && sh.Index != secretIndex {
+		return Share{}, errors.New("codex32: unshared secret must have index s")
+	}
+	return sh, nil
+}
+
+// Seed returns the master seed of a secret share.
+func (s Share) Seed() ([]byte, error) {
+	if s.Index != secretIndex {
+		return nil, errors.New("codex32: not a secret share")
+	}
+	return fromBase32(s.data[headerLen:]), nil
+}
+
+// String returns the upper case codex32 string of the share.
+func (s Share) String() string {
+	var b strings.Builder
+	b.WriteString(hrp + "1")
+	for _, v := range s.data {
+		b.WriteByte(Alphabet[v])
+	}
+	for _, v := range checksum(s.data) {
+		b.WriteByte(Alphabet[v])
+	}
+	return strings.ToUpper(b.String())
+}
+
+// interpolate evaluates the Lagrange polynomial through the
+// shares at the share index x.
+func interpolate(shares []Share, x byte) Share {
+	for _, s := range shares {
+		// The basis is undefined at the share indices.
+		if s.Index == x {
+			return Share{Threshold: s.Threshold, ID: s.ID, Index: x, data: append([]byte(nil), s.data...)}
+		}
+	}
+	xv := byte(strings.IndexByte(Alphabet, x))
+	var idx []byte
+	for _, s := range shares {
+		idx = append(idx, byte(strings.IndexByte(Alphabet, s.Index)))
+	}
+	// Compute the Lagrange basis at x.
+	weights := make([]byte, len(shares))
+	n := byte(1)
+	for i, xi := range idx {
+		n = gfMul(n, xi^xv)
+		m := byte(1)
+		for _, xj := range idx {
+			if xi == xj {
+				m = gfMul(m, xv^xj)
+			} else {
+				m = gfMul(m, xi^xj)
+			}
+		}
+		weights[i] = m
+	}
+	for i, m := range weights {
+		weights[i] = gfMul(n, gfInv(m))
+	}
+	first := shares[0]
+	res := Share{Threshold: first.Threshold, ID: first.ID, Index: x, data: make([]byte, len(first.data))}
+	for i := range res.data {
+		var v byte
+		for j, s := range shares {
+			v ^= gfMul(weights[j], s.data[i])
+		}
+		res.data[i] = v
+	}
+	return res
+}
+
+// gfMul multiplies in GF(32) with the bech32 polynomial
+// x^5 + x^3 + 1.
+func gfMul(a, b byte) byte {
+	var res byte
+	for i := 0; i < 5; i++ {
+		if b>>i&1 == 1 {
+			res ^= a
+		}
+		a <<= 1
+		if a&32 != 0 {
+			a ^= 41
+		}
+	}
+	return res
+}
+
+func gfInv(a byte) byte {
+	// a^30 = a^-1 in the multiplicative group of order 31.
+	res := byte(1)
+	for i := 0; i < 30; i++ {
+		res = gfMul(res, a)
+	}
+	return res
+}
+
+func toBase32(data []byte) []byte {
+	var res []byte
+	acc, bits := 0, 0
+	for _, b := range data {
+		acc = acc<<8 | int(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			res = append(res, byte(acc>>bits&31))
+		}
+		acc &= 1<<bits - 1
+	}
+	if bits > 0 {
+		res = append(res, byte(acc<<(5-bits)&31))
+	}
+	return res
+}
+
+// fromBase32 converts 5-bit values to bytes. An incomplete group
+// at the end is discarded.
+func fromBase32(data []byte) []byte {
+	var res []byte
+	acc, bits := 0, 0
+	for _, v := range data {
+		acc = acc<<5 | int(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			res = append(res, byte(acc>>bits))
+		}
+		acc &= 1<<bits - 1
+	}
+	return res
+}
+
+// residue is the expected polymod of a valid data part,
+// 0x10ce0795c2fd1e62a.
+var residue = uint128{0x1, 0x0ce0795c2fd1e62a}
+
+var generator = [...]uint128{
+	{0x1, 0x9dc500ce73fde210},
+	{0x1, 0xbfae00def77fe529},
+	{0x1, 0xfbd920fffe7bee52},
+	{0x1, 0x739640bdeee3fdad},
+	{0x0, 0x7729a039cfc75f5a},
+}
+
+// uint128 holds the 65-bit BCH residue.
+type uint128 struct {
+	hi, lo uint64
+}
+
+func polymod(data []byte) uint128 {
+	r := uint128{0, 0x23181b3}
+	for _, v := range data {
+		b := r.hi<<4 | r.lo>>60
+		r = uint128{
+			hi: r.lo >> 59 & 1,
+			lo: (r.lo&0x0fffffffffffffff)<<5 | uint64(v),
+		}
+		for i, g := range generator {
+			if b>>i&1 == 1 {
+				r.hi ^= g.hi
+				r.lo ^= g.lo
+			}
+		}
+	}
+	return r
+}
+
+func checksum(data []byte) []byte {
+	padded := append(append([]byte(nil), data...), make([]byte, checksumLen)...)
+	r := polymod(padded)
+	r.hi ^= residue.hi
+	r.lo ^= residue.lo
+	sum := make([]byte, checksumLen)
+	for i := range sum {
+		shift := 5 * (checksumLen - 1 - i)
+		sum[i] = byte((r.lo>>shift | r.hi<<(64-shift)) & 31)
+	}
+	return sum
+}
@@ -0,0 +1,136 @@
+package codex32
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	for _, v := range testVectors {
+		var shares []Share
+		for _, str := range v.shares {
+			s, err := Parse(str)
+			if err != nil {
+				t.Fatalf("%s: %v", str, err)
+			}
+			if got := s.String(); got != str {
+				t.Errorf("encoding mismatch:\ngot:  %s\nwant: %s", got, str)
+			}
+			shares = append(shares, s)
+		}
+		secret, err := Combine(shares)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := secret.String(); got != v.secret {
+			t.Errorf("secret mismatch:\ngot:  %s\nwant: %s", got, v.secret)
+		}
+		seed, err := secret.Seed()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(seed); got != v.seed {
+			t.Errorf("seed mismatch: got %s, want %s", got, v.seed)
+		}
+	}
+}
+
+func TestCombineSecretShare(t *testing.T) {
+	for _, v := range testVectors {
+		secret, err := Parse(v.secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret.Threshold < 2 {
+			continue
+		}
+		share, err := Parse(v.shares[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The secret share is itself a share, in any position.
+		got, err := Combine([]Share{share, secret})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != v.secret {
+			t.Errorf("secret mismatch:\ngot:  %s\nwant: %s", got, v.secret)
+		}
+		if got := interpolate([]Share{share, secret}, secretIndex); got.String() != v.secret {
+			t.Errorf("interpolated secret mismatch:\ngot:  %s\nwant: %s", got, v.secret)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	str := []byte(testVectors[0].secret)
+	str[len(str)-1] = 'Q'
+	if _, err := Parse(string(str)); !errors.Is(err, ErrChecksum) {
+		t.Errorf("got error %v, want %v", err, ErrChecksum)
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	for _, seedLen := range []int{16, 32} {
+		seed := make([]byte, seedLen)
+		if _, err := rand.Read(seed); err != nil {
+			t.Fatal(err)
+		}
+		const k, n = 3, 5
+		shares, err := Split(rand.Reader, "cash", seed, k, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Verify every share through its string encoding.
+		for i, s := range shares {
+			p, err := Parse(s.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			shares[i] = p
+		}
+		for _, sel := range [][]int{{0, 1, 2}, {2, 3, 4}, {0, 2, 4}, {4, 1, 3}} {
+			var subset []Share
+			for _, i := range sel {
+				subset = append(subset, shares[i])
+			}
+			secret, err := Combine(subset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := secret.Seed()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, seed) {
+				t.Errorf("shares %v: recovered %x, want %x", sel, got, seed)
+			}
+		}
+		if _, err := Combine(shares[:k-1]); !errors.Is(err, ErrShareCount) {
+			t.Errorf("got error %v, want %v", err, ErrShareCount)
+		}
+	}
+}
+
+var testVectors = []struct {
+	shares []string
+	secret string
+	seed   string
+}{
+	{
+		shares: []string{"MS10TESTSXXXXXXXXXXXXXXXXXXXXXXXXXX4NZVCA9CMCZLW"},
+		secret: "MS10TESTSXXXXXXXXXXXXXXXXXXXXXXXXXX4NZVCA9CMCZLW",
+		seed:   "318c6318c6318c6318c6318c6318c631",
+	},
+	{
+		shares: []string{
+			"MS12NAMEA320ZYXWVUTSRQPNMLKJHGFEDCAXRPP870HKKQRM",
+			"MS12NAMECACDEFGHJKLMNPQRSTUVWXYZ023FTR2GDZMPY6PN",
+		},
+		secret: "MS12NAMES6XQGUZTTXKEQNJSJZV4JV3NZ5K3KWGSPHUH6EVW",
+		seed:   "d1808e096b35b209ca12132b264662a5",
+	},
+}
@@ -0,0 +1,367 @@
+package engrave
+
+import (
+	"errors"
+)
+
+// ErrTooLarge is returned when content doesn't fit in any symbol of a
+// 2D code.
+var ErrTooLarge = errors.New("engrave: content too large for symbol")
+
+// DataMatrix returns a command that engraves content as the smallest
+// square ECC 200 Data Matrix symbol, with scale strokes per module.
+func DataMatrix(strokeWidth float32, scale int, content []byte) (Command, error) {
+	bitmap, err := dataMatrix(content)
+	if err != nil {
+		return nil, err
+	}
+	return bitmapCmd{
+		strokeWidth: strokeWidth,
+		scale:       scale,
+		bitmap:      bitmap,
+	}, nil
+}
+
+// dmSymbol describes a square Data Matrix symbol.
+type dmSymbol struct {
+	// size is the width and height in modules.
+	size int
+	// regions is the number of data regions along each side.
+	regions int
+	// data and ec are the number of data and error correction
+	// codewords.
+	data, ec int
+	// blocks is the number of interleaved error correction blocks.
+	blocks int
+}
+
+// dmSymbols lists the square ECC 200 symbols.
+var dmSymbols = []dmSymbol{
+	{10, 1, 3, 5, 1},
+	{12, 1, 5, 7, 1},
+	{14, 1, 8, 10, 1},
+	{16, 1, 12, 12, 1},
+	{18, 1, 18, 14, 1},
+	{20, 1, 22, 18, 1},
+	{22, 1, 30, 20, 1},
+	{24, 1, 36, 24, 1},
+	{26, 1, 44, 28, 1},
+	{32, 2, 62, 36, 1},
+	{36, 2, 86, 42, 1},
+	{40, 2, 114, 48, 1},
+	{44, 2, 144, 56, 1},
+	{48, 2, 174, 68, 1},
+	{52, 2, 204, 84, 2},
+	{64, 4, 280, 112, 2},
+	{72, 4, 368, 144, 4},
+	{80, 4, 456, 192, 4},
+	{88, 4, 576, 224, 4},
+	{96, 4, 696, 272, 4},
+	{104, 4, 816, 336, 6},
+	{120, 6, 1050, 408, 6},
+	{132, 6, 1304, 496, 8},
+	{144, 6, 1558, 620, 10},
+}
+
+// regionSize returns the width and height of a data region in
+// modules.
+func (s dmSymbol) regionSize() int {
+	return s.size/s.regions - 2
+}
+
+// Data Matrix codeword values.
+const (
+	dmPad         = 129
+	dmDigitPairs  = 130
+	dmLatchC40    = 230
+	dmUpperShift  = 235
+	dmUnlatch     = 254
+	dmC40Shift1   = 0
+	dmC40Shift2   = 1
+	dmC40Shift3   = 2
+	dmC40Space    = 3
+	dmC40FirstNum = 4
+	dmC40FirstUC  = 14
+)
+
+func dataMatrix(content []byte) ([][]bool, error) {
+	sym, cws, err := dmCodewords(content)
+	if err != nil {
+		return nil, err
+	}
+	return dmBitmap(sym, cws), nil
+}
+
+// dmCodewords encodes content and selects the smallest symbol that
+// fits it. It returns the interleaved data and error correction
+// codewords.
+func dmCodewords(content []byte) (dmSymbol, []byte, error) {
+	cws := dmEncodeASCII(content)
+	if c40, ok := dmEncodeC40(content); ok && len(c40) < len(cws) {
+		cws = c40
+	}
+	var sym dmSymbol
+	found := false
+	for _, s := range dmSymbols {
+		if s.data >= len(cws) {
+			sym, found = s, true
+			break
+		}
+	}
+	if !found {
+		return dmSymbol{}, nil, ErrTooLarge
+	}
+	// Pad the remaining data codewords.
+	for i := len(cws); i < sym.data; i++ {
+		if i == len(cws) {
+			cws = append(cws, dmPad)
+			continue
+		}
+		pos := i + 1
+		v := dmPad + (149*pos)%253 + 1
+		if v > 254 {
+			v -= 254
+		}
+		cws = append(cws, byte(v))
+	}
+	// Compute and interleave the error correction codewords.
+	cws = append(cws, make([]byte, sym.ec)...)
+	ecPerBlock := sym.ec / sym.blocks
+	for b := 0; b < sym.blocks; b++ {
+		var block []byte
+		for i := b; i < sym.data; i += sym.blocks {
+			block = append(block, cws[i])
+		}
+		ec := dataMatrixField.rsEncode(block, ecPerBlock, 1)
+		for i, c := range ec {
+			cws[sym.data+b+i*sym.blocks] = c
+		}
+	}
+	return sym, cws, nil
+}
+
+func dmBitmap(sym dmSymbol, cws []byte) [][]bool {
+	rsize := sym.regionSize()
+	n := sym.regions * rsize
+	placement := dmPlacement(n, n)
+	bitmap := make([][]bool, sym.size)
+	for y := range bitmap {
+		bitmap[y] = make([]bool, sym.size)
+	}
+	// Finder and clock patterns around every region.
+	for ry := 0; ry < sym.regions; ry++ {
+		for rx := 0; rx < sym.regions; rx++ {
+			x0, y0 := rx*(rsize+2), ry*(rsize+2)
+			x1, y1 := x0+rsize+1, y0+rsize+1
+			for i := 0; i < rsize+2; i++ {
+				bitmap[y0+i][x0] = true
+				bitmap[y1][x0+i] = true
+				bitmap[y0][x0+i] = i%2 == 0
+				bitmap[y0+i][x1] = i%2 == 1
+			}
+		}
+	}
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			y := r + 2*(r/rsize) + 1
+			x := c + 2*(c/rsize) + 1
+			switch v := placement[r*n+c]; v {
+			case dmCornerDark:
+				bitmap[y][x] = true
+			case dmCornerLight:
+			default:
+				bitmap[y][x] = cws[v/8]&(0x80>>(v%8)) != 0
+			}
+		}
+	}
+	return bitmap
+}
+
+func dmEncodeASCII(content []byte) []byte {
+	var cws []byte
+	isDigit := func(c byte) bool { return '0' <= c && c <= '9' }
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case i+1 < len(content) && isDigit(c) && isDigit(content[i+1]):
+			cws = append(cws, dmDigitPairs+(c-'0')*10+content[i+1]-'0')
+			i++
+		case c < 128:
+			cws = append(cws, c+1)
+		default:
+			cws = append(cws, dmUpperShift, c-127)
+		}
+	}
+	return cws
+}
+
+// dmEncodeC40 encodes ASCII content in the C40 encodation, which is
+// efficient for upper case letters and digits.
+func dmEncodeC40(content []byte) ([]byte, bool) {
+	var vals []byte
+	// lens tracks the number of values of each character.
+	var lens []int
+	for _, c := range content {
+		before := len(vals)
+		switch {
+		case c == ' ':
+			vals = append(vals, dmC40Space)
+		case '0' <= c && c <= '9':
+			vals = append(vals, dmC40FirstNum+c-'0')
+		case 'A' <= c && c <= 'Z':
+			vals = append(vals, dmC40FirstUC+c-'A')
+		case c < 32:
+			vals = append(vals, dmC40Shift1, c)
+		case c <= 47:
+			vals = append(vals, dmC40Shift2, c-33)
+		case 58 <= c && c <= 64:
+			vals = append(vals, dmC40Shift2, c-58+15)
+		case 91 <= c && c <= 95:
+			vals = append(vals, dmC40Shift2, c-91+22)
+		case 96 <= c && c <= 127:
+			vals = append(vals, dmC40Shift3, c-96)
+		default:
+			return nil, false
+		}
+		lens = append(lens, len(vals)-before)
+	}
+	// Values are packed in triplets. Encode a final character that
+	// would leave a single value in ASCII, and pad a final pair.
+	tail := 0
+	if len(vals)%3 == 1 {
+		tail = 1
+		vals = vals[:len(vals)-lens[len(lens)-1]]
+	}
+	if len(vals)%3 == 2 {
+		vals = append(vals, dmC40Shift1)
+	}
+	cws := []byte{dmLatchC40}
+	for i := 0; i < len(vals); i += 3 {
+		v := 1600*int(vals[i]) + 40*int(vals[i+1]) + int(vals[i+2]) + 1
+		cws = append(cws, byte(v>>8), byte(v))
+	}
+	cws = append(cws, dmUnlatch)
+	cws = append(cws, dmEncodeASCII(content[len(content)-tail:])...)
+	return cws, true
+}
+
+// Placement values of the modules of the fixed corner pattern.
+const (
+	dmCornerDark  = -1
+	dmCornerLight = -2
+)
+
+// dmPlacement computes the placement of codeword bits in the data
+// regions of a symbol, without its finder patterns, as described by
+// ISO/IEC 16022 Annex F. Each element is the codeword index times 8
+// plus the bit index, counted from the most significant bit, or one
+// of the corner pattern values.
+func dmPlacement(nrow, ncol int) []int {
+	const unset = -3
+	array := make([]int, nrow*ncol)
+	for i := range array {
+		array[i] = unset
+	}
+	module := func(row, col, chr, bit int) {
+		if row < 0 {
+			row += nrow
+			col += 4 - ((nrow + 4) % 8)
+		}
+		if col < 0 {
+			col += ncol
+			row += 4 - ((ncol + 4) % 8)
+		}
+		array[row*ncol+col] = chr*8 + bit
+	}
+	utah := func(row, col, chr int) {
+		module(row-2, col-2, chr, 0)
+		module(row-2, col-1, chr, 1)
+		module(row-1, col-2, chr, 2)
+		module(row-1, col-1, chr, 3)
+		module(row-1, col, chr, 4)
+		module(row, col-2, chr, 5)
+		module(row, col-1, chr, 6)
+		module(row, col, chr, 7)
+	}
+	corner := func(chr int, pos [8][2]int) {
+		for bit, p := range pos {
+			module(p[0], p[1], chr, bit)
+		}
+	}
+	corner1 := [8][2]int{
+		{nrow - 1, 0}, {nrow - 1, 1}, {nrow - 1, 2}, {0, ncol - 2},
+		{0, ncol - 1}, {1, ncol - 1}, {2, ncol - 1}, {3, ncol - 1},
+	}
+	corner2 := [8][2]int{
+		{nrow - 3, 0}, {nrow - 2, 0}, {nrow - 1, 0}, {0, ncol - 4},
+		{0, ncol - 3}, {0, ncol - 2}, {0, ncol - 1}, {1, ncol - 1},
+	}
+	corner3 := [8][2]int{
+		{nrow - 3, 0}, {nrow - 2, 0}, {nrow - 1, 0}, {0, ncol - 2},
+		{0, ncol - 1}, {1, ncol - 1}, {2, ncol - 1}, {3, ncol - 1},
+	}
+	corner4 := [8][2]int{
+		{nrow - 1, 0}, {nrow - 1, ncol - 1}, {0, ncol - 3}, {0, ncol - 2},
+		{0, ncol - 1}, {1, ncol - 3}, {1, ncol - 2}, {1, ncol - 1},
+	}
+	chr := 0
+	row, col := 4, 0
+	for {
+		switch {
+		case row == nrow && col == 0:
+			corner(chr, corner1)
+			chr++
+		case row == nrow-2 && col == 0 && ncol%4 != 0:
+			corner(chr, corner2)
+			chr++
+		case row == nrow-2 && col == 0 && ncol%8 == 4:
+			corner(chr, corner3)
+			chr++
+		case row == nrow+4 && col == 2 && ncol%8 == 0:
+			corner(chr, corner4)
+			chr++
+		}
+		// Sweep upward diagonally.
+		for {
+			if row < nrow && col >= 0 && array[row*ncol+col] == unset {
+				utah(row, col, chr)
+				chr++
+			}
+			row -= 2
+			col += 2
+			if row < 0 || col >= ncol {
+				break
+			}
+		}
+		row++
+		col += 3
+		// Sweep downward diagonally.
+		for {
+			if row >= 0 && col < ncol && array[row*ncol+col] == unset {
+				utah(row, col, chr)
+				chr++
+			}
+			row += 2
+			col -= 2
+			if row >= nrow || col < 0 {
+				break
+			}
+		}
+		row += 3
+		col++
+		if row >= nrow && col >= ncol {
+			break
+		}
+	}
+	// Fill the unused corner, if any.
+	if array[nrow*ncol-1] == unset {
+		array[nrow*ncol-1] = dmCornerDark
+		array[(nrow-2)*ncol+ncol-2] = dmCornerDark
+	}
+	for i, v := range array {
+		if v == unset {
+			array[i] = dmCornerLight
+		}
+	}
+	return array
+}
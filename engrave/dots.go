@@ -0,0 +1,105 @@
+package engrave
+
+import (
+	"math"
+
+	"golang.org/x/image/math/f32"
+)
+
+// DotProgram is a Program that can strike single points, such as
+// the impacts of a hammer.
+type DotProgram interface {
+	Program
+	Dot(p f32.Vec2)
+}
+
+// Dot strikes a single point. Programs that don't implement
+// DotProgram receive a move followed by a line of zero length.
+func Dot(p Program, pos f32.Vec2) {
+	if d, ok := p.(DotProgram); ok {
+		d.Dot(pos)
+		return
+	}
+	p.Move(pos)
+	p.Line(pos)
+}
+
+// dotProgram converts lines to dots.
+type dotProgram struct {
+	prog    Program
+	pitch   float32
+	pos     f32.Vec2
+	drawing bool
+}
+
+// Dots returns a program that converts the lines engraved to it into
+// dots spaced at most pitch apart along each stroke, and strikes them
+// on prog. Every end point of a line is struck. 2D codes engraved to
+// the program strike a pattern of dots for each module instead of
+// lines.
+func Dots(pitch float32, prog Program) DotProgram {
+	return &dotProgram{
+		prog:  prog,
+		pitch: pitch,
+	}
+}
+
+// Dotted returns a command that engraves c through Dots.
+func Dotted(pitch float32, c Command) Command {
+	return dottedCmd{pitch: pitch, cmd: c}
+}
+
+type dottedCmd struct {
+	pitch float32
+	cmd   Command
+}
+
+func (d dottedCmd) Engrave(p Program) {
+	d.cmd.Engrave(Dots(d.pitch, p))
+}
+
+func (d *dotProgram) Move(p f32.Vec2) {
+	d.pos = p
+	d.drawing = false
+}
+
+func (d *dotProgram) Line(p f32.Vec2) {
+	if !d.drawing {
+		d.Dot(d.pos)
+	}
+	from := d.pos
+	d.pos = p
+	l := dist(from, p)
+	if l == 0 {
+		return
+	}
+	n := int(math.Ceil(float64(l / d.pitch)))
+	for i := 1; i <= n; i++ {
+		t := float32(i) / float32(n)
+		d.Dot(f32.Vec2{
+			from[0] + t*(p[0]-from[0]),
+			from[1] + t*(p[1]-from[1]),
+		})
+	}
+}
+
+func (d *dotProgram) Dot(p f32.Vec2) {
+	Dot(d.prog, p)
+	d.pos = p
+	d.drawing = true
+}
+
+// isDots reports whether p converts lines to dots, possibly through
+// transformations.
+func isDots(p Program) bool {
+	for {
+		switch t := p.(type) {
+		case *dotProgram:
+			return true
+		case *transformedProgram:
+			p = t.prog
+		default:
+			return false
+		}
+	}
+}
@@ -50,6 +50,10 @@ func (t *transformedProgram) Line(p f32.Vec2) {
 	t.prog.Line(affine.Transform(t.trans, p))
 }
 
+func (t *transformedProgram) Dot(p f32.Vec2) {
+	Dot(t.prog, affine.Transform(t.trans, p))
+}
+
 func TransformedProgram(prog Program, transform f32.Aff3) Program {
 	return &transformedProgram{
 		prog:  prog,
@@ -110,13 +114,35 @@ func (q qrCmd) Engrave(p Program) {
 		panic(err)
 	}
 	qr.DisableBorder = true
-	bitmap := qr.Bitmap()
+	bitmapCmd{
+		strokeWidth: q.strokeWidth,
+		scale:       q.scale,
+		bitmap:      qr.Bitmap(),
+	}.Engrave(p)
+}
+
+// bitmapCmd fills the dark modules of a 2D code with horizontal
+// strokes, scale strokes per module. When engraved to a program from
+// Dots, it strikes scale by scale dots per module instead.
+type bitmapCmd struct {
+	strokeWidth float32
+	scale       int
+	// bitmap is indexed by row, then column.
+	bitmap [][]bool
+}
+
+func (b bitmapCmd) Engrave(p Program) {
+	if isDots(p) {
+		b.engraveDots(p)
+		return
+	}
+	bitmap := b.bitmap
 	for y := 0; y < len(bitmap); y++ {
 		row := bitmap[y]
-		for i := 0; i < q.scale; i++ {
+		for i := 0; i < b.scale; i++ {
 			draw := false
 			var firstx int
-			line := y*q.scale + i
+			line := y*b.scale + i
 			// Swap direction every other line.
 			rev := line%2 != 0
 			radius := float32(.5)
@@ -124,8 +150,8 @@ func (q qrCmd) Engrave(p Program) {
 				radius = -radius
 			}
 			drawLine := func(endx int) {
-				start := affine.Scale(f32.Vec2{float32(firstx*q.scale) + radius, float32(line)}, q.strokeWidth)
-				end := affine.Scale(f32.Vec2{float32(endx*q.scale) - radius, float32(line)}, q.strokeWidth)
+				start := affine.Scale(f32.Vec2{float32(firstx*b.scale) + radius, float32(line)}, b.strokeWidth)
+				end := affine.Scale(f32.Vec2{float32(endx*b.scale) - radius, float32(line)}, b.strokeWidth)
 				p.Move(start)
 				p.Line(end)
 				draw = false
@@ -150,6 +176,27 @@ func (q qrCmd) Engrave(p Program) {
 	}
 }
 
+// engraveDots strikes the dots of the dark modules, one row of dots
+// at a time in alternating directions.
+func (b bitmapCmd) engraveDots(p Program) {
+	for y, row := range b.bitmap {
+		for i := 0; i < b.scale; i++ {
+			line := y*b.scale + i
+			rev := line%2 != 0
+			for j := 0; j < len(row)*b.scale; j++ {
+				col := j
+				if rev {
+					col = len(row)*b.scale - 1 - j
+				}
+				if !row[col/b.scale] {
+					continue
+				}
+				Dot(p, affine.Scale(f32.Vec2{float32(col) + .5, float32(line)}, b.strokeWidth))
+			}
+		}
+	}
+}
+
 func String(face *font.Face, mmPrEm float32, msg string) *StringCmd {
 	return &StringCmd{
 		LineHeight: 1,
@@ -0,0 +1,743 @@
+package engrave
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/math/f32"
+)
+
+// pathsCmd engraves a list of paths.
+type pathsCmd [][]f32.Vec2
+
+func (c pathsCmd) Engrave(p Program) {
+	for _, path := range c {
+		p.Move(path[0])
+		for _, pt := range path[1:] {
+			p.Line(pt)
+		}
+	}
+}
+
+var testPaths = pathsCmd{
+	{{1, 2}, {3, 2}, {3, 4.5}},
+	// Moves without lines are dropped.
+	{{5, 5}},
+	{{6, 1}, {7, 1}},
+}
+
+func TestSVG(t *testing.T) {
+	buf := new(bytes.Buffer)
+	svg := NewSVG(buf, image.Rect(0, 0, 10, 8), .3)
+	testPaths.Engrave(svg)
+	if err := svg.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="10mm" height="8mm" viewBox="0 0 10 8">
+<g fill="none" stroke="black" stroke-width="0.300" stroke-linecap="round" stroke-linejoin="round">
+<polyline points="1.000,2.000 3.000,2.000 3.000,4.500"/>
+<polyline points="6.000,1.000 7.000,1.000"/>
+</g>
+</svg>
+`
+	if got := buf.String(); got != want {
+		t.Errorf("SVG mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDXF(t *testing.T) {
+	buf := new(bytes.Buffer)
+	dxf := NewDXF(buf, image.Rect(0, 0, 10, 8))
+	testPaths.Engrave(dxf)
+	if err := dxf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if n := strings.Count(got, "\nPOLYLINE\n"); n != 2 {
+		t.Errorf("got %d polylines, want 2", n)
+	}
+	if n := strings.Count(got, "\nVERTEX\n"); n != 5 {
+		t.Errorf("got %d vertices, want 5", n)
+	}
+	// The y axis points up.
+	if !strings.Contains(got, "10\n3.000\n20\n3.500\n") {
+		t.Errorf("flipped vertex (3, 3.5) missing from:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "0\nENDSEC\n0\nEOF\n") {
+		t.Error("incomplete DXF")
+	}
+}
+
+func TestGCode(t *testing.T) {
+	buf := new(bytes.Buffer)
+	conf := GCodeConfig{
+		Feed:       300,
+		PlungeFeed: 100,
+		Depth:      .1,
+		SafeZ:      2,
+	}
+	gcode := NewGCode(buf, image.Rect(0, 0, 10, 8), conf)
+	testPaths.Engrave(gcode)
+	if err := gcode.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := `G21
+G90
+G0 Z2.000
+G0 X1.000 Y6.000
+G1 Z-0.100 F100.000
+G1 F300.000
+G1 X3.000 Y6.000
+G1 X3.000 Y3.500
+G0 Z2.000
+G0 X6.000 Y7.000
+G1 Z-0.100 F100.000
+G1 F300.000
+G1 X7.000 Y7.000
+G0 Z2.000
+M2
+`
+	if got := buf.String(); got != want {
+		t.Errorf("G-code mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var paths pathsCmd
+	for i := 0; i < 500; i++ {
+		path := []f32.Vec2{{rng.Float32() * 100, rng.Float32() * 100}}
+		for j := rng.Intn(3); j >= 0; j-- {
+			last := path[len(path)-1]
+			path = append(path, f32.Vec2{last[0] + rng.Float32(), last[1] + rng.Float32()})
+		}
+		paths = append(paths, path)
+	}
+	opt := Optimize(paths)
+	before, after := Travel(paths), Travel(opt)
+	t.Logf("travel before: %.0f mm, after: %.0f mm", before, after)
+	if after >= before/4 {
+		t.Errorf("travel distance %f not sufficiently reduced from %f", after, before)
+	}
+	if !reflect.DeepEqual(segments(paths), segments(opt)) {
+		t.Error("optimized engraving differs from the original")
+	}
+}
+
+// segments returns the lines of an engraving in a canonical order.
+func segments(c Command) [][2]f32.Vec2 {
+	s := new(segmentProgram)
+	c.Engrave(s)
+	less := func(a, b f32.Vec2) bool {
+		return a[0] < b[0] || a[0] == b[0] && a[1] < b[1]
+	}
+	for i, seg := range s.segs {
+		if less(seg[1], seg[0]) {
+			s.segs[i] = [2]f32.Vec2{seg[1], seg[0]}
+		}
+	}
+	sort.Slice(s.segs, func(i, j int) bool {
+		a, b := s.segs[i], s.segs[j]
+		if a[0] != b[0] {
+			return less(a[0], b[0])
+		}
+		return less(a[1], b[1])
+	})
+	return s.segs
+}
+
+type segmentProgram struct {
+	pos  f32.Vec2
+	segs [][2]f32.Vec2
+}
+
+func (s *segmentProgram) Move(p f32.Vec2) {
+	s.pos = p
+}
+
+func (s *segmentProgram) Line(p f32.Vec2) {
+	s.segs = append(s.segs, [2]f32.Vec2{s.pos, p})
+	s.pos = p
+}
+
+func TestMeasure(t *testing.T) {
+	s := Measure(testPaths)
+	if s.LineLength != 5.5 {
+		t.Errorf("line length %v, want 5.5", s.LineLength)
+	}
+	if s.Lines != 3 || s.Strokes != 2 || s.Turns != 1 {
+		t.Errorf("got %d lines, %d strokes, %d turns, want 3, 2, 1", s.Lines, s.Strokes, s.Turns)
+	}
+	// From the origin to (1, 2), from (3, 4.5) to (5, 5) and on to (6, 1).
+	want := dist(f32.Vec2{}, f32.Vec2{1, 2}) + dist(f32.Vec2{3, 4.5}, f32.Vec2{5, 5}) + dist(f32.Vec2{5, 5}, f32.Vec2{6, 1})
+	if d := s.MoveLength - want; d < -1e-4 || d > 1e-4 {
+		t.Errorf("move length %v, want %v", s.MoveLength, want)
+	}
+}
+
+func TestDataMatrixCodewords(t *testing.T) {
+	// The example of ISO/IEC 16022 Annex O.
+	sym, cws, err := dmCodewords([]byte("123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{142, 164, 186, 114, 25, 5, 88, 102}
+	if sym.size != 10 || !bytes.Equal(cws, want) {
+		t.Errorf("got %dx%d symbol with codewords %v, want 10x10 with %v", sym.size, sym.size, cws, want)
+	}
+}
+
+func TestDataMatrixReference(t *testing.T) {
+	// The symbol of the ISO/IEC 16022 Annex O example, generated by an
+	// encoder independent of this package.
+	want := parseBitmap(`
+		#.#.#.#.#.
+		##..#.##.#
+		##.....#..
+		##...###.#
+		##....#...
+		#.....####
+		###.##....
+		####.##..#
+		#..###.#..
+		##########
+	`)
+	got, err := dataMatrix([]byte("123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got symbol\n%s\nwant\n%s", formatBitmap(got), formatBitmap(want))
+	}
+}
+
+func TestDataMatrixPlacement(t *testing.T) {
+	for _, sym := range dmSymbols {
+		n := sym.regions * sym.regionSize()
+		placement := dmPlacement(n, n)
+		seen := make(map[int]bool)
+		for _, v := range placement {
+			if v < 0 {
+				continue
+			}
+			if seen[v] {
+				t.Fatalf("%dx%d: bit %d placed twice", sym.size, sym.size, v)
+			}
+			seen[v] = true
+		}
+		if got, want := len(seen), (sym.data+sym.ec)*8; got != want {
+			t.Errorf("%dx%d: placed %d bits, want %d", sym.size, sym.size, got, want)
+		}
+	}
+}
+
+func TestMicroQRCodewords(t *testing.T) {
+	// The example of ISO/IEC 18004 Annex I.
+	bits, ok := mqrEncode(mqrSymbols[1], mqrNumeric, []byte("01234567"))
+	if !ok {
+		t.Fatal("content doesn't fit M2-L")
+	}
+	cws := mqrCodewords(mqrSymbols[1], bits)
+	want := []byte{
+		0b01000000, 0b00011000, 0b10101100, 0b11000011, 0b00000000,
+		0b10000110, 0b00001101, 0b00100010, 0b10101110, 0b00110000,
+	}
+	if !bytes.Equal(cws, want) {
+		t.Errorf("got codewords %x, want %x", cws, want)
+	}
+}
+
+func TestMicroQRReference(t *testing.T) {
+	// The M2-L symbol of the ISO/IEC 18004 Annex I example with mask
+	// pattern 01, generated by an encoder independent of this package.
+	want := parseBitmap(`
+		#######.#.#.#
+		#.....#.###.#
+		#.###.#..##.#
+		#.###.#..####
+		#.###.#.###..
+		#.....#.#...#
+		#######..####
+		.........##..
+		##.#....#...#
+		.##.#.#.#.#.#
+		###..#######.
+		...#.#....##.
+		###.#..##.###
+	`)
+	got, err := microQR(qrcode.Low, []byte("01234567"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got symbol\n%s\nwant\n%s", formatBitmap(got), formatBitmap(want))
+	}
+}
+
+// parseBitmap parses a bitmap of lines of '#' (dark) and '.' (light)
+// modules.
+func parseBitmap(s string) [][]bool {
+	var bitmap [][]bool
+	for _, line := range strings.Fields(s) {
+		row := make([]bool, len(line))
+		for i, c := range line {
+			row[i] = c == '#'
+		}
+		bitmap = append(bitmap, row)
+	}
+	return bitmap
+}
+
+func formatBitmap(bitmap [][]bool) string {
+	var b strings.Builder
+	for _, row := range bitmap {
+		for _, v := range row {
+			if v {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestDataMatrixRoundTrip(t *testing.T) {
+	for _, content := range []string{
+		"123456",
+		"Hello, World!",
+		"UR:CRYPTO-OUTPUT/1-3/LPADAXCFAXHLCYYNSBGYRTHDRSWTSNTSAMHYAOCTVDNBSKOEHLJSDNYTYAPKIAMWKTIWYCPYTTIHIMURSOS",
+		strings.Repeat("DATA MATRIX ", 60),
+	} {
+		const scale = 2
+		cmd, err := DataMatrix(.3, scale, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sym, _, err := dmCodewords([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bitmap := sample(cmd, .3, scale, sym.size)
+		got, err := decodeDataMatrix(bitmap)
+		if err != nil {
+			t.Errorf("%dx%d: %v", sym.size, sym.size, err)
+			continue
+		}
+		if got != content {
+			t.Errorf("decoded %q, want %q", got, content)
+		}
+	}
+	if _, err := DataMatrix(.3, 2, bytes.Repeat([]byte{0xff}, 2000)); err != ErrTooLarge {
+		t.Errorf("got error %v for oversized content, want %v", err, ErrTooLarge)
+	}
+}
+
+func TestMicroQRRoundTrip(t *testing.T) {
+	tests := []struct {
+		level   qrcode.RecoveryLevel
+		content string
+		version int
+	}{
+		{qrcode.Low, "12345", 1},
+		{qrcode.Low, "01234567", 2},
+		{qrcode.Medium, "HELLO", 2},
+		{qrcode.Medium, "seedhammer", 4},
+		{qrcode.Low, "UR:BYTES/HDCX", 3},
+		{qrcode.High, "QUARTILE", 4},
+	}
+	for _, test := range tests {
+		const scale = 2
+		cmd, err := MicroQR(.3, scale, test.level, []byte(test.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := 2*test.version + 9
+		bitmap := sample(cmd, .3, scale, size)
+		got, err := decodeMicroQR(bitmap)
+		if err != nil {
+			t.Errorf("M%d: %v", test.version, err)
+			continue
+		}
+		if got != test.content {
+			t.Errorf("decoded %q, want %q", got, test.content)
+		}
+	}
+	if _, err := MicroQR(.3, 2, qrcode.Low, bytes.Repeat([]byte("x"), 16)); err != ErrTooLarge {
+		t.Errorf("got error %v for oversized content, want %v", err, ErrTooLarge)
+	}
+}
+
+// sample rasterizes a 2D code and samples the center of its modules.
+func sample(cmd Command, strokeWidth float32, scale, size int) [][]bool {
+	const ppmm = 20
+	module := strokeWidth * float32(scale)
+	// Leave a quiet zone around the symbol.
+	dim := int(float32(size+2) * module * ppmm)
+	img := image.NewAlpha(image.Rect(0, 0, dim, dim))
+	r := NewRasterizer(img, img.Bounds(), strokeWidth*ppmm)
+	Scale(ppmm, ppmm, Offset(module, module, cmd)).Engrave(r)
+	r.Rasterize()
+	bitmap := make([][]bool, size)
+	for y := range bitmap {
+		bitmap[y] = make([]bool, size)
+		for x := range bitmap[y] {
+			// Strokes are centered on their lines.
+			cx := (float32(x) + 1.5) * module
+			cy := (float32(y)+1.5)*module - strokeWidth/2
+			bitmap[y][x] = img.AlphaAt(int(cx*ppmm), int(cy*ppmm)).A > 128
+		}
+	}
+	return bitmap
+}
+
+// syndromesZero reports whether a Reed-Solomon codeword has zero
+// syndromes for the roots α^first, ..., α^(first+ec-1).
+func syndromesZero(f *gf256, cw []byte, ec, first int) bool {
+	for i := 0; i < ec; i++ {
+		x := f.exp[(first+i)%255]
+		s := byte(0)
+		for _, c := range cw {
+			s = f.mul(s, x) ^ c
+		}
+		if s != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeDataMatrix(bitmap [][]bool) (string, error) {
+	size := len(bitmap)
+	var sym dmSymbol
+	for _, s := range dmSymbols {
+		if s.size == size {
+			sym = s
+		}
+	}
+	if sym.size == 0 {
+		return "", fmt.Errorf("no %dx%d symbol", size, size)
+	}
+	rsize := sym.regionSize()
+	if !bitmap[size-1][0] || !bitmap[size-1][size-1] || !bitmap[0][0] || bitmap[0][size-1] {
+		return "", errors.New("missing finder pattern")
+	}
+	// Extract the data regions.
+	n := sym.regions * rsize
+	placement := dmPlacement(n, n)
+	cws := make([]byte, sym.data+sym.ec)
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			v := placement[r*n+c]
+			if v >= 0 && bitmap[r+2*(r/rsize)+1][c+2*(c/rsize)+1] {
+				cws[v/8] |= 0x80 >> (v % 8)
+			}
+		}
+	}
+	// Check every block.
+	ecPerBlock := sym.ec / sym.blocks
+	for b := 0; b < sym.blocks; b++ {
+		var block []byte
+		for i := b; i < sym.data; i += sym.blocks {
+			block = append(block, cws[i])
+		}
+		for i := 0; i < ecPerBlock; i++ {
+			block = append(block, cws[sym.data+b+i*sym.blocks])
+		}
+		if !syndromesZero(dataMatrixField, block, ecPerBlock, 1) {
+			return "", fmt.Errorf("block %d: error correction mismatch", b)
+		}
+	}
+	var out []byte
+	data := cws[:sym.data]
+	c40 := false
+	// shift is the active C40 shift set plus one, or zero.
+	shift := 0
+	for len(data) > 0 {
+		if c40 {
+			if data[0] == dmUnlatch {
+				c40 = false
+				data = data[1:]
+				continue
+			}
+			v := (int(data[0])<<8 | int(data[1])) - 1
+			data = data[2:]
+			for _, c := range []int{v / 1600, v / 40 % 40, v % 40} {
+				// Shifted values may span triplets.
+				switch shift {
+				case dmC40Shift1 + 1:
+					out = append(out, byte(c))
+				case dmC40Shift2 + 1:
+					out = append(out, "!\"#$%&'()*+,-./:;<=>?@[\\]^_"[c])
+				case dmC40Shift3 + 1:
+					out = append(out, byte(c+96))
+				default:
+					switch {
+					case c <= dmC40Shift3:
+						shift = c + 1
+						continue
+					case c == dmC40Space:
+						out = append(out, ' ')
+					case c >= dmC40FirstUC:
+						out = append(out, byte(c-dmC40FirstUC+'A'))
+					default:
+						out = append(out, byte(c-dmC40FirstNum+'0'))
+					}
+				}
+				shift = 0
+			}
+			continue
+		}
+		c := data[0]
+		data = data[1:]
+		switch {
+		case c == dmPad:
+			return string(out), nil
+		case c <= 128:
+			out = append(out, c-1)
+		case c < dmLatchC40:
+			out = append(out, '0'+(c-dmDigitPairs)/10, '0'+(c-dmDigitPairs)%10)
+		case c == dmLatchC40:
+			c40 = true
+		case c == dmUpperShift:
+			out = append(out, data[0]+127)
+			data = data[1:]
+		default:
+			return "", fmt.Errorf("unsupported codeword %d", c)
+		}
+	}
+	return string(out), nil
+}
+
+func decodeMicroQR(bitmap [][]bool) (string, error) {
+	size := len(bitmap)
+	version := (size - 9) / 2
+	var format uint
+	for i := 0; i < 8; i++ {
+		if bitmap[8][i+1] {
+			format |= 1 << (14 - i)
+		}
+	}
+	for i := 0; i < 7; i++ {
+		if bitmap[7-i][8] {
+			format |= 1 << (6 - i)
+		}
+	}
+	number, mask := int(format^0x4445)>>12, int(format^0x4445)>>10&0b11
+	if mqrFormat(number, mask) != format {
+		return "", fmt.Errorf("invalid format information %.15b", format)
+	}
+	sym := mqrSymbols[number]
+	if sym.version != version {
+		return "", fmt.Errorf("symbol M%d in %dx%d modules", sym.version, size, size)
+	}
+	// Read the data modules in the zig-zag order.
+	var bits []bool
+	for right := size - 1; right > 0; right -= 2 {
+		upward := (size-1-right)%4 == 0
+		for i := 0; i < size; i++ {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+			for x := right; x >= right-1; x-- {
+				if x <= 8 && y <= 8 || x == 0 || y == 0 {
+					continue
+				}
+				bits = append(bits, bitmap[y][x] != mqrMasks[mask](x, y))
+			}
+		}
+	}
+	read := func(n int) uint {
+		v := uint(0)
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if bits[0] {
+				v |= 1
+			}
+			bits = bits[1:]
+		}
+		return v
+	}
+	ndata := (sym.dataBits + 7) / 8
+	var cws []byte
+	for i := 0; i < ndata+sym.ec; i++ {
+		if i == ndata-1 && sym.dataBits%8 != 0 {
+			cws = append(cws, byte(read(4)<<4))
+		} else {
+			cws = append(cws, byte(read(8)))
+		}
+	}
+	if !syndromesZero(qrField, cws, sym.ec, 0) {
+		return "", errors.New("error correction mismatch")
+	}
+	bits = nil
+	for _, c := range cws[:ndata] {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, c&(1<<i) != 0)
+		}
+	}
+	mode := read(version - 1)
+	n := int(read(mqrCountBits[mode][version-1]))
+	var out []byte
+	switch mode {
+	case mqrNumeric:
+		for ; n > 0; n -= 3 {
+			digits := n
+			if digits > 3 {
+				digits = 3
+			}
+			out = append(out, fmt.Sprintf("%0*d", digits, read(3*digits+1))...)
+		}
+	case mqrAlphanumeric:
+		for ; n > 1; n -= 2 {
+			v := read(11)
+			out = append(out, mqrAlphabet[v/45], mqrAlphabet[v%45])
+		}
+		if n == 1 {
+			out = append(out, mqrAlphabet[read(6)])
+		}
+	case mqrByte:
+		for ; n > 0; n-- {
+			out = append(out, byte(read(8)))
+		}
+	}
+	return string(out), nil
+}
+
+func TestDots(t *testing.T) {
+	d := new(dotRecorder)
+	p := Dots(.3, d)
+	p.Move(f32.Vec2{1, 1})
+	p.Line(f32.Vec2{2, 1})
+	p.Line(f32.Vec2{2, 1.5})
+	want := []f32.Vec2{
+		{1, 1}, {1.25, 1}, {1.5, 1}, {1.75, 1}, {2, 1},
+		{2, 1.25}, {2, 1.5},
+	}
+	if !reflect.DeepEqual(d.dots, want) {
+		t.Errorf("got dots %v, want %v", d.dots, want)
+	}
+	if d.lines > 0 {
+		t.Errorf("%d lines engraved in dot mode", d.lines)
+	}
+}
+
+func TestDottedQR(t *testing.T) {
+	const scale = 3
+	content := []byte("UR:CRYPTO-SEED/OYADGDHKWZDTFTHPTOKIGTVWNNJSMSKIZOIYFTDALFSA")
+	qr := QR(.3, scale, qrcode.Medium, content)
+	d := new(dotRecorder)
+	Offset(10, 10, Dotted(.3, qr)).Engrave(d)
+	q, err := qrcode.New(string(content), qrcode.Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.DisableBorder = true
+	dark := 0
+	for _, row := range q.Bitmap() {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	if got, want := len(d.dots), dark*scale*scale; got != want {
+		t.Errorf("got %d dots, want %d", got, want)
+	}
+	if d.lines > 0 {
+		t.Errorf("%d lines engraved in dot mode", d.lines)
+	}
+	opt := new(dotRecorder)
+	Optimize(Offset(10, 10, Dotted(.3, qr))).Engrave(opt)
+	if len(opt.dots) != len(d.dots) || opt.lines > 0 {
+		t.Errorf("optimized: got %d dots and %d lines, want %d dots", len(opt.dots), opt.lines, len(d.dots))
+	}
+	for _, p := range d.dots {
+		if p[0] < 10 || p[1] < 10-.3 {
+			t.Fatalf("dot %v outside symbol", p)
+		}
+	}
+}
+
+// dotRecorder records the dots struck.
+type dotRecorder struct {
+	dots  []f32.Vec2
+	lines int
+}
+
+func (d *dotRecorder) Move(p f32.Vec2) {}
+
+func (d *dotRecorder) Line(p f32.Vec2) {
+	d.lines++
+}
+
+func (d *dotRecorder) Dot(p f32.Vec2) {
+	d.dots = append(d.dots, p)
+}
+
+func TestJob(t *testing.T) {
+	content := []byte("UR:CRYPTO-SEED/OYADGDHKWZDTFTHPTOKIGTVWNNJSMSKIZOIYFTDALFSA")
+	design := Commands{
+		testPaths,
+		Offset(10, 10, Dotted(.3, QR(.3, 3, qrcode.Medium, content))),
+	}
+	job := Record(design)
+	job.Material = "Titanium"
+	job.MoveSpeed, job.PrintSpeed = .75, .4
+	job.PenDown, job.PenUp = 30, 20
+	job.Passes = 2
+	job.DryRun = true
+	job.Plate = image.Rect(-5, 10, 85, 95)
+	job.End = f32.Vec2{100, 50}
+	if !reflect.DeepEqual(Record(job).Steps, job.Steps) {
+		t.Error("replayed job differs from the recording")
+	}
+	if !reflect.DeepEqual(segments(job), segments(design)) {
+		t.Error("job engraves differently from the design")
+	}
+	d1, d2 := new(dotRecorder), new(dotRecorder)
+	design.Engrave(d1)
+	job.Engrave(d2)
+	if !reflect.DeepEqual(d1, d2) {
+		t.Error("job strikes different dots than the design")
+	}
+	data, err := job.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := new(Job)
+	if err := dec.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, job) {
+		t.Error("decoded job differs from the original")
+	}
+	if dec.Hash() != job.Hash() {
+		t.Error("decoded job hash differs from the original")
+	}
+	dec.Steps[len(dec.Steps)-1].P[0] += .01
+	if dec.Hash() == job.Hash() {
+		t.Error("modified job has the same hash")
+	}
+	for _, i := range []int{0, len(jobMagic), 30, len(data) - 1} {
+		corrupt := append([]byte(nil), data...)
+		corrupt[i] ^= 0x01
+		if err := new(Job).UnmarshalBinary(corrupt); !errors.Is(err, ErrJobFormat) {
+			t.Errorf("corrupt byte %d: got error %v, want %v", i, err, ErrJobFormat)
+		}
+	}
+	if err := new(Job).UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrJobFormat) {
+		t.Errorf("truncated job: got error %v, want %v", err, ErrJobFormat)
+	}
+}
@@ -0,0 +1,195 @@
+package engrave
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+
+	"golang.org/x/image/math/f32"
+)
+
+// path collects the connected lines between moves.
+type path struct {
+	pos    f32.Vec2
+	points []f32.Vec2
+}
+
+func (p *path) move(to f32.Vec2) {
+	p.pos = to
+	p.points = p.points[:0]
+}
+
+func (p *path) line(to f32.Vec2) {
+	if len(p.points) == 0 {
+		p.points = append(p.points, p.pos)
+	}
+	p.points = append(p.points, to)
+	p.pos = to
+}
+
+// exporter writes paths in a text format.
+type exporter struct {
+	w    *bufio.Writer
+	path path
+	// flip maps y coordinates to a y axis pointing up, if not zero.
+	flip float32
+	// write outputs a completed path.
+	write func(points []f32.Vec2)
+	err   error
+}
+
+func (e *exporter) Move(p f32.Vec2) {
+	e.flush()
+	e.path.move(p)
+}
+
+func (e *exporter) Line(p f32.Vec2) {
+	e.path.line(p)
+}
+
+func (e *exporter) flush() {
+	if len(e.path.points) == 0 {
+		return
+	}
+	if e.flip != 0 {
+		for i, p := range e.path.points {
+			e.path.points[i][1] = e.flip - p[1]
+		}
+	}
+	e.write(e.path.points)
+	e.path.points = e.path.points[:0]
+}
+
+func (e *exporter) printf(format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
+
+// close writes the remaining path and footer and flushes the output.
+func (e *exporter) close(footer string) error {
+	e.flush()
+	e.printf("%s", footer)
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// coord formats a coordinate with micrometer precision.
+func coord(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', 3, 32)
+}
+
+// SVG is a Program that writes an engraving as SVG polylines.
+type SVG struct {
+	exporter
+}
+
+// NewSVG returns an SVG program that writes a document covering
+// bounds to w. Close must be called to complete the document.
+func NewSVG(w io.Writer, bounds image.Rectangle, strokeWidth float32) *SVG {
+	s := new(SVG)
+	s.w = bufio.NewWriter(w)
+	s.write = func(points []f32.Vec2) {
+		s.printf(`<polyline points="`)
+		for i, p := range points {
+			if i > 0 {
+				s.printf(" ")
+			}
+			s.printf("%s,%s", coord(p[0]), coord(p[1]))
+		}
+		s.printf("\"/>\n")
+	}
+	sz := bounds.Size()
+	s.printf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	s.printf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%dmm\" height=\"%dmm\" viewBox=\"%d %d %d %d\">\n",
+		sz.X, sz.Y, bounds.Min.X, bounds.Min.Y, sz.X, sz.Y)
+	s.printf("<g fill=\"none\" stroke=\"black\" stroke-width=\"%s\" stroke-linecap=\"round\" stroke-linejoin=\"round\">\n", coord(strokeWidth))
+	return s
+}
+
+// Close completes the document.
+func (s *SVG) Close() error {
+	return s.close("</g>\n</svg>\n")
+}
+
+// DXF is a Program that writes an engraving as polyline entities in
+// the AutoCAD R12 DXF format.
+type DXF struct {
+	exporter
+}
+
+// NewDXF returns a DXF program that writes to w. The y axis is flipped
+// to point up, with the engraving staying inside bounds. Close must be
+// called to complete the drawing.
+func NewDXF(w io.Writer, bounds image.Rectangle) *DXF {
+	d := new(DXF)
+	d.w = bufio.NewWriter(w)
+	d.flip = float32(bounds.Min.Y + bounds.Max.Y)
+	d.write = func(points []f32.Vec2) {
+		d.printf("0\nPOLYLINE\n8\nENGRAVE\n66\n1\n10\n0.0\n20\n0.0\n30\n0.0\n")
+		for _, p := range points {
+			d.printf("0\nVERTEX\n8\nENGRAVE\n10\n%s\n20\n%s\n30\n0.0\n", coord(p[0]), coord(p[1]))
+		}
+		d.printf("0\nSEQEND\n8\nENGRAVE\n")
+	}
+	// Declare millimeter units.
+	d.printf("0\nSECTION\n2\nHEADER\n9\n$INSUNITS\n70\n4\n0\nENDSEC\n")
+	d.printf("0\nSECTION\n2\nENTITIES\n")
+	return d
+}
+
+// Close completes the drawing.
+func (d *DXF) Close() error {
+	return d.close("0\nENDSEC\n0\nEOF\n")
+}
+
+// GCode is a Program that writes an engraving as generic G-code for
+// CNC engravers and mills.
+type GCode struct {
+	exporter
+}
+
+// GCodeConfig describes the tool movements of GCode.
+type GCodeConfig struct {
+	// Feed is the engraving feed rate in mm/min.
+	Feed float32
+	// PlungeFeed is the feed rate for lowering the tool.
+	PlungeFeed float32
+	// Depth is the engraving depth below the surface.
+	Depth float32
+	// SafeZ is the height above the surface for moves.
+	SafeZ float32
+}
+
+// NewGCode returns a G-code program that writes to w. The y axis is
+// flipped to point up, with the engraving staying inside bounds. Close
+// must be called to complete the program.
+func NewGCode(w io.Writer, bounds image.Rectangle, conf GCodeConfig) *GCode {
+	g := new(GCode)
+	g.w = bufio.NewWriter(w)
+	g.flip = float32(bounds.Min.Y + bounds.Max.Y)
+	g.write = func(points []f32.Vec2) {
+		start := points[0]
+		g.printf("G0 X%s Y%s\n", coord(start[0]), coord(start[1]))
+		g.printf("G1 Z%s F%s\n", coord(-conf.Depth), coord(conf.PlungeFeed))
+		g.printf("G1 F%s\n", coord(conf.Feed))
+		for _, p := range points[1:] {
+			g.printf("G1 X%s Y%s\n", coord(p[0]), coord(p[1]))
+		}
+		g.printf("G0 Z%s\n", coord(conf.SafeZ))
+	}
+	// Millimeters, absolute positioning.
+	g.printf("G21\nG90\n")
+	g.printf("G0 Z%s\n", coord(conf.SafeZ))
+	return g
+}
+
+// Close ends the program.
+func (g *GCode) Close() error {
+	return g.close("M2\n")
+}
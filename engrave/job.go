@@ -0,0 +1,226 @@
+package engrave
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"golang.org/x/image/math/f32"
+)
+
+// Job is a recorded engraving, independent of the engraver. A Job
+// is a Program that records the commands engraved to it, and a
+// Command that replays them. The remaining fields record the settings
+// of the engraving, so replays engrave identically.
+type Job struct {
+	// Material names the material profile of the plate.
+	Material string
+	// MoveSpeed and PrintSpeed range from 0, the slowest, to 1, the
+	// fastest. Zero means the engraver default.
+	MoveSpeed, PrintSpeed float32
+	// PenDown and PenUp are the delays in milliseconds of lowering
+	// and raising the tool.
+	PenDown, PenUp uint8
+	// Passes is the number of times each line is engraved.
+	Passes int
+	// DryRun moves the tool instead of engraving.
+	DryRun bool
+	// Plate is the area in millimeters of the plate, in engraver
+	// coordinates.
+	Plate image.Rectangle
+	// End is the position of the engraver after the job.
+	End   f32.Vec2
+	Steps []Step
+}
+
+// Step is a single command of a Job.
+type Step struct {
+	Op StepOp
+	P  f32.Vec2
+}
+
+type StepOp uint8
+
+const (
+	MoveOp StepOp = iota
+	LineOp
+	DotOp
+)
+
+// ErrJobFormat is returned when decoding a malformed job.
+var ErrJobFormat = errors.New("engrave: invalid job")
+
+// Job file header and version.
+const (
+	jobMagic   = "SHJOB"
+	jobVersion = 2
+	// jobStepSize is the encoded size of a Step.
+	jobStepSize = 1 + 2*4
+)
+
+// Record returns a job of the commands engraved by c.
+func Record(c Command) *Job {
+	j := new(Job)
+	c.Engrave(j)
+	return j
+}
+
+func (j *Job) Move(p f32.Vec2) {
+	j.Steps = append(j.Steps, Step{Op: MoveOp, P: p})
+}
+
+func (j *Job) Line(p f32.Vec2) {
+	j.Steps = append(j.Steps, Step{Op: LineOp, P: p})
+}
+
+func (j *Job) Dot(p f32.Vec2) {
+	j.Steps = append(j.Steps, Step{Op: DotOp, P: p})
+}
+
+// Engrave replays the recorded commands.
+func (j *Job) Engrave(p Program) {
+	for _, s := range j.Steps {
+		switch s.Op {
+		case MoveOp:
+			p.Move(s.P)
+		case LineOp:
+			p.Line(s.P)
+		case DotOp:
+			Dot(p, s.P)
+		}
+	}
+}
+
+// Hash returns the SHA-256 hash of the encoded job. Jobs with the
+// same hash engrave identically.
+func (j *Job) Hash() [sha256.Size]byte {
+	return sha256.Sum256(j.encode())
+}
+
+// MarshalBinary encodes the job followed by its hash.
+func (j *Job) MarshalBinary() ([]byte, error) {
+	enc := j.encode()
+	h := sha256.Sum256(enc)
+	return append(enc, h[:]...), nil
+}
+
+func (j *Job) encode() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(jobMagic)
+	buf.WriteByte(jobVersion)
+	var tmp [4]byte
+	writeUint := func(v uint32) {
+		binary.LittleEndian.PutUint32(tmp[:], v)
+		buf.Write(tmp[:])
+	}
+	writeFloat := func(v float32) {
+		writeUint(math.Float32bits(v))
+	}
+	writeUint(uint32(len(j.Material)))
+	buf.WriteString(j.Material)
+	writeFloat(j.MoveSpeed)
+	writeFloat(j.PrintSpeed)
+	buf.WriteByte(j.PenDown)
+	buf.WriteByte(j.PenUp)
+	writeUint(uint32(j.Passes))
+	dryRun := byte(0)
+	if j.DryRun {
+		dryRun = 1
+	}
+	buf.WriteByte(dryRun)
+	for _, v := range []int{j.Plate.Min.X, j.Plate.Min.Y, j.Plate.Max.X, j.Plate.Max.Y} {
+		writeUint(uint32(int32(v)))
+	}
+	writeFloat(j.End[0])
+	writeFloat(j.End[1])
+	writeUint(uint32(len(j.Steps)))
+	for _, s := range j.Steps {
+		buf.WriteByte(byte(s.Op))
+		writeFloat(s.P[0])
+		writeFloat(s.P[1])
+	}
+	return buf.Bytes()
+}
+
+// UnmarshalBinary decodes a job encoded by MarshalBinary and verifies
+// its hash.
+func (j *Job) UnmarshalBinary(data []byte) error {
+	if len(data) < len(jobMagic)+1+sha256.Size || string(data[:len(jobMagic)]) != jobMagic {
+		return ErrJobFormat
+	}
+	if v := data[len(jobMagic)]; v != jobVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrJobFormat, v)
+	}
+	enc, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if h := sha256.Sum256(enc); !bytes.Equal(h[:], sum) {
+		return fmt.Errorf("%w: hash mismatch", ErrJobFormat)
+	}
+	d := enc[len(jobMagic)+1:]
+	truncated := false
+	read := func(n int) []byte {
+		if n > len(d) {
+			truncated = true
+			n = len(d)
+		}
+		b := d[:n]
+		d = d[n:]
+		return b
+	}
+	readByte := func() byte {
+		if b := read(1); len(b) == 1 {
+			return b[0]
+		}
+		return 0
+	}
+	readUint := func() uint32 {
+		if b := read(4); len(b) == 4 {
+			return binary.LittleEndian.Uint32(b)
+		}
+		return 0
+	}
+	readFloat := func() float32 {
+		return math.Float32frombits(readUint())
+	}
+	readInt := func() int {
+		return int(int32(readUint()))
+	}
+	var dec Job
+	if n := readUint(); uint64(n) <= uint64(len(d)) {
+		dec.Material = string(read(int(n)))
+	} else {
+		truncated = true
+	}
+	dec.MoveSpeed = readFloat()
+	dec.PrintSpeed = readFloat()
+	dec.PenDown = readByte()
+	dec.PenUp = readByte()
+	dec.Passes = int(readUint())
+	switch readByte() {
+	case 0:
+	case 1:
+		dec.DryRun = true
+	default:
+		return fmt.Errorf("%w: invalid dry run flag", ErrJobFormat)
+	}
+	dec.Plate.Min = image.Pt(readInt(), readInt())
+	dec.Plate.Max = image.Pt(readInt(), readInt())
+	dec.End = f32.Vec2{readFloat(), readFloat()}
+	n := readUint()
+	if truncated || uint64(len(d)) != uint64(n)*jobStepSize {
+		return fmt.Errorf("%w: truncated", ErrJobFormat)
+	}
+	dec.Steps = make([]Step, n)
+	for i := range dec.Steps {
+		op := StepOp(readByte())
+		if op > DotOp {
+			return fmt.Errorf("%w: unknown step %d", ErrJobFormat, op)
+		}
+		dec.Steps[i] = Step{Op: op, P: f32.Vec2{readFloat(), readFloat()}}
+	}
+	*j = dec
+	return nil
+}
@@ -0,0 +1,304 @@
+package engrave
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// MicroQR returns a command that engraves content as the smallest
+// Micro QR symbol with the error correction level, with scale strokes
+// per module. Micro QR symbols support the Low, Medium and High
+// levels, corresponding to L, M and Q. Only Low admits the M1 symbol,
+// which detects but doesn't correct errors.
+func MicroQR(strokeWidth float32, scale int, level qrcode.RecoveryLevel, content []byte) (Command, error) {
+	bitmap, err := microQR(level, content)
+	if err != nil {
+		return nil, err
+	}
+	return bitmapCmd{
+		strokeWidth: strokeWidth,
+		scale:       scale,
+		bitmap:      bitmap,
+	}, nil
+}
+
+// mqrSymbol describes a Micro QR symbol version and error correction
+// level.
+type mqrSymbol struct {
+	// version is 1 to 4 for M1 to M4.
+	version int
+	level   qrcode.RecoveryLevel
+	// number is the symbol number of the format information.
+	number int
+	// dataBits is the data capacity. M1 and M3 symbols end in a
+	// 4-bit data codeword.
+	dataBits int
+	// ec is the number of error correction codewords.
+	ec int
+}
+
+var mqrSymbols = []mqrSymbol{
+	{1, qrcode.Low, 0, 20, 2},
+	{2, qrcode.Low, 1, 40, 5},
+	{2, qrcode.Medium, 2, 32, 6},
+	{3, qrcode.Low, 3, 84, 6},
+	{3, qrcode.Medium, 4, 68, 8},
+	{4, qrcode.Low, 5, 128, 8},
+	{4, qrcode.Medium, 6, 112, 10},
+	{4, qrcode.High, 7, 80, 14},
+}
+
+func (s mqrSymbol) size() int {
+	return 2*s.version + 9
+}
+
+// Micro QR encoding modes.
+const (
+	mqrNumeric = iota
+	mqrAlphanumeric
+	mqrByte
+)
+
+const mqrAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// mqrCountBits lists the character count bits for each mode and
+// version, or 0 if the mode is not supported by the version.
+var mqrCountBits = [3][4]int{
+	mqrNumeric:      {3, 4, 5, 6},
+	mqrAlphanumeric: {0, 3, 4, 5},
+	mqrByte:         {0, 0, 4, 5},
+}
+
+var errLevel = errors.New("engrave: unsupported error correction level")
+
+func microQR(level qrcode.RecoveryLevel, content []byte) ([][]bool, error) {
+	if level == qrcode.Highest {
+		return nil, errLevel
+	}
+	mode := mqrNumeric
+	for _, c := range content {
+		switch {
+		case '0' <= c && c <= '9':
+		case strings.IndexByte(mqrAlphabet, c) != -1:
+			if mode < mqrAlphanumeric {
+				mode = mqrAlphanumeric
+			}
+		default:
+			mode = mqrByte
+		}
+	}
+	for _, sym := range mqrSymbols {
+		if sym.level != level {
+			continue
+		}
+		bits, ok := mqrEncode(sym, mode, content)
+		if !ok {
+			continue
+		}
+		return mqrBitmap(sym, mqrCodewords(sym, bits)), nil
+	}
+	return nil, ErrTooLarge
+}
+
+// bitBuffer is a sequence of bits.
+type bitBuffer []bool
+
+func (b *bitBuffer) append(v uint, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v&(1<<i) != 0)
+	}
+}
+
+// mqrEncode encodes content in a single segment, or reports false if
+// it doesn't fit the symbol.
+func mqrEncode(sym mqrSymbol, mode int, content []byte) (bitBuffer, bool) {
+	countBits := mqrCountBits[mode][sym.version-1]
+	if countBits == 0 || len(content) >= 1<<countBits {
+		return nil, false
+	}
+	var bits bitBuffer
+	// The mode indicator is version-1 bits wide.
+	bits.append(uint(mode), sym.version-1)
+	bits.append(uint(len(content)), countBits)
+	switch mode {
+	case mqrNumeric:
+		for i := 0; i < len(content); i += 3 {
+			n := len(content) - i
+			if n > 3 {
+				n = 3
+			}
+			v := uint(0)
+			for _, c := range content[i : i+n] {
+				v = v*10 + uint(c-'0')
+			}
+			bits.append(v, 3*n+1)
+		}
+	case mqrAlphanumeric:
+		for i := 0; i < len(content); i += 2 {
+			v := uint(strings.IndexByte(mqrAlphabet, content[i]))
+			if i+1 < len(content) {
+				v = v*45 + uint(strings.IndexByte(mqrAlphabet, content[i+1]))
+				bits.append(v, 11)
+			} else {
+				bits.append(v, 6)
+			}
+		}
+	case mqrByte:
+		for _, c := range content {
+			bits.append(uint(c), 8)
+		}
+	}
+	if len(bits) > sym.dataBits {
+		return nil, false
+	}
+	// Terminator, truncated if the symbol is full.
+	term := 2*sym.version + 1
+	if rem := sym.dataBits - len(bits); term > rem {
+		term = rem
+	}
+	bits.append(0, term)
+	// Pad to a codeword boundary, and fill with pad codewords.
+	for len(bits)%8 != 0 && len(bits) < sym.dataBits {
+		bits = append(bits, false)
+	}
+	pads := []uint{0xec, 0x11}
+	for i := 0; len(bits)+8 <= sym.dataBits; i++ {
+		bits.append(pads[i%2], 8)
+	}
+	// The final 4-bit codeword of M1 and M3 symbols.
+	for len(bits) < sym.dataBits {
+		bits = append(bits, false)
+	}
+	return bits, true
+}
+
+// mqrCodewords splits data bits into codewords and appends the error
+// correction codewords. A final 4-bit data codeword occupies the high
+// bits of its byte.
+func mqrCodewords(sym mqrSymbol, bits bitBuffer) []byte {
+	data := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			data[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	ec := qrField.rsEncode(data, sym.ec, 0)
+	return append(data, ec...)
+}
+
+// mqrFunction reports whether a module belongs to the finder pattern,
+// its separator, the timing patterns or the format information.
+func mqrFunction(x, y int) bool {
+	return x <= 8 && y <= 8 || x == 0 || y == 0
+}
+
+// mqrMasks are the Micro QR data mask conditions.
+var mqrMasks = [4]func(x, y int) bool{
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return (y/2+x/3)%2 == 0 },
+	func(x, y int) bool { return (y*x%2+y*x%3)%2 == 0 },
+	func(x, y int) bool { return ((y+x)%2+y*x%3)%2 == 0 },
+}
+
+// mqrFormat computes the format information for a symbol number and
+// mask.
+func mqrFormat(number, mask int) uint {
+	data := uint(number<<2 | mask)
+	// BCH(15,5) code.
+	const gen = 0x537
+	rem := data << 10
+	for i := 14; i >= 10; i-- {
+		if rem&(1<<i) != 0 {
+			rem ^= gen << (i - 10)
+		}
+	}
+	return (data<<10 | rem) ^ 0x4445
+}
+
+func mqrBitmap(sym mqrSymbol, cws []byte) [][]bool {
+	size := sym.size()
+	bitmap := make([][]bool, size)
+	for y := range bitmap {
+		bitmap[y] = make([]bool, size)
+	}
+	// Finder pattern.
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 7; x++ {
+			ring := x == 0 || x == 6 || y == 0 || y == 6
+			center := 2 <= x && x <= 4 && 2 <= y && y <= 4
+			bitmap[y][x] = ring || center
+		}
+	}
+	// Timing patterns.
+	for i := 8; i < size; i++ {
+		bitmap[0][i] = i%2 == 0
+		bitmap[i][0] = i%2 == 0
+	}
+	// Data bits, in the order of the codewords. The half codeword of
+	// M1 and M3 symbols contributes only its 4 high bits.
+	var bits bitBuffer
+	for i, c := range cws {
+		n := 8
+		if i == sym.dataBits/8 && sym.dataBits%8 != 0 {
+			n = 4
+			c >>= 4
+		}
+		bits.append(uint(c), n)
+	}
+	var data [][2]int
+	up := true
+	for x := size - 1; x > 0; x -= 2 {
+		for i := 0; i < size; i++ {
+			y := i
+			if up {
+				y = size - 1 - i
+			}
+			for _, cx := range []int{x, x - 1} {
+				if !mqrFunction(cx, y) {
+					data = append(data, [2]int{cx, y})
+				}
+			}
+		}
+		up = !up
+	}
+	for i, p := range data {
+		bitmap[p[1]][p[0]] = i < len(bits) && bits[i]
+	}
+	// Select the mask with the most dark modules along the right and
+	// bottom edges.
+	best, bestScore := 0, -1
+	for m, mask := range mqrMasks {
+		sum1, sum2 := 0, 0
+		for i := 1; i < size; i++ {
+			if bitmap[i][size-1] != mask(size-1, i) {
+				sum1++
+			}
+			if bitmap[size-1][i] != mask(i, size-1) {
+				sum2++
+			}
+		}
+		if sum1 > sum2 {
+			sum1, sum2 = sum2, sum1
+		}
+		if score := sum1*16 + sum2; score > bestScore {
+			best, bestScore = m, score
+		}
+	}
+	for _, p := range data {
+		if mqrMasks[best](p[0], p[1]) {
+			bitmap[p[1]][p[0]] = !bitmap[p[1]][p[0]]
+		}
+	}
+	// Format information, from the most significant bit along row 8
+	// and then up column 8.
+	format := mqrFormat(sym.number, best)
+	for i := 0; i < 8; i++ {
+		bitmap[8][i+1] = format&(1<<(14-i)) != 0
+	}
+	for i := 0; i < 7; i++ {
+		bitmap[7-i][8] = format&(1<<(6-i)) != 0
+	}
+	return bitmap
+}
@@ -0,0 +1,259 @@
+package engrave
+
+import (
+	"math"
+
+	"golang.org/x/image/math/f32"
+)
+
+// stroke is a connected sequence of lines, or a single dot.
+type stroke struct {
+	points []f32.Vec2
+	dot    bool
+}
+
+func (s stroke) start(rev bool) f32.Vec2 {
+	if rev {
+		return s.points[len(s.points)-1]
+	}
+	return s.points[0]
+}
+
+func (s stroke) end(rev bool) f32.Vec2 {
+	return s.start(!rev)
+}
+
+// recorder is a DotProgram that splits an engraving into strokes.
+type recorder struct {
+	strokes []stroke
+	pos     f32.Vec2
+	drawing bool
+}
+
+func (r *recorder) Move(p f32.Vec2) {
+	r.pos = p
+	r.drawing = false
+}
+
+func (r *recorder) Line(p f32.Vec2) {
+	if !r.drawing {
+		r.strokes = append(r.strokes, stroke{points: []f32.Vec2{r.pos}})
+		r.drawing = true
+	}
+	s := &r.strokes[len(r.strokes)-1]
+	s.points = append(s.points, p)
+	r.pos = p
+}
+
+func (r *recorder) Dot(p f32.Vec2) {
+	r.strokes = append(r.strokes, stroke{points: []f32.Vec2{p}, dot: true})
+	r.pos = p
+	r.drawing = false
+}
+
+// optimizedCmd engraves strokes in order, reversing some of them.
+type optimizedCmd struct {
+	strokes []stroke
+	rev     []bool
+}
+
+func (o *optimizedCmd) Engrave(p Program) {
+	for i, s := range o.strokes {
+		if s.dot {
+			Dot(p, s.points[0])
+			continue
+		}
+		n := len(s.points)
+		p.Move(s.start(o.rev[i]))
+		for j := 1; j < n; j++ {
+			k := j
+			if o.rev[i] {
+				k = n - 1 - j
+			}
+			p.Line(s.points[k])
+		}
+	}
+}
+
+// Optimize records the engraving of c and returns a command that
+// engraves the same lines, but with its strokes reordered and
+// possibly reversed to reduce the distance travelled between them.
+// The tool is assumed to start at the origin. Moves that are not
+// followed by lines are dropped, and dots are struck as dots.
+func Optimize(c Command) Command {
+	rec := new(recorder)
+	c.Engrave(rec)
+	strokes := rec.strokes
+	order, rev := nearestNeighbour(strokes)
+	twoOpt(strokes, order, rev)
+	o := &optimizedCmd{
+		strokes: make([]stroke, len(order)),
+		rev:     make([]bool, len(order)),
+	}
+	for i, idx := range order {
+		o.strokes[i] = strokes[idx]
+		o.rev[i] = rev[idx]
+	}
+	return o
+}
+
+// Travel returns the total distance of the moves of c, starting from
+// the origin. It equals the MoveLength of Measure(c).
+func Travel(c Command) float32 {
+	return Measure(c).MoveLength
+}
+
+func dist(a, b f32.Vec2) float32 {
+	dx, dy := a[0]-b[0], a[1]-b[1]
+	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+}
+
+// gridCell is the size in millimeters of the cells of the spatial
+// index used by nearestNeighbour.
+const gridCell = 2
+
+// nearestNeighbour orders strokes by repeatedly choosing the stroke
+// with the end point closest to the current position.
+func nearestNeighbour(strokes []stroke) (order []int, rev []bool) {
+	type endpoint struct {
+		stroke int
+		rev    bool
+	}
+	grid := make(map[[2]int][]endpoint)
+	cellOf := func(p f32.Vec2) [2]int {
+		return [2]int{
+			int(math.Floor(float64(p[0] / gridCell))),
+			int(math.Floor(float64(p[1] / gridCell))),
+		}
+	}
+	// The bounds of the cells, including the origin.
+	var minc, maxc [2]int
+	for i, s := range strokes {
+		for _, r := range []bool{false, true} {
+			c := cellOf(s.start(r))
+			grid[c] = append(grid[c], endpoint{i, r})
+			for j := range c {
+				if c[j] < minc[j] {
+					minc[j] = c[j]
+				}
+				if c[j] > maxc[j] {
+					maxc[j] = c[j]
+				}
+			}
+		}
+	}
+	// Every cell is covered by a ring of this size around any cell.
+	maxRing := 0
+	for j := range minc {
+		if d := maxc[j] - minc[j]; d > maxRing {
+			maxRing = d
+		}
+	}
+	rev = make([]bool, len(strokes))
+	used := make([]bool, len(strokes))
+	remaining := len(strokes)
+	var pos f32.Vec2
+	for remaining > 0 {
+		center := cellOf(pos)
+		best, bestDist := endpoint{stroke: -1}, float32(math.Inf(1))
+		// Search rings of cells around the position until no closer
+		// point can be found.
+		for ring := 0; ; ring++ {
+			for y := center[1] - ring; y <= center[1]+ring; y++ {
+				for x := center[0] - ring; x <= center[0]+ring; x++ {
+					if x != center[0]-ring && x != center[0]+ring &&
+						y != center[1]-ring && y != center[1]+ring {
+						continue
+					}
+					c := [2]int{x, y}
+					eps := grid[c]
+					for j := 0; j < len(eps); j++ {
+						ep := eps[j]
+						if used[ep.stroke] {
+							// Prune endpoints of used strokes.
+							eps[j] = eps[len(eps)-1]
+							eps = eps[:len(eps)-1]
+							j--
+							continue
+						}
+						if d := dist(pos, strokes[ep.stroke].start(ep.rev)); d < bestDist {
+							best, bestDist = ep, d
+						}
+					}
+					if len(eps) == 0 {
+						delete(grid, c)
+					} else {
+						grid[c] = eps
+					}
+				}
+			}
+			// Points outside the rings searched so far are at least
+			// ring*gridCell away.
+			if best.stroke != -1 && (bestDist <= float32(ring*gridCell) || ring > maxRing) {
+				break
+			}
+		}
+		used[best.stroke] = true
+		remaining--
+		order = append(order, best.stroke)
+		rev[best.stroke] = best.rev
+		pos = strokes[best.stroke].end(best.rev)
+	}
+	return order, rev
+}
+
+// twoOptWindow limits the length of the sequences of strokes
+// considered for reversal by twoOpt.
+const twoOptWindow = 64
+
+// twoOpt improves an ordering of strokes by reversing sequences of
+// them whenever that reduces the travel distance. Reversing a sequence
+// reverses the direction of each of its strokes as well, so the
+// distance travelled inside the sequence is unchanged.
+func twoOpt(strokes []stroke, order []int, rev []bool) {
+	n := len(order)
+	start := func(i int) f32.Vec2 {
+		return strokes[order[i]].start(rev[order[i]])
+	}
+	end := func(i int) f32.Vec2 {
+		if i < 0 {
+			return f32.Vec2{}
+		}
+		return strokes[order[i]].end(rev[order[i]])
+	}
+	const maxPasses = 10
+	for pass := 0; pass < maxPasses; pass++ {
+		improved := false
+		for i := 0; i < n; i++ {
+			prev := end(i - 1)
+			jmax := i + twoOptWindow
+			if jmax > n {
+				jmax = n
+			}
+			for j := i; j < jmax; j++ {
+				before := dist(prev, start(i))
+				after := dist(prev, end(j))
+				if j+1 < n {
+					next := start(j + 1)
+					before += dist(end(j), next)
+					after += dist(start(i), next)
+				}
+				// Require a minimum gain to avoid cycling because of
+				// rounding.
+				if after >= before-1e-3 {
+					continue
+				}
+				for a, b := i, j; a < b; a, b = a+1, b-1 {
+					order[a], order[b] = order[b], order[a]
+				}
+				for k := i; k <= j; k++ {
+					rev[order[k]] = !rev[order[k]]
+				}
+				improved = true
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package engrave
+
+// gf256 is a Galois field of 256 elements for computing Reed-Solomon
+// error correction codewords.
+type gf256 struct {
+	exp [510]byte
+	log [256]byte
+}
+
+// Fields of Data Matrix and (Micro) QR codes, with their generator
+// polynomials.
+var (
+	dataMatrixField = newGF256(0x12d)
+	qrField         = newGF256(0x11d)
+)
+
+func newGF256(poly int) *gf256 {
+	f := new(gf256)
+	x := 1
+	for i := 0; i < 255; i++ {
+		f.exp[i] = byte(x)
+		f.exp[i+255] = byte(x)
+		f.log[x] = byte(i)
+		x <<= 1
+		if x >= 256 {
+			x ^= poly
+		}
+	}
+	return f
+}
+
+func (f *gf256) mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[int(f.log[a])+int(f.log[b])]
+}
+
+// rsEncode returns n error correction codewords for data, using the
+// generator polynomial with roots α^first, ..., α^(first+n-1).
+func (f *gf256) rsEncode(data []byte, n, first int) []byte {
+	// Compute the generator polynomial, highest degree first.
+	gen := []byte{1}
+	for i := 0; i < n; i++ {
+		root := f.exp[(first+i)%255]
+		next := make([]byte, len(gen)+1)
+		for j, c := range gen {
+			next[j] ^= c
+			next[j+1] ^= f.mul(c, root)
+		}
+		gen = next
+	}
+	// Divide data(x)·x^n by the generator.
+	rem := make([]byte, n)
+	for _, d := range data {
+		coef := d ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for j := 0; j < n; j++ {
+			rem[j] ^= f.mul(gen[j+1], coef)
+		}
+	}
+	return rem
+}
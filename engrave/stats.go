@@ -0,0 +1,70 @@
+package engrave
+
+import (
+	"math"
+
+	"golang.org/x/image/math/f32"
+)
+
+// Stats is a Program that measures an engraving, for estimating its
+// duration and the wear of the engraver. The tool is assumed to start
+// at the origin.
+type Stats struct {
+	// LineLength is the total length of lines.
+	LineLength float32
+	// MoveLength is the total distance of every move with the tool
+	// raised, including moves that are not followed by a line. It
+	// excludes the distance covered by lines, so the tool travels
+	// MoveLength+LineLength in total.
+	MoveLength float32
+	// Lines counts the lines.
+	Lines int
+	// Strokes counts the sequences of connected lines.
+	Strokes int
+	// Turns counts the changes of direction between connected lines
+	// sharper than turnAngle.
+	Turns int
+
+	pos     f32.Vec2
+	dir     f32.Vec2
+	drawing bool
+}
+
+// turnAngle is the smallest change of direction counted as a turn.
+const turnAngle = 15 * math.Pi / 180
+
+// Measure returns the statistics of an engraving.
+func Measure(c Command) Stats {
+	s := new(Stats)
+	c.Engrave(s)
+	return *s
+}
+
+func (s *Stats) Move(p f32.Vec2) {
+	s.MoveLength += dist(s.pos, p)
+	s.pos = p
+	s.drawing = false
+}
+
+func (s *Stats) Line(p f32.Vec2) {
+	d := dist(s.pos, p)
+	s.LineLength += d
+	s.Lines++
+	if !s.drawing {
+		s.Strokes++
+		s.drawing = true
+		s.dir = f32.Vec2{}
+	}
+	if d == 0 {
+		return
+	}
+	dir := f32.Vec2{(p[0] - s.pos[0]) / d, (p[1] - s.pos[1]) / d}
+	if s.dir != (f32.Vec2{}) {
+		cos := dir[0]*s.dir[0] + dir[1]*s.dir[1]
+		if cos < float32(math.Cos(turnAngle)) {
+			s.Turns++
+		}
+	}
+	s.dir = dir
+	s.pos = p
+}
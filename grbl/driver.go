@@ -0,0 +1,397 @@
+// package grbl implements a driver for engravers, CNC mills and
+// dot-peen markers running the GRBL firmware or a compatible G-code
+// interpreter.
+package grbl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tarm/serial"
+	"golang.org/x/image/math/f32"
+	"seedhammer.com/engrave"
+)
+
+// Program is an engrave.Program that generates G-code. Like
+// mjolnir.Program, the design is engraved once to count the number
+// of G-code lines and once more, after Prepare, concurrently with
+// Engrave to stream them.
+//
+// Coordinates are in millimeters and sent unchanged. The machine's
+// work coordinate system must place the origin in the top left corner
+// of the bed, with the Y axis pointing towards the front.
+type Program struct {
+	DryRun bool
+	// Feed is the engraving feed rate in mm/min.
+	Feed float32
+	// PlungeFeed is the feed rate in mm/min for lowering the tool.
+	PlungeFeed float32
+	// Depth is the engraving depth below the surface.
+	Depth float32
+	// SafeZ is the tool height above the surface during moves.
+	SafeZ float32
+	End   f32.Vec2
+	lines chan string
+	down  bool
+	count int
+	sent  int
+}
+
+const (
+	defaultFeed       = 300
+	defaultPlungeFeed = 100
+	defaultDepth      = 0.1
+	defaultSafeZ      = 2
+)
+
+// rxBufferSize is the size of the serial receive buffer of GRBL.
+const rxBufferSize = 128
+
+// Real-time commands. They bypass the receive buffer.
+const (
+	softResetCmd  = 0x18
+	feedHoldCmd   = '!'
+	cycleStartCmd = '~'
+)
+
+func Open(dev string) (io.ReadWriteCloser, error) {
+	const baudRate = 115200
+
+	var devices []string
+	if dev != "" {
+		devices = append(devices, dev)
+	} else {
+		switch runtime.GOOS {
+		case "windows":
+			devices = append(devices, "COM3")
+		case "linux":
+			devices = append(devices, "/dev/ttyACM0", "/dev/ttyUSB0", "/dev/ttyUSB1")
+		}
+	}
+	if len(devices) == 0 {
+		return nil, errors.New("no device specified")
+	}
+	var firstErr error
+	for _, dev := range devices {
+		c := &serial.Config{Name: dev, Baud: baudRate}
+		s, err := serial.OpenPort(c)
+		if err == nil {
+			return s, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+var ErrCancelled = errors.New("cancelled")
+
+// Engrave resets the machine, homes it and streams prog while
+// reporting progress. Receiving true from pause stops streaming lines
+// once the buffered lines complete, and receiving false continues.
+// Closing quit stops the machine and results in ErrCancelled.
+func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, pause <-chan bool, quit <-chan struct{}) (eerr error) {
+	defer func() {
+		for i := prog.sent; i < prog.count; i++ {
+			<-prog.lines
+		}
+	}()
+	done := make(chan struct{})
+	defer close(done)
+	replies := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		s := bufio.NewScanner(dev)
+		for s.Scan() {
+			line := strings.TrimSpace(s.Text())
+			if line == "" {
+				continue
+			}
+			select {
+			case replies <- line:
+			case <-done:
+				return
+			}
+		}
+		err := s.Err()
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		readErr <- err
+	}()
+	write := func(data string) {
+		if eerr != nil {
+			return
+		}
+		_, eerr = io.WriteString(dev, data)
+	}
+	// await waits for the next reply from the machine, or for a
+	// pause state from pause. The returned bool reports whether a
+	// pause state was received.
+	await := func(pause <-chan bool) (string, bool, bool) {
+		if eerr != nil {
+			return "", false, false
+		}
+		select {
+		case line := <-replies:
+			if strings.HasPrefix(line, "ALARM:") {
+				eerr = fmt.Errorf("grbl: %s", line)
+			}
+			return line, false, false
+		case p := <-pause:
+			return "", p, true
+		case err := <-readErr:
+			eerr = err
+		case <-quit:
+			// Stop motion before discarding the buffered commands.
+			write(string([]byte{feedHoldCmd, softResetCmd}))
+			if eerr == nil {
+				eerr = ErrCancelled
+			}
+		}
+		return "", false, false
+	}
+	// reply waits for the next reply from the machine.
+	reply := func() string {
+		line, _, _ := await(nil)
+		return line
+	}
+	// stream sends n lines from next while keeping the receive buffer
+	// from overflowing, and waits for every line to be acknowledged.
+	// Pausing by pause holds the motion of the machine, and streaming
+	// stops until continued.
+	stream := func(n int, next func() string, acked func(completed int), pause <-chan bool) {
+		var pending []int
+		buffered, sent, completed := 0, 0, 0
+		line := ""
+		paused := false
+		setPaused := func(p bool) {
+			if p == paused {
+				return
+			}
+			paused = p
+			// A feed hold decelerates to a stop, even in the middle
+			// of a buffered motion, and a cycle start resumes it.
+			if paused {
+				write(string([]byte{feedHoldCmd}))
+			} else {
+				write(string([]byte{cycleStartCmd}))
+			}
+		}
+		// Don't return while held, or the remaining motions would
+		// never complete.
+		for (completed < n || paused) && eerr == nil {
+			select {
+			case p := <-pause:
+				setPaused(p)
+				continue
+			default:
+			}
+			if sent < n && !paused {
+				if line == "" {
+					line = next() + "\n"
+					if len(line) > rxBufferSize {
+						eerr = fmt.Errorf("grbl: line too long: %q", line)
+						return
+					}
+				}
+				if buffered+len(line) <= rxBufferSize {
+					write(line)
+					sent++
+					buffered += len(line)
+					pending = append(pending, len(line))
+					line = ""
+					continue
+				}
+			}
+			r, p, ok := await(pause)
+			if ok {
+				setPaused(p)
+				continue
+			}
+			if len(pending) == 0 || r != "ok" && !strings.HasPrefix(r, "error:") {
+				// Status reports and messages.
+				continue
+			}
+			buffered -= pending[0]
+			pending = pending[1:]
+			completed++
+			if code, ok := strings.CutPrefix(r, "error:"); ok {
+				c, err := strconv.Atoi(code)
+				if err != nil {
+					eerr = fmt.Errorf("grbl: invalid reply: %q", r)
+					return
+				}
+				eerr = &Error{Code: c}
+				return
+			}
+			if acked != nil {
+				acked(completed)
+			}
+		}
+	}
+	send := func(lines ...string) {
+		i := 0
+		stream(len(lines), func() string {
+			l := lines[i]
+			i++
+			return l
+		}, nil, nil)
+	}
+
+	// Reset and wait for the welcome message.
+	write(string([]byte{softResetCmd}))
+	for eerr == nil {
+		if strings.HasPrefix(reply(), "Grbl ") {
+			break
+		}
+	}
+	prog.setDefaults()
+	send("$H")
+	if errors.Is(eerr, errHomingDisabled) {
+		// Machines without limit switches start at their origin.
+		eerr = nil
+	}
+	// Millimeters, absolute positioning, feed rates in units per
+	// minute.
+	send("G21", "G90", "G94", "G0 Z"+coord(prog.SafeZ))
+
+	completed := 0
+	stream(prog.count, func() string {
+		prog.sent++
+		return <-prog.lines
+	}, func(c int) {
+		completed = c
+		if progress == nil {
+			return
+		}
+		// Don't spam the progress channel.
+		if completed%10 != 0 && completed < prog.count {
+			return
+		}
+		select {
+		case <-progress:
+		default:
+		}
+		progress <- float32(completed) / float32(prog.count)
+	}, pause)
+	if eerr == nil {
+		// Move out of the way and wait for the motion to complete.
+		send(
+			"G0 Z"+coord(prog.SafeZ),
+			"G0 X"+coord(prog.End[0])+" Y"+coord(prog.End[1]),
+			"G4 P0",
+		)
+	}
+	return eerr
+}
+
+// errHomingDisabled is the GRBL error code for homing without
+// homing switches.
+var errHomingDisabled = &Error{Code: 5}
+
+// Error represents an error reported by the machine.
+type Error struct {
+	Code int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("grbl: error:%d", e.Code)
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func (p *Program) setDefaults() {
+	if p.Feed == 0 {
+		p.Feed = defaultFeed
+	}
+	if p.PlungeFeed == 0 {
+		p.PlungeFeed = defaultPlungeFeed
+	}
+	if p.Depth == 0 {
+		p.Depth = defaultDepth
+	}
+	if p.SafeZ == 0 {
+		p.SafeZ = defaultSafeZ
+	}
+}
+
+// Timing model of GRBL machines.
+const (
+	// rapidFeed is the feed rate in mm/min of G0 moves, GRBL's
+	// default maximum rate.
+	rapidFeed = 500
+	// setupDuration covers the reset and homing.
+	setupDuration = 10 * time.Second
+)
+
+// Estimate returns the approximate duration of engraving a design
+// measured by s, including setup. It ignores acceleration.
+func (p *Program) Estimate(s engrave.Stats) time.Duration {
+	q := *p
+	q.setDefaults()
+	minutes := func(length, feed float32) time.Duration {
+		return time.Duration(float64(length/feed) * float64(time.Minute))
+	}
+	if q.DryRun {
+		// Lines are moves.
+		return setupDuration + minutes(s.MoveLength+s.LineLength, rapidFeed)
+	}
+	// Every stroke lowers the tool from the safe height and raises it
+	// again.
+	z := float32(s.Strokes) * (q.SafeZ + q.Depth)
+	return setupDuration + minutes(s.MoveLength+z, rapidFeed) +
+		minutes(z, q.PlungeFeed) + minutes(s.LineLength, q.Feed)
+}
+
+// coord formats a coordinate with micrometer precision.
+func coord(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', 3, 32)
+}
+
+func (p *Program) line(l string) {
+	if p.lines != nil {
+		p.lines <- l
+	} else {
+		p.count++
+	}
+}
+
+// Prepare readies the program for streaming.
+func (p *Program) Prepare() {
+	p.setDefaults()
+	p.lines = make(chan string)
+	p.down = false
+}
+
+func (p *Program) Move(to f32.Vec2) {
+	if p.down {
+		p.line("G0 Z" + coord(p.SafeZ))
+		p.down = false
+	}
+	p.line("G0 X" + coord(to[0]) + " Y" + coord(to[1]))
+}
+
+func (p *Program) Line(to f32.Vec2) {
+	if p.DryRun {
+		p.Move(to)
+		return
+	}
+	if !p.down {
+		p.line("G1 Z" + coord(-p.Depth) + " F" + coord(p.PlungeFeed))
+		p.line("G1 X" + coord(to[0]) + " Y" + coord(to[1]) + " F" + coord(p.Feed))
+		p.down = true
+		return
+	}
+	p.line("G1 X" + coord(to[0]) + " Y" + coord(to[1]))
+}
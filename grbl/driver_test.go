@@ -0,0 +1,239 @@
+package grbl
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/image/math/f32"
+	"seedhammer.com/engrave"
+)
+
+func TestEndToEnd(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	prog := &Program{
+		End: f32.Vec2{5, 6},
+	}
+	const n = 2000
+	design := func() {
+		for i := 0; i < n; i++ {
+			prog.Line(f32.Vec2{float32(i), float32(i) * 2})
+			prog.Line(f32.Vec2{float32(i) * 4, float32(i) * 3})
+			prog.Move(f32.Vec2{float32(i), float32(i)})
+		}
+	}
+	design()
+	prog.Prepare()
+	engraveErr := make(chan error)
+	progress := make(chan float32, 1)
+	go func() {
+		engraveErr <- Engrave(s, prog, progress, nil, nil)
+	}()
+	design()
+	if err := <-engraveErr; err != nil {
+		t.Fatal(err)
+	}
+	if p := <-progress; p != 1 {
+		t.Errorf("final progress %v, want 1", p)
+	}
+	// Homing, the design and the end position.
+	if got, want := len(s.Cmds), 1+3*n+1; got != want {
+		t.Fatalf("executed %d motions, want %d", got, want)
+	}
+	want := []Cmd{
+		{MoveTo, 0, 0},
+		{LineTo, 0, 0},
+		{LineTo, 0, 0},
+		{MoveTo, 0, 0},
+		{LineTo, 1, 2},
+		{LineTo, 4, 3},
+		{MoveTo, 1, 1},
+	}
+	for i, c := range want {
+		if s.Cmds[i] != c {
+			t.Errorf("motion %d is %v, want %v", i, s.Cmds[i], c)
+		}
+	}
+	if got, want := s.Cmds[len(s.Cmds)-1], (Cmd{MoveTo, 5, 6}); got != want {
+		t.Errorf("end position %v, want %v", got, want)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	s := engrave.Stats{
+		LineLength: 300,
+		MoveLength: 500,
+		Strokes:    10,
+	}
+	prog := &Program{Feed: 300, PlungeFeed: 100, Depth: 0.5, SafeZ: 2.5}
+	// 10 strokes lower the tool 3 mm and raise it again.
+	const z = 10 * 3
+	want := setupDuration + (500+z)*time.Minute/rapidFeed + z*time.Minute/100 + time.Minute
+	if got := prog.Estimate(s); !closeDuration(got, want) {
+		t.Errorf("estimated %v, want %v", got, want)
+	}
+	// Dry runs move instead of engraving lines.
+	prog.DryRun = true
+	if got, want := prog.Estimate(s), setupDuration+800*time.Minute/rapidFeed; !closeDuration(got, want) {
+		t.Errorf("dry run estimated %v, want %v", got, want)
+	}
+}
+
+func closeDuration(d1, d2 time.Duration) bool {
+	d := d1 - d2
+	return -time.Millisecond < d && d < time.Millisecond
+}
+
+func TestPause(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	prog := &Program{}
+	design := func() {
+		for i := 0; i < 100; i++ {
+			prog.Line(f32.Vec2{float32(i), 0})
+		}
+	}
+	design()
+	prog.Prepare()
+	pause := make(chan bool, 1)
+	pause <- true
+	progress := make(chan float32, 1)
+	engraveErr := make(chan error, 1)
+	go func() {
+		engraveErr <- Engrave(s, prog, progress, pause, nil)
+	}()
+	go design()
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case p := <-progress:
+		t.Fatalf("paused engraving progressed to %v", p)
+	case err := <-engraveErr:
+		t.Fatalf("paused engraving returned %v", err)
+	default:
+	}
+	pause <- false
+	if err := <-engraveErr; err != nil {
+		t.Fatal(err)
+	}
+	if p := <-progress; p != 1 {
+		t.Errorf("final progress %v, want 1", p)
+	}
+}
+
+func TestFeedHold(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+	rec := &recorder{ReadWriter: s}
+
+	prog := &Program{}
+	design := func() {
+		for i := 0; i < 1000; i++ {
+			prog.Line(f32.Vec2{float32(i), 0})
+		}
+	}
+	design()
+	prog.Prepare()
+	pause := make(chan bool, 1)
+	progress := make(chan float32, 1)
+	engraveErr := make(chan error, 1)
+	go func() {
+		engraveErr <- Engrave(rec, prog, progress, pause, nil)
+	}()
+	go design()
+	<-progress
+	pause <- true
+	// Discard the progress of the lines completed before the hold.
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-progress:
+	default:
+	}
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case p := <-progress:
+		t.Fatalf("held engraving progressed to %v", p)
+	case err := <-engraveErr:
+		t.Fatalf("held engraving returned %v", err)
+	default:
+	}
+	pause <- false
+	if err := <-engraveErr; err != nil {
+		t.Fatal(err)
+	}
+	written := rec.String()
+	hold := strings.IndexByte(written, feedHoldCmd)
+	if hold == -1 {
+		t.Fatal("no feed hold sent")
+	}
+	if strings.IndexByte(written[hold:], cycleStartCmd) == -1 {
+		t.Error("no cycle start sent after the feed hold")
+	}
+}
+
+// recorder records the data written to a device.
+type recorder struct {
+	io.ReadWriter
+
+	mu      sync.Mutex
+	written strings.Builder
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.mu.Lock()
+	r.written.Write(data)
+	r.mu.Unlock()
+	return r.ReadWriter.Write(data)
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.written.String()
+}
+
+func TestCancel(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	prog := &Program{}
+	design := func() {
+		for i := 0; i < 1000; i++ {
+			prog.Line(f32.Vec2{float32(i), 0})
+		}
+	}
+	design()
+	prog.Prepare()
+	quit := make(chan struct{})
+	close(quit)
+	engraveErr := make(chan error)
+	go func() {
+		engraveErr <- Engrave(s, prog, nil, nil, quit)
+	}()
+	design()
+	if err := <-engraveErr; !errors.Is(err, ErrCancelled) {
+		t.Errorf("got error %v, want %v", err, ErrCancelled)
+	}
+}
+
+func TestMachineError(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	prog := new(Program)
+	prog.line("G38.2 Z-10")
+	prog.Prepare()
+	engraveErr := make(chan error)
+	go func() {
+		engraveErr <- Engrave(s, prog, nil, nil, nil)
+	}()
+	prog.line("G38.2 Z-10")
+	if err := <-engraveErr; !errors.Is(err, &Error{Code: 20}) {
+		t.Errorf("got error %v, want error:20", err)
+	}
+}
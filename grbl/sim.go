@@ -0,0 +1,194 @@
+package grbl
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Simulator is an in-process GRBL machine. It enforces the size of
+// the receive buffer and records the motion commands it executes.
+type Simulator struct {
+	// Cmds lists the executed motions. It must not be accessed
+	// concurrently with reads and writes.
+	Cmds []Cmd
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	rx     []byte
+	out    bytes.Buffer
+	held   bool
+	closed bool
+	pos    [3]float32
+	motion string
+}
+
+// Cmd is a motion in millimeters.
+type Cmd struct {
+	Type CmdType
+	X, Y float32
+}
+
+type CmdType int
+
+const (
+	MoveTo CmdType = iota
+	LineTo
+)
+
+const simVersion = "Grbl 1.1h ['$' for help]"
+
+func NewSimulator() *Simulator {
+	s := new(Simulator)
+	s.cond = sync.NewCond(&s.mu)
+	go s.run()
+	return s
+}
+
+// run executes the lines of the receive buffer.
+func (s *Simulator) run() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		idx := bytes.IndexByte(s.rx, '\n')
+		for !s.closed && (idx == -1 || s.held) {
+			s.cond.Wait()
+			idx = bytes.IndexByte(s.rx, '\n')
+		}
+		if s.closed {
+			return
+		}
+		line := string(s.rx[:idx])
+		s.rx = s.rx[idx+1:]
+		s.reply(s.exec(strings.TrimSpace(line)))
+	}
+}
+
+func (s *Simulator) reply(line string) {
+	s.out.WriteString(line + "\r\n")
+	s.cond.Broadcast()
+}
+
+// exec executes a line and returns the reply.
+func (s *Simulator) exec(line string) string {
+	switch line {
+	case "":
+		return "ok"
+	case "$H":
+		s.pos = [3]float32{}
+		s.Cmds = append(s.Cmds, Cmd{MoveTo, 0, 0})
+		return "ok"
+	case "$X":
+		return "ok"
+	}
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return "error:1"
+	}
+	pos := s.pos
+	moved := false
+	for _, w := range words {
+		if len(w) < 2 {
+			return "error:1"
+		}
+		letter, arg := w[0], w[1:]
+		switch letter {
+		case 'G':
+			switch arg {
+			case "0", "1":
+				s.motion = arg
+			case "4", "21", "90", "94":
+			default:
+				return "error:20"
+			}
+			continue
+		case 'M':
+			if arg != "2" {
+				return "error:20"
+			}
+			continue
+		}
+		v, err := strconv.ParseFloat(arg, 32)
+		if err != nil {
+			return "error:2"
+		}
+		switch letter {
+		case 'X':
+			pos[0], moved = float32(v), true
+		case 'Y':
+			pos[1], moved = float32(v), true
+		case 'Z':
+			pos[2] = float32(v)
+		case 'F', 'P':
+		default:
+			return "error:20"
+		}
+	}
+	if moved {
+		if s.motion == "" {
+			return "error:31"
+		}
+		typ := MoveTo
+		if s.motion == "1" && pos[2] < 0 {
+			typ = LineTo
+		}
+		s.Cmds = append(s.Cmds, Cmd{typ, pos[0], pos[1]})
+	}
+	s.pos = pos
+	return "ok"
+}
+
+func (s *Simulator) Read(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.out.Len() == 0 && !s.closed {
+		s.cond.Wait()
+	}
+	if s.closed {
+		return 0, io.EOF
+	}
+	return s.out.Read(data)
+}
+
+func (s *Simulator) Write(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, io.ErrClosedPipe
+	}
+	for i, b := range data {
+		switch b {
+		case softResetCmd:
+			s.rx = s.rx[:0]
+			s.held = false
+			s.motion = ""
+			s.reply("")
+			s.reply(simVersion)
+		case feedHoldCmd:
+			s.held = true
+		case cycleStartCmd:
+			s.held = false
+			s.cond.Broadcast()
+		case '?':
+			s.reply("<Idle|MPos:" + coord(s.pos[0]) + "," + coord(s.pos[1]) + "," + coord(s.pos[2]) + "|FS:0,0>")
+		default:
+			if len(s.rx) == rxBufferSize {
+				return i, errors.New("receive buffer overflow")
+			}
+			s.rx = append(s.rx, b)
+			s.cond.Broadcast()
+		}
+	}
+	return len(data), nil
+}
+
+func (s *Simulator) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+	return nil
+}
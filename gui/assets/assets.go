@@ -14,6 +14,7 @@ var (
 
 	ArrowLeft  = mustLoad("arrow-left.png")
 	ArrowRight = mustLoad("arrow-right.png")
+	ArrowUp    = mustLoad("arrow-up.png")
 
 	PlateCreditcardPrimary   = mustLoad("plate-creditcard-primary.png")
 	PlateCreditcardSecondary = mustLoad("plate-creditcard-secondary.png")
@@ -34,6 +35,7 @@ var (
 	IconRight     = mustLoad("icon-right.png")
 	IconInfo      = mustLoad("icon-info.png")
 	IconHammer    = mustLoad("icon-hammer.png")
+	IconQR        = mustLoad("icon-qr.png")
 
 	LogoSmall = mustLoad("logo-small.png")
 
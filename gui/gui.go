@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"io"
 	"log"
 	"math"
@@ -14,10 +15,12 @@ import (
 	"strings"
 	"text/template"
 	"time"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/skip2/go-qrcode"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/font/sfnt"
@@ -28,7 +31,10 @@ import (
 	"seedhammer.com/bip32"
 	"seedhammer.com/bip39"
 	"seedhammer.com/camera"
+	"seedhammer.com/codex32"
+	"seedhammer.com/engrave"
 	"seedhammer.com/font/sh"
+	"seedhammer.com/grbl"
 	"seedhammer.com/gui/assets"
 	"seedhammer.com/gui/layout"
 	"seedhammer.com/gui/op"
@@ -54,6 +60,9 @@ type Context struct {
 	EnableSeedScan bool
 	NoSDCard       bool
 	Version        string
+	// Profile indexes the mjolnir.Profiles entry of the plate
+	// material.
+	Profile int
 
 	Wakeup chan struct{}
 	events []Event
@@ -163,13 +172,13 @@ func (c *Context) Next() (Event, bool) {
 }
 
 const longestWord = "REMEMBER"
-const passphrase = ""
 
 type walletType int
 
 const (
 	singleKey walletType = iota
 	multiKey
+	exportKey
 )
 
 type CosignersScreen struct {
@@ -177,6 +186,7 @@ type CosignersScreen struct {
 
 	page   int
 	scroll int
+	qr     *QRScreen
 }
 
 type linePos struct {
@@ -212,8 +222,19 @@ func (s *CosignersScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) boo
 	const linesPerPage = 8
 	const linesPerScroll = linesPerPage - 3
 
+	// Only descriptors of known scripts can be encoded.
+	exportable := s.Descriptor.Type != urtypes.UnknownScript
 	maxPage := len(s.Descriptor.Keys)
 	for {
+		if s.qr != nil {
+			done := s.qr.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return false
+			}
+			s.qr = nil
+		}
 		e, ok := ctx.Next()
 		if !ok {
 			break
@@ -223,6 +244,10 @@ func (s *CosignersScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) boo
 			if e.Click {
 				return true
 			}
+		case input.Button3:
+			if e.Click && exportable {
+				s.qr = NewQRScreen("Export Wallet", "crypto-output", s.Descriptor.Encode())
+			}
 		case input.Left:
 			if e.Pressed {
 				s.page = (s.page - 1 + maxPage) % maxPage
@@ -298,12 +323,259 @@ func (s *CosignersScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) boo
 	}
 	clipScroll(ops, ops.End(), image.Rectangle(body))
 
+	nav := []NavButton{{Button: input.Button1, Style: StyleSecondary, Icon: assets.IconBack}}
+	if exportable {
+		nav = append(nav, NavButton{Button: input.Button3, Style: StylePrimary, Icon: assets.IconQR})
+	}
+	layoutNavigation(ctx, ops, th, dims, nav...)
+	return false
+}
+
+// QRScreen shows a message as an animated sequence of multi-part UR
+// QR codes.
+type QRScreen struct {
+	Title string
+
+	encoder *ur.Encoder
+	// speed indexes qrSpeeds.
+	speed int
+	// part is the UR of the current frame and qr its rendering.
+	part string
+	qr   *image.Gray
+	next time.Time
+}
+
+// qrFragmentLen is the maximum fragment length of animated QR codes,
+// small enough for codes that are readable on the display.
+const qrFragmentLen = 60
+
+// qrSpeeds lists the selectable frame rates of animated QR codes in
+// frames per second.
+var qrSpeeds = []int{1, 2, 4, 8}
+
+// qrQuietZone is the width in modules of the blank margin of QR
+// codes.
+const qrQuietZone = 2
+
+func NewQRScreen(title, typ string, message []byte) *QRScreen {
+	return &QRScreen{
+		Title:   title,
+		encoder: ur.NewEncoder(typ, message, qrFragmentLen),
+		speed:   2,
+	}
+}
+
+func (s *QRScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) bool {
+	for {
+		e, ok := ctx.Next()
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case input.Button1:
+			if e.Click {
+				return true
+			}
+		case input.Up:
+			if e.Pressed && s.speed < len(qrSpeeds)-1 {
+				s.speed++
+			}
+		case input.Down:
+			if e.Pressed && s.speed > 0 {
+				s.speed--
+			}
+		}
+	}
+
+	th := &descriptorTheme
+	op.ColorOp(ops, th.Background)
+	r := layout.Rectangle{Max: dims}
+	layoutTitle(ctx, ops, dims.X, th.Text, s.Title)
+
+	btnw := assets.NavBtnPrimary.Bounds().Dx()
+	speed := qrSpeeds[s.speed]
+	spsz := widget.Label(ops.Begin(), ctx.Styles.body, th.Text, fmt.Sprintf("%d fps", speed))
+	content, footer := r.Shrink(leadingSize, btnw, 0, 0).CutBottom(spsz.Y + 8)
+	op.Position(ops, ops.End(), footer.Center(spsz))
+
+	now := ctx.Platform.Now()
+	if s.qr == nil || !now.Before(s.next) {
+		s.part = s.encoder.Next()
+		// Upper case URs encode in the compact alphanumeric mode.
+		q, err := qrcode.New(strings.ToUpper(s.part), qrcode.Low)
+		if err != nil {
+			// The parts are small by construction.
+			panic(err)
+		}
+		q.DisableBorder = true
+		s.qr = qrImage(q.Bitmap(), content.Dx(), content.Dy())
+		s.next = now.Add(time.Second / time.Duration(speed))
+	}
+	ctx.WakeupAfter(s.next.Sub(now))
+	op.ImageOp(ops.Begin(), s.qr)
+	op.Position(ops, ops.End(), content.Center(s.qr.Bounds().Size()))
+
 	layoutNavigation(ctx, ops, th, dims,
 		NavButton{Button: input.Button1, Style: StyleSecondary, Icon: assets.IconBack},
 	)
 	return false
 }
 
+// qrImage renders a QR code bitmap with a quiet zone, scaled to fit
+// within width and height.
+func qrImage(bitmap [][]bool, width, height int) *image.Gray {
+	n := len(bitmap) + 2*qrQuietZone
+	size := width
+	if height < size {
+		size = height
+	}
+	scale := size / n
+	if scale < 1 {
+		scale = 1
+	}
+	img := image.NewGray(image.Rect(0, 0, n*scale, n*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			pos := image.Pt(x+qrQuietZone, y+qrQuietZone).Mul(scale)
+			px := image.Rectangle{Min: pos, Max: pos.Add(image.Pt(scale, scale))}
+			draw.Draw(img, px, image.Black, image.Point{}, draw.Src)
+		}
+	}
+	return img
+}
+
+// AccountScreen shows the standard account keys of a seed, and
+// exports them as a crypto-account QR code.
+type AccountScreen struct {
+	Account urtypes.Account
+
+	page   int
+	scroll int
+	qr     *QRScreen
+}
+
+func (s *AccountScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) bool {
+	const linesPerPage = 8
+	const linesPerScroll = linesPerPage - 3
+
+	maxPage := len(s.Account.Descriptors)
+	for {
+		if s.qr != nil {
+			done := s.qr.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return false
+			}
+			s.qr = nil
+		}
+		e, ok := ctx.Next()
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case input.Button1:
+			if e.Click {
+				return true
+			}
+		case input.Button3:
+			if e.Click {
+				s.qr = NewQRScreen("Export Key", "crypto-account", s.Account.Encode())
+			}
+		case input.Left:
+			if e.Pressed {
+				s.page = (s.page - 1 + maxPage) % maxPage
+				s.scroll = 0
+			}
+		case input.Right:
+			if e.Pressed {
+				s.page = (s.page + 1) % maxPage
+				s.scroll = 0
+			}
+		case input.Up:
+			if e.Pressed {
+				s.scroll -= linesPerScroll
+			}
+		case input.Down:
+			if e.Pressed {
+				s.scroll += linesPerScroll
+			}
+		}
+	}
+
+	th := &descriptorTheme
+	op.ColorOp(ops, th.Background)
+
+	// Title.
+	r := layout.Rectangle{Max: dims}
+	layoutTitle(ctx, ops, dims.X, th.Text, fmt.Sprintf("Key %d of %d", s.page+1, maxPage))
+
+	op.MaskOp(ops.Begin(), assets.ArrowLeft)
+	op.ColorOp(ops, th.Text)
+	left := ops.End()
+
+	op.MaskOp(ops.Begin(), assets.ArrowRight)
+	op.ColorOp(ops, th.Text)
+	right := ops.End()
+
+	leftsz := assets.ArrowLeft.Bounds().Size()
+	rightsz := assets.ArrowRight.Bounds().Size()
+
+	content := r.Shrink(0, 12, 0, 12)
+	body := content.Shrink(leadingSize, rightsz.X+12, 0, leftsz.X+12)
+	inner := body.Shrink(scrollFadeDist, 0, scrollFadeDist, 0)
+
+	bodyst := ctx.Styles.body
+	subst := ctx.Styles.subtitle
+	desc := s.Account.Descriptors[s.page]
+	k := desc.Keys[0]
+	script := desc.Type.String()
+	switch desc.Type {
+	case urtypes.P2SH, urtypes.P2SH_P2WSH, urtypes.P2WSH:
+		script = "Multisig " + script
+	}
+	var bodytxt richText
+	bodytxt.Add(ops, subst, body.Dx(), th.Text, "Fingerprint")
+	bodytxt.Add(ops, bodyst, body.Dx(), th.Text, fmt.Sprintf("%.8x", k.MasterFingerprint))
+	bodytxt.Y += infoSpacing
+	bodytxt.Add(ops, subst, body.Dx(), th.Text, "Script")
+	bodytxt.Add(ops, bodyst, body.Dx(), th.Text, script)
+	bodytxt.Y += infoSpacing
+	bodytxt.Add(ops, subst, body.Dx(), th.Text, "Derivation Path")
+	bodytxt.Add(ops, bodyst, body.Dx(), th.Text, derivationPath(k.DerivationPath))
+	bodytxt.Y += infoSpacing
+	bodytxt.Add(ops, bodyst, body.Dx(), th.Text, k.Key.String())
+
+	op.Position(ops, left, content.W(leftsz))
+	op.Position(ops, right, content.E(rightsz))
+
+	maxScroll := len(bodytxt.Lines) - linesPerPage
+	if s.scroll > maxScroll {
+		s.scroll = maxScroll
+	}
+	if s.scroll < 0 {
+		s.scroll = 0
+	}
+	off := bodytxt.Lines[s.scroll].Y - bodytxt.Lines[0].Y
+	ops.Begin()
+	for _, l := range bodytxt.Lines {
+		op.Position(ops, l.W, inner.Min.Sub(image.Pt(0, off)))
+	}
+	clipScroll(ops, ops.End(), image.Rectangle(body))
+
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: input.Button1, Style: StyleSecondary, Icon: assets.IconBack},
+		NavButton{Button: input.Button3, Style: StylePrimary, Icon: assets.IconQR},
+	)
+	return false
+}
+
 type DescriptorScreen struct {
 	Descriptor urtypes.OutputDescriptor
 	mnemonic   bip39.Mnemonic
@@ -341,6 +613,47 @@ func singlesigDescriptor(m bip39.Mnemonic, pass string) (urtypes.OutputDescripto
 	return desc, true
 }
 
+// accountKeys lists the scripts and standard derivation paths of
+// exported account keys.
+var accountKeys = []struct {
+	Type urtypes.Script
+	Path urtypes.Path
+}{
+	{urtypes.P2WSH, urtypes.Path{hdkeychain.HardenedKeyStart + 48, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 2}},
+	{urtypes.P2SH_P2WSH, urtypes.Path{hdkeychain.HardenedKeyStart + 48, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 1}},
+	{urtypes.P2WPKH, urtypes.Path{hdkeychain.HardenedKeyStart + 84, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 0}},
+	{urtypes.P2TR, urtypes.Path{hdkeychain.HardenedKeyStart + 86, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 0}},
+}
+
+// seedAccount derives the standard account keys of a seed and a
+// passphrase.
+func seedAccount(m bip39.Mnemonic, pass string) (urtypes.Account, bool) {
+	mk, ok := deriveMasterKey(m, pass)
+	if !ok {
+		return urtypes.Account{}, false
+	}
+	var acc urtypes.Account
+	for _, a := range accountKeys {
+		mfp, xpub, err := bip32.Derive(mk, a.Path)
+		if err != nil {
+			return urtypes.Account{}, false
+		}
+		acc.MasterFingerprint = mfp
+		acc.Descriptors = append(acc.Descriptors, urtypes.OutputDescriptor{
+			Type:      a.Type,
+			Threshold: 1,
+			Keys: []urtypes.KeyDescriptor{
+				{
+					DerivationPath:    a.Path,
+					MasterFingerprint: mfp,
+					Key:               *xpub,
+				},
+			},
+		})
+	}
+	return acc, true
+}
+
 func descriptorKeyIdx(desc urtypes.OutputDescriptor, m bip39.Mnemonic, pass string) (int, bool) {
 	seed := bip39.MnemonicSeed(m, pass)
 	mk, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
@@ -394,12 +707,13 @@ func (s *DescriptorScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) bo
 				dialog.Add(ops)
 				return false
 			}
+			pass := s.seed.Passphrase
 			s.seed = nil
 			if m == nil {
 				break
 			}
 			s.mnemonic = m
-			eng, err := NewEngraveScreen(ctx, s.Descriptor, s.mnemonic, passphrase)
+			eng, err := NewEngraveScreen(ctx, s.Descriptor, s.mnemonic, pass)
 			if err != nil {
 				s.warning = NewErrorScreen(err)
 				continue
@@ -515,8 +829,10 @@ type ScanScreen struct {
 	Title   string
 	Lead    string
 	decoder ur.Decoder
-	feed    *image.Gray
-	camera  struct {
+	// shares are the scanned output descriptor parts.
+	shares []string
+	feed   *image.Gray
+	camera struct {
 		out  chan<- camera.Frame
 		in   <-chan camera.Frame
 		quit chan struct{}
@@ -685,6 +1001,9 @@ func (s *ScanScreen) parseQR(qr []byte) (any, bool) {
 		s.decoder = ur.Decoder{}
 		s.decoder.Add(uqr)
 	}
+	if strings.HasPrefix(uqr, "UR:CRYPTO-OUTPUT/") {
+		return s.recoverDescriptor(uqr)
+	}
 	typ, enc, err := s.decoder.Result()
 	if err != nil {
 		s.decoder = ur.Decoder{}
@@ -701,6 +1020,44 @@ func (s *ScanScreen) parseQR(qr []byte) (any, bool) {
 	return v, true
 }
 
+// recoverDescriptor collects the output descriptor parts of plates
+// until they recover the descriptor.
+func (s *ScanScreen) recoverDescriptor(share string) (any, bool) {
+	for _, sh := range s.shares {
+		if sh == share {
+			return nil, false
+		}
+	}
+	s.shares = append(s.shares, share)
+	r, err := backup.Recover(s.shares)
+	if err == nil {
+		s.shares = nil
+		s.decoder = ur.Decoder{}
+		return r.Descriptor, true
+	}
+	if errors.Is(err, backup.ErrConflictingShares) {
+		// Start over from the share of a different descriptor.
+		s.shares = []string{share}
+		return nil, false
+	}
+	// Drop invalid shares.
+	var valid []string
+	for i, sh := range s.shares {
+		inconsistent := false
+		for _, j := range r.Inconsistent {
+			if i == j {
+				inconsistent = true
+				break
+			}
+		}
+		if !inconsistent {
+			valid = append(valid, sh)
+		}
+	}
+	s.shares = valid
+	return nil, false
+}
+
 type ErrorScreen struct {
 	Title string
 	Body  string
@@ -843,14 +1200,24 @@ type EngraveScreen struct {
 	Key          urtypes.KeyDescriptor
 	instructions []Instruction
 	plate        backup.Plate
+	// idx is the index of the share among total shares.
+	idx, total int
+	// estimates are the engraving durations of the plate sides.
+	estimates []time.Duration
 
 	cancel *ConfirmWarningScreen
+	// resume offers to resume an interrupted engraving.
+	resume *ConfirmWarningScreen
+	// paused offers to continue a paused engraving.
+	paused *ConfirmWarningScreen
 	step   int
 	dryRun struct {
 		timeout time.Time
 		enabled bool
 	}
 	engrave engraveState
+	// job is the engraving of the current side.
+	job     *EngraveJob
 	confirm ConfirmDelay
 }
 
@@ -901,6 +1268,16 @@ func NewErrorScreen(err error) *ErrorScreen {
 			Title: "Too Large",
 			Body:  "The descriptor cannot fit any plate size.",
 		}
+	case errors.Is(err, backup.ErrUnreadable):
+		return &ErrorScreen{
+			Title: "Unreadable",
+			Body:  "The QR codes of the backup would not be readable.",
+		}
+	case errors.Is(err, backup.ErrUnverifiable):
+		return &ErrorScreen{
+			Title: "Unverifiable",
+			Body:  "Only QR codes can be verified and scanned.",
+		}
 	case errors.Is(err, errKeyNotInDescriptor):
 		return &ErrorScreen{
 			Title: "Unknown Share",
@@ -932,10 +1309,18 @@ func validateDescriptor(desc urtypes.OutputDescriptor) error {
 			}
 		}
 	}
-	// Do a dummy engrave to see whether the backup fits any plate.
+	// Do a dummy engrave to see whether the backup fits any plate. Include
+	// the passphrase marker to cover the largest layout.
 	m := make(bip39.Mnemonic, 24)
 	m = m.FixChecksum()
-	if _, err := engravePlate(desc, 0, m); err != nil {
+	plateDesc := newPlateDesc(desc, 0, m, Passphrase{Marked: true})
+	plate, err := backup.Engrave(mjolnir.StrokeWidth, plateDesc)
+	if err != nil {
+		return err
+	}
+	// Verify that the QR codes of the layout are readable before
+	// engraving any plates.
+	if err := backup.Verify(mjolnir.StrokeWidth, plateDesc, plate); err != nil {
 		return err
 	}
 	// Verify that every permutation of desc.Threshold shares can recover the
@@ -947,28 +1332,34 @@ func validateDescriptor(desc urtypes.OutputDescriptor) error {
 	return nil
 }
 
-func engravePlate(desc urtypes.OutputDescriptor, keyIdx int, m bip39.Mnemonic) (backup.Plate, error) {
-	plateDesc := backup.PlateDesc{
-		Descriptor: desc,
-		Mnemonic:   m,
-		KeyIdx:     keyIdx,
-		Font:       &sh.Fontsh,
+func engravePlate(desc urtypes.OutputDescriptor, keyIdx int, m bip39.Mnemonic, pass Passphrase) (backup.Plate, error) {
+	return backup.Engrave(mjolnir.StrokeWidth, newPlateDesc(desc, keyIdx, m, pass))
+}
+
+func newPlateDesc(desc urtypes.OutputDescriptor, keyIdx int, m bip39.Mnemonic, pass Passphrase) backup.PlateDesc {
+	return backup.PlateDesc{
+		Descriptor:     desc,
+		Mnemonic:       m,
+		MarkPassphrase: pass.Marked,
+		KeyIdx:         keyIdx,
+		Font:           &sh.Fontsh,
 	}
-	return backup.Engrave(mjolnir.StrokeWidth, plateDesc)
 }
 
-func NewEngraveScreen(ctx *Context, desc urtypes.OutputDescriptor, m bip39.Mnemonic, passphrase string) (*EngraveScreen, error) {
-	keyIdx, ok := descriptorKeyIdx(desc, m, passphrase)
+func NewEngraveScreen(ctx *Context, desc urtypes.OutputDescriptor, m bip39.Mnemonic, pass Passphrase) (*EngraveScreen, error) {
+	keyIdx, ok := descriptorKeyIdx(desc, m, pass.Text)
 	if !ok {
 		return nil, errKeyNotInDescriptor
 	}
-	plate, err := engravePlate(desc, keyIdx, m)
+	plate, err := engravePlate(desc, keyIdx, m, pass)
 	if err != nil {
 		return nil, err
 	}
 	s := &EngraveScreen{
 		Key:   desc.Keys[keyIdx],
 		plate: plate,
+		total: len(desc.Keys),
+		idx:   keyIdx,
 	}
 	if !ctx.Calibrated {
 		s.instructions = append(s.instructions, EngraveFirstSideA...)
@@ -979,32 +1370,85 @@ func NewEngraveScreen(ctx *Context, desc urtypes.OutputDescriptor, m bip39.Mnemo
 		s.instructions = append(s.instructions, EngraveSideB...)
 	}
 	s.instructions = append(s.instructions, EngraveSuccess...)
+	s.resolve(ctx)
+	return s, nil
+}
+
+// profile returns the engraving profile of the selected material.
+func (s *EngraveScreen) profile(ctx *Context) mjolnir.Profile {
+	return mjolnir.Profiles[ctx.Profile%len(mjolnir.Profiles)]
+}
+
+// resolve estimates the engraving durations of the plate and expands
+// the instruction templates.
+func (s *EngraveScreen) resolve(ctx *Context) {
+	prof := s.profile(ctx)
 	args := struct {
-		Name  string
-		Idx   int
-		Total int
+		Name     string
+		Idx      int
+		Total    int
+		Material string
+		Depth    string
 	}{
-		Name:  plateName(s.plate.Size),
-		Total: len(desc.Keys),
-		Idx:   keyIdx + 1,
+		Name:     plateName(s.plate.Template),
+		Total:    s.total,
+		Idx:      s.idx + 1,
+		Material: prof.Name,
+		Depth:    prof.Depth,
+	}
+	model := ctx.Platform.EngraverModel()
+	s.estimates = nil
+	for _, side := range s.plate.Sides {
+		d := model.Estimate(engrave.Measure(side), s.dryRun.enabled, prof)
+		s.estimates = append(s.estimates, d)
 	}
 	for i, ins := range s.instructions {
 		tmpl := template.Must(template.New("instruction").Parse(ins.Body))
 		buf := new(bytes.Buffer)
 		tmpl.Execute(buf, args)
 		s.instructions[i].resolvedBody = buf.String()
+		if ins.Type == ConnectInstruction {
+			// Add the duration of the following engraving.
+			side := s.instructions[i+1].Side
+			s.instructions[i].resolvedBody += fmt.Sprintf("\n\nEstimated time: %s.", formatDuration(s.estimates[side]))
+		}
+	}
+}
+
+// engraveStatus describes the stage of an engraving with an
+// estimated duration.
+func engraveStatus(e mjolnir.Event, estimate time.Duration) string {
+	switch e.Stage {
+	case mjolnir.Buffering, mjolnir.Executing:
+		remaining := time.Duration(float32(estimate) * (1 - e.Progress))
+		return formatDuration(remaining) + " left"
+	case mjolnir.Connecting:
+		return "Connecting"
+	case mjolnir.Initializing, mjolnir.SettingSpeeds:
+		return "Preparing"
+	case mjolnir.Homing:
+		return "Homing"
+	case mjolnir.Returning:
+		return "Finishing"
+	case mjolnir.Done:
+		return "Done"
+	case mjolnir.Cancelled:
+		return "Cancelled"
+	default:
+		return ""
 	}
-	return s, nil
 }
 
 type engraveState struct {
-	dev          io.ReadWriteCloser
-	cancel       chan struct{}
-	progress     <-chan float32
-	errs         <-chan error
-	lastProgress float32
-	warning      *ErrorScreen
-	fatal        bool
+	dev    Engraver
+	cancel chan struct{}
+	// pause pauses and continues the engraving.
+	pause     chan bool
+	events    <-chan mjolnir.Event
+	errs      <-chan error
+	lastEvent mjolnir.Event
+	warning   *ErrorScreen
+	fatal     bool
 }
 
 func (s *EngraveScreen) close() {
@@ -1049,50 +1493,118 @@ func (s *EngraveScreen) moveStep(ctx *Context) bool {
 	}
 	ins = s.instructions[s.step]
 	if ins.Type == EngraveInstruction {
-		prog := &mjolnir.Program{
-			DryRun: s.dryRun.enabled,
-		}
-		s.plate.Sides[ins.Side].Engrave(prog)
-		prog.Prepare()
-		cancel := make(chan struct{})
-		errs := make(chan error, 1)
-		progress := make(chan float32, 1)
-		s.engrave.cancel = cancel
-		s.engrave.errs = WakeupChan(ctx, errs)
-		s.engrave.progress = WakeupChan(ctx, progress)
-		dev := s.engrave.dev
-		go func() {
-			defer close(errs)
-			defer close(progress)
-			err := mjolnir.Engrave(dev, prog, progress, cancel)
-			dev.Close()
-			errs <- err
-		}()
-		go s.plate.Sides[ins.Side].Engrave(prog)
+		// Record the side, because engravers generate the design
+		// more than once.
+		s.job = &EngraveJob{
+			Design:  engrave.Record(s.plate.Sides[ins.Side]),
+			DryRun:  s.dryRun.enabled,
+			Profile: s.profile(ctx),
+		}
+		s.startEngrave(ctx)
 	}
 	return false
 }
 
+// startEngrave runs the current job on the connected engraver.
+func (s *EngraveScreen) startEngrave(ctx *Context) {
+	cancel := make(chan struct{})
+	pause := make(chan bool, 1)
+	errs := make(chan error, 1)
+	events := make(chan mjolnir.Event, 1)
+	s.engrave.cancel = cancel
+	s.engrave.pause = pause
+	s.engrave.errs = WakeupChan(ctx, errs)
+	s.engrave.events = WakeupChan(ctx, events)
+	dev := s.engrave.dev
+	job := s.job
+	go func() {
+		defer close(errs)
+		defer close(events)
+		err := dev.Engrave(job, events, pause, cancel)
+		dev.Close()
+		errs <- err
+	}()
+}
+
+// setPaused pauses or continues the running engraving.
+func (s *EngraveScreen) setPaused(paused bool) {
+	select {
+	case <-s.engrave.pause:
+	default:
+	}
+	s.engrave.pause <- paused
+}
+
+// resumeEngrave reconnects to the engraver and resumes the interrupted
+// job.
+func (s *EngraveScreen) resumeEngrave(ctx *Context) {
+	s.engrave = engraveState{}
+	dev, err := ctx.Platform.Engraver()
+	if err != nil {
+		log.Printf("gui: failed to reconnect to engraver: %v", err)
+		s.engrave.warning = &ErrorScreen{
+			Title: "Connection Error",
+			Body:  "Failed to establish a connection to the engraver.",
+		}
+		s.engrave.fatal = true
+		return
+	}
+	s.engrave.dev = dev
+	s.startEngrave(ctx)
+}
+
 func (s *EngraveScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) bool {
 loop:
 	for {
 		select {
-		case p := <-s.engrave.progress:
-			s.engrave.lastProgress = p
+		case e := <-s.engrave.events:
+			s.engrave.lastEvent = e
 		case err := <-s.engrave.errs:
 			s.engrave = engraveState{}
+			s.paused = nil
+			var posErr *mjolnir.PositionError
+			if errors.As(err, &posErr) {
+				log.Printf("gui: engraver position error: %v", err)
+				s.job = nil
+				s.engrave = engraveState{
+					warning: &ErrorScreen{
+						Title: "Position Error",
+						Body:  "The engraver is not where it should be. Check the plate and the engraver for obstructions.",
+					},
+					fatal: true,
+				}
+				break
+			}
 			if err != nil {
 				log.Printf("gui: connection lost to engraver: %v", err)
+				title, reason := "Connection Error", "Connection to the engraver failed."
+				var timeoutErr *mjolnir.TimeoutError
+				if errors.As(err, &timeoutErr) {
+					title = "Not Responding"
+					reason = fmt.Sprintf("The engraver is not responding during %v.", timeoutErr.Phase)
+					if timeoutErr.Stalled {
+						reason = fmt.Sprintf("The engraver stopped making progress during %v.", timeoutErr.Phase)
+					}
+				}
+				if s.job != nil && s.job.Resumable() {
+					s.resume = &ConfirmWarningScreen{
+						Title: "Interrupted",
+						Body:  reason + "\n\nHold button to resume the engraving where it stopped, or go back to cancel.",
+						Icon:  assets.IconHammer,
+					}
+					break
+				}
 				s.engrave = engraveState{
 					warning: &ErrorScreen{
-						Title: "Connection Error",
-						Body:  "Connection to the engraver failed.",
+						Title: title,
+						Body:  reason,
 					},
 					fatal: true,
 				}
 				break
 			}
 			ctx.Calibrated = true
+			s.job = nil
 			s.step++
 			if s.step == len(s.instructions) {
 				return true
@@ -1111,8 +1623,17 @@ loop:
 		canPrev = s.step > 0 && s.instructions[s.step-1].Type == PrepareInstruction
 		progress = s.confirm.Progress(ctx)
 		if progress == 1. {
-			s.moveStep(ctx)
 			s.confirm = ConfirmDelay{}
+			if ins.Type == EngraveInstruction {
+				s.setPaused(true)
+				s.paused = &ConfirmWarningScreen{
+					Title: "Paused",
+					Body:  "The engraver stops after the current batch of lines.\n\nHold button to continue, or go back to cancel.",
+					Icon:  assets.IconHammer,
+				}
+				continue
+			}
+			s.moveStep(ctx)
 			continue
 		}
 		if !s.dryRun.timeout.IsZero() {
@@ -1122,6 +1643,7 @@ loop:
 				ctx.Buttons[input.Button2] = false
 				s.dryRun.timeout = time.Time{}
 				s.dryRun.enabled = !s.dryRun.enabled
+				s.resolve(ctx)
 			}
 		}
 		switch {
@@ -1137,6 +1659,36 @@ loop:
 				continue
 			}
 			defer dialog.Add(ops)
+		case s.paused != nil:
+			result := s.paused.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			switch result {
+			case ConfirmYes:
+				s.paused = nil
+				s.setPaused(false)
+				continue
+			case ConfirmNo:
+				s.cancel = &ConfirmWarningScreen{
+					Title: "Cancel?",
+					Body:  "This will cancel the engraving process\n\nHold button to confirm.",
+					Icon:  assets.IconDiscard,
+				}
+				continue
+			}
+			defer dialog.Add(ops)
+		case s.resume != nil:
+			result := s.resume.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			switch result {
+			case ConfirmYes:
+				s.resume = nil
+				s.resumeEngrave(ctx)
+				continue
+			case ConfirmNo:
+				s.close()
+				return true
+			}
+			defer dialog.Add(ops)
 		case s.engrave.warning != nil:
 			dismissed := s.engrave.warning.Layout(ctx, ops.Begin(), th, dims)
 			dialog := ops.End()
@@ -1169,6 +1721,11 @@ loop:
 				}
 			}
 		case input.Button2:
+			if e.Click && ins.Type == MaterialInstruction {
+				ctx.Profile = (ctx.Profile + 1) % len(mjolnir.Profiles)
+				s.resolve(ctx)
+				break
+			}
 			if e.Pressed {
 				s.dryRun.timeout = ctx.Platform.Now().Add(confirmDelay)
 				ctx.WakeupAfter(confirmDelay)
@@ -1176,7 +1733,8 @@ loop:
 				s.dryRun.timeout = time.Time{}
 			}
 		case input.Button3:
-			if ins.Type == ConnectInstruction {
+			// Hold to start the engraving, and to pause it.
+			if ins.Type == ConnectInstruction || ins.Type == EngraveInstruction && s.engrave.pause != nil {
 				if e.Pressed {
 					ctx.Buttons[input.Button3] = false
 					s.confirm.Start(ctx, confirmDelay)
@@ -1204,17 +1762,20 @@ loop:
 	const margin = 8
 	_, content := r.CutTop(leadingSize)
 	if ins.Type == EngraveInstruction {
-		progress := fmt.Sprintf("%d%%", int(s.engrave.lastProgress*100))
+		e := s.engrave.lastEvent
+		progress := fmt.Sprintf("%d%%", int(e.Progress*100))
 		_, content = subt.CutTop(subtsz.Y)
 		middle, _ := content.CutBottom(leadingSize)
 		op.Offset(ops, middle.Center(assets.ProgressCircle.Bounds().Size()))
 		op.MaskOp(ops, ProgressImage{
-			Progress: s.engrave.lastProgress,
+			Progress: e.Progress,
 			Src:      assets.ProgressCircle,
 		})
 		op.ColorOp(ops, th.Text)
 		sz := widget.Label(ops.Begin(), ctx.Styles.progress, th.Text, progress)
 		op.Position(ops, ops.End(), middle.Center(sz))
+		remsz := widget.Label(ops.Begin(), ctx.Styles.body, th.Text, engraveStatus(e, s.estimates[ins.Side]))
+		op.Position(ops, ops.End(), middle.Center(remsz).Add(image.Pt(0, sz.Y*2/3)))
 	}
 	content = content.Shrink(0, margin, 0, margin)
 	content, lead := content.CutBottom(leadingSize)
@@ -1240,6 +1801,17 @@ loop:
 		layoutNavigation(ctx, ops, th, dims, NavButton{Button: input.Button1, Style: StyleSecondary, Icon: icnBack})
 		switch ins.Type {
 		case EngraveInstruction:
+			if s.engrave.pause == nil {
+				break
+			}
+			var icn image.Image = assets.IconDot
+			if s.confirm.Running() {
+				icn = ProgressImage{
+					Progress: progress,
+					Src:      assets.IconProgress,
+				}
+			}
+			layoutNavigation(ctx, ops, th, dims, NavButton{Button: input.Button3, Style: StyleSecondary, Icon: icn})
 		case ConnectInstruction:
 			icn := assets.IconHammer
 			if s.confirm.Running() {
@@ -1256,17 +1828,17 @@ loop:
 	return false
 }
 
-func plateName(p backup.PlateSize) string {
-	switch p {
-	case backup.SmallPlate:
-		return "SH01 (85x55 mm)"
-	case backup.SquarePlate:
-		return "SH02 (85x85 mm)"
-	case backup.LargePlate:
-		return "SH03 (85x134 mm)"
-	default:
-		panic("unsupported plate")
+// formatDuration formats an estimated duration in whole minutes.
+func formatDuration(d time.Duration) string {
+	mins := int(math.Ceil(d.Minutes()))
+	if mins < 60 {
+		return fmt.Sprintf("%d min", mins)
 	}
+	return fmt.Sprintf("%d h %d min", mins/60, mins%60)
+}
+
+func plateName(t backup.Template) string {
+	return fmt.Sprintf("%s (%dx%d mm)", t.Name, t.Size.X, t.Size.Y)
 }
 
 type InstructionType int
@@ -1275,6 +1847,8 @@ const (
 	PrepareInstruction InstructionType = iota
 	ConnectInstruction
 	EngraveInstruction
+	// MaterialInstruction selects the plate material.
+	MaterialInstruction
 )
 
 type Instruction struct {
@@ -1313,7 +1887,11 @@ var (
 			Lead: "seedhammer.com/tip#5",
 		},
 		{
-			Body: "Tighten the hammerhead finger screw and make sure the depth selector is set to \"Strong\".",
+			Body: "Plate material:\n{{.Material}}\n\nClick the middle button to change.",
+			Type: MaterialInstruction,
+		},
+		{
+			Body: "Tighten the hammerhead finger screw and make sure the depth selector is set to \"{{.Depth}}\".",
 			Lead: "seedhammer.com/tip#6",
 		},
 		{
@@ -1342,6 +1920,10 @@ var (
 		{
 			Body: "Place \n2 x {{.Name}}\n on top of each other and tighten the nuts firmly.",
 		},
+		{
+			Body: "Plate material:\n{{.Material}}\n\nMake sure the depth selector is set to \"{{.Depth}}\". Click the middle button to change material.",
+			Type: MaterialInstruction,
+		},
 		{
 			Body: "Hold button to start the engraving process. The process is loud, use hearing protection.",
 			Type: ConnectInstruction,
@@ -1390,7 +1972,7 @@ func NewEmptySeedScreen(ctx *Context, title string) *SeedScreen {
 		s.seedlen = &ChoiceScreen{
 			Title:   title,
 			Lead:    "Choose number of words",
-			Choices: []string{"12 WORDS", "24 WORDS"},
+			Choices: []string{"12 WORDS", "24 WORDS", "CODEX32"},
 		}
 	}
 	return s
@@ -1404,14 +1986,18 @@ func NewSeedScreen(ctx *Context, m bip39.Mnemonic) *SeedScreen {
 
 type SeedScreen struct {
 	Mnemonic bip39.Mnemonic
-	selected int
-	scroll   int
-	method   *ChoiceScreen
-	seedlen  *ChoiceScreen
-	input    *WordKeyboardScreen
-	scanner  *ScanScreen
-	cancel   *ConfirmWarningScreen
-	warning  *ErrorScreen
+	// Passphrase is the passphrase of a confirmed seed.
+	Passphrase Passphrase
+	selected   int
+	scroll     int
+	method     *ChoiceScreen
+	seedlen    *ChoiceScreen
+	input      *WordKeyboardScreen
+	codex32    *Codex32Screen
+	passphrase *PassphraseScreen
+	scanner    *ScanScreen
+	cancel     *ConfirmWarningScreen
+	warning    *ErrorScreen
 }
 
 func (s *SeedScreen) empty() bool {
@@ -1451,6 +2037,31 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 			defer warning.Add(ops)
 		}
 		switch {
+		case s.passphrase != nil:
+			pass, done := s.passphrase.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return nil, false
+			}
+			s.passphrase = nil
+			if pass == nil {
+				continue
+			}
+			s.Passphrase = *pass
+			return s.Mnemonic, true
+		case s.codex32 != nil:
+			str, done := s.codex32.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return nil, false
+			}
+			s.codex32 = nil
+			if str != "" {
+				s.warning = codex32Result(str)
+			}
+			continue
 		case s.scanner != nil:
 			res, done := s.scanner.Layout(ctx, ops.Begin(), dims)
 			dialog := ops.End()
@@ -1487,6 +2098,11 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 				dialog.Add(ops)
 				return nil, false
 			}
+			if choice == 2 {
+				// Keep the choice screen for after the check.
+				s.codex32 = new(Codex32Screen)
+				continue
+			}
 			s.seedlen = nil
 			if choice == -1 {
 				continue
@@ -1512,7 +2128,7 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 				s.seedlen = &ChoiceScreen{
 					Title:   "Input Seed",
 					Lead:    "Choose number of words",
-					Choices: []string{"12 WORDS", "24 WORDS"},
+					Choices: []string{"12 WORDS", "24 WORDS", "CODEX32"},
 				}
 			case 1:
 				s.scanner = &ScanScreen{
@@ -1582,7 +2198,8 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 				}
 				break
 			}
-			return s.Mnemonic, true
+			s.passphrase = new(PassphraseScreen)
+			continue
 		case input.Down:
 			if e.Pressed && s.selected < len(s.Mnemonic)-1 {
 				s.selected++
@@ -1719,6 +2336,246 @@ func layoutWarning(ctx *Context, ops op.Ctx, th *Colors, width int, title, txt s
 	return box.Bounds().Size()
 }
 
+// codex32Result checks a codex32 string and describes the result.
+func codex32Result(str string) *ErrorScreen {
+	share, err := codex32.Parse(str)
+	if err != nil {
+		return &ErrorScreen{
+			Title: "Invalid Share",
+			Body:  "The codex32 string is invalid.\nCheck the characters and try again.",
+		}
+	}
+	body := fmt.Sprintf("Share %c of the %d-of-n backup %s is valid.", unicode.ToUpper(rune(share.Index)), share.Threshold, strings.ToUpper(share.ID))
+	if share.Threshold == 0 {
+		body = fmt.Sprintf("The secret %s is valid.", strings.ToUpper(share.ID))
+	}
+	return &ErrorScreen{
+		Title: "Valid Share",
+		Body:  body,
+	}
+}
+
+const codex32Prefix = "MS1"
+
+// Codex32Screen is a keyboard screen for entering a codex32
+// string.
+type Codex32Screen struct {
+	kbd *Keyboard
+}
+
+func (s *Codex32Screen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) (string, bool) {
+	if s.kbd == nil {
+		s.kbd = NewCodex32Keyboard(ctx)
+		// Every codex32 string starts with the human readable part
+		// and separator.
+		s.kbd.Word = codex32Prefix
+	}
+	for {
+		e, ok := ctx.Next()
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case input.Button1:
+			if e.Click {
+				return "", true
+			}
+		case input.Button2:
+			if e.Click && s.kbd.Word != codex32Prefix {
+				return s.kbd.Word, true
+			}
+		default:
+			s.kbd.Event(e)
+			if len(s.kbd.Word) < len(codex32Prefix) {
+				// Don't delete the prefix.
+				s.kbd.Word = codex32Prefix
+			}
+		}
+	}
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, "Input Codex32")
+
+	screen := layout.Rectangle{Max: dims}
+	_, content := screen.CutTop(leadingSize)
+	content, _ = content.CutBottom(8)
+
+	kbdsz := s.kbd.Layout(ctx, ops.Begin(), th)
+	op.Position(ops, ops.End(), content.S(kbdsz))
+
+	// Display the current row in groups of 4 characters, like
+	// the plates.
+	const groupLen, rowLen = 4, 12
+	layoutRow := func(ops op.Ctx, n int, row string) image.Point {
+		var groups []string
+		for len(row) > groupLen {
+			groups = append(groups, row[:groupLen])
+			row = row[groupLen:]
+		}
+		groups = append(groups, row)
+		txt := fmt.Sprintf("%d: %s", n, strings.Join(groups, " "))
+		return widget.Label(ops, ctx.Styles.word, th.Background, txt)
+	}
+	longest := layoutRow(op.Ctx{}, 8, strings.Repeat("W", rowLen))
+	word := s.kbd.Word
+	start := len(word) / rowLen * rowLen
+	layoutRow(ops.Begin(), start/rowLen+1, word[start:])
+	row := ops.End()
+	r := image.Rectangle{Max: longest}
+	r.Min.Y -= 3
+	op.MaskOp(ops.Begin(), assets.ButtonFocused.For(r))
+	op.ColorOp(ops, th.Text)
+	row.Add(ops)
+	top, _ := content.CutBottom(kbdsz.Y)
+	op.Position(ops, ops.End(), top.Center(longest))
+
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: input.Button1, Style: StyleSecondary, Icon: assets.IconBack},
+	)
+	if word != codex32Prefix {
+		layoutNavigation(ctx, ops, th, dims, NavButton{Button: input.Button2, Style: StylePrimary, Icon: assets.IconCheckmark})
+	}
+	return "", false
+}
+
+// Passphrase is a BIP-39 passphrase. It is only ever kept in
+// memory.
+type Passphrase struct {
+	Text string
+	// Marked requests plates marked as passphrase protected.
+	Marked bool
+}
+
+// PassphraseScreen asks for the optional passphrase of a seed. The
+// passphrase is entered twice to catch typing mistakes.
+type PassphraseScreen struct {
+	choice  *ChoiceScreen
+	kbd     *Keyboard
+	marked  bool
+	first   string
+	confirm bool
+	warning *ErrorScreen
+}
+
+func (s *PassphraseScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) (*Passphrase, bool) {
+	if s.choice == nil {
+		s.choice = &ChoiceScreen{
+			Title:   "Passphrase",
+			Lead:    "Does the seed have a passphrase?",
+			Choices: []string{"NO", "YES", "YES, MARKED"},
+		}
+	}
+	for {
+		if s.warning != nil {
+			dismiss := s.warning.Layout(ctx, ops.Begin(), th, dims)
+			warning := ops.End()
+			if dismiss {
+				s.warning = nil
+				continue
+			}
+			defer warning.Add(ops)
+		}
+		if s.kbd == nil {
+			choice, done := s.choice.Layout(ctx, ops.Begin(), th, dims, s.warning == nil)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return nil, false
+			}
+			switch choice {
+			case -1:
+				return nil, true
+			case 0:
+				return new(Passphrase), true
+			}
+			s.marked = choice == 2
+			s.kbd = NewPassphraseKeyboard(ctx)
+			continue
+		}
+		e, ok := ctx.Next()
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case input.Button1:
+			if !e.Click {
+				break
+			}
+			if s.confirm {
+				// Back to the first entry.
+				s.kbd.Word = s.first
+				s.first, s.confirm = "", false
+			} else {
+				s.kbd = nil
+			}
+		case input.Button2:
+			if !e.Click || s.kbd.Word == "" {
+				break
+			}
+			if !s.confirm {
+				s.first, s.confirm = s.kbd.Word, true
+				s.kbd.Clear()
+				break
+			}
+			if s.kbd.Word != s.first {
+				s.first, s.confirm = "", false
+				s.kbd.Clear()
+				s.warning = &ErrorScreen{
+					Title: "Passphrase Mismatch",
+					Body:  "The passphrases don't match.\nEnter the passphrase again.",
+				}
+				continue
+			}
+			pass := &Passphrase{Text: s.first, Marked: s.marked}
+			s.first = ""
+			s.kbd.Clear()
+			return pass, true
+		default:
+			s.kbd.Event(e)
+		}
+	}
+	op.ColorOp(ops, th.Background)
+	title := "Input Passphrase"
+	if s.confirm {
+		title = "Confirm Passphrase"
+	}
+	layoutTitle(ctx, ops, dims.X, th.Text, title)
+
+	screen := layout.Rectangle{Max: dims}
+	_, content := screen.CutTop(leadingSize)
+	content, _ = content.CutBottom(8)
+
+	kbdsz := s.kbd.Layout(ctx, ops.Begin(), th)
+	op.Position(ops, ops.End(), content.S(kbdsz))
+
+	// Display the end of the passphrase that fits.
+	const maxChars = 14
+	style := ctx.Styles.word
+	longest := widget.Label(op.Ctx{}, style, th.Background, strings.Repeat("W", maxChars))
+	txt := []rune(strings.ReplaceAll(s.kbd.Word, " ", spaceLabel))
+	if len(txt) > maxChars {
+		txt = txt[len(txt)-maxChars:]
+	}
+	widget.Label(ops.Begin(), style, th.Background, string(txt))
+	word := ops.End()
+	r := image.Rectangle{Max: longest}
+	r.Min.Y -= 3
+	op.MaskOp(ops.Begin(), assets.ButtonFocused.For(r))
+	op.ColorOp(ops, th.Text)
+	word.Add(ops)
+	top, _ := content.CutBottom(kbdsz.Y)
+	op.Position(ops, ops.End(), top.Center(longest))
+
+	if s.warning == nil {
+		layoutNavigation(ctx, ops, th, dims,
+			NavButton{Button: input.Button1, Style: StyleSecondary, Icon: assets.IconBack},
+		)
+		if s.kbd.Word != "" {
+			layoutNavigation(ctx, ops, th, dims, NavButton{Button: input.Button2, Style: StylePrimary, Icon: assets.IconCheckmark})
+		}
+	}
+	return nil, false
+}
+
 type WordKeyboardScreen struct {
 	Mnemonic bip39.Mnemonic
 	selected int
@@ -1803,17 +2660,60 @@ func (s *WordKeyboardScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims i
 	return false
 }
 
-var kbdKeys = [...][]rune{
+var kbdKeys = [][]rune{
+	[]rune("QWERTYUIOP"),
+	[]rune("ASDFGHJKL"),
+	[]rune("ZXCVBNM⌫"),
+}
+
+// codex32Keys is the keyboard layout for entering codex32 strings. Keys
+// outside the bech32 alphabet are disabled.
+var codex32Keys = [][]rune{
+	[]rune("023456789"),
 	[]rune("QWERTYUIOP"),
 	[]rune("ASDFGHJKL"),
 	[]rune("ZXCVBNM⌫"),
 }
 
+// passphraseKeys are the layers of the keyboard for entering passphrases.
+// The shift key, ⇧, cycles through them.
+var passphraseKeys = [][][]rune{
+	{
+		[]rune("1234567890"),
+		[]rune("qwertyuiop"),
+		[]rune("asdfghjkl"),
+		[]rune("⇧zxcvbnm ⌫"),
+	},
+	{
+		[]rune("1234567890"),
+		[]rune("QWERTYUIOP"),
+		[]rune("ASDFGHJKL"),
+		[]rune("⇧ZXCVBNM ⌫"),
+	},
+	{
+		[]rune("!@#$%^&*()"),
+		[]rune("-_=+[]{}\\|"),
+		[]rune(";:'\"<>,.?/"),
+		[]rune("⇧`~ ⌫"),
+	},
+}
+
+// spaceLabel stands in for the otherwise invisible space character.
+const spaceLabel = "·"
+
 type Keyboard struct {
 	Word string
 
+	// layers are the keyboard layouts, and keys the current one.
+	layers [][][]rune
+	layer  int
+	keys   [][]rune
+	// charset, if not empty, is the set of accepted runes. Otherwise,
+	// the keyboard only accepts prefixes of bip39 words.
+	charset string
+
 	nvalid    int
-	positions [len(kbdKeys)][]image.Point
+	positions [][]image.Point
 	bginact   image.Image
 	bgact     image.Image
 	bsinact   image.Image
@@ -1822,12 +2722,41 @@ type Keyboard struct {
 	backspace image.Point
 	size      image.Point
 
-	mask     uint32
+	mask     uint64
 	row, col int
 }
 
 func NewKeyboard(ctx *Context) *Keyboard {
-	k := new(Keyboard)
+	return newKeyboard(ctx, "", kbdKeys)
+}
+
+// NewCodex32Keyboard returns a keyboard for entering codex32
+// strings.
+func NewCodex32Keyboard(ctx *Context) *Keyboard {
+	return newKeyboard(ctx, strings.ToUpper(codex32.Alphabet), codex32Keys)
+}
+
+// NewPassphraseKeyboard returns a keyboard for entering passphrases
+// of printable ASCII characters.
+func NewPassphraseKeyboard(ctx *Context) *Keyboard {
+	var charset strings.Builder
+	for _, layer := range passphraseKeys {
+		for _, row := range layer {
+			for _, r := range row {
+				if r != '⇧' && r != '⌫' && !strings.ContainsRune(charset.String(), r) {
+					charset.WriteRune(r)
+				}
+			}
+		}
+	}
+	return newKeyboard(ctx, charset.String(), passphraseKeys...)
+}
+
+func newKeyboard(ctx *Context, charset string, layers ...[][]rune) *Keyboard {
+	k := &Keyboard{
+		layers:  layers,
+		charset: charset,
+	}
 	_, k.widest = ctx.Styles.keyboard.Layout(math.MaxInt, "W")
 	bssz := assets.KeyBackspace.Bounds().Size()
 	k.backspace = image.Pt(bssz.X, k.widest.Y)
@@ -1835,19 +2764,29 @@ func NewKeyboard(ctx *Context) *Keyboard {
 	k.bgact = assets.KeyActive.For(image.Rectangle{Max: k.widest})
 	k.bsinact = assets.Key.For(image.Rectangle{Max: k.backspace})
 	k.bsact = assets.KeyActive.For(image.Rectangle{Max: k.backspace})
+	k.setLayer(0)
+	k.Clear()
+	return k
+}
+
+// setLayer switches to a layer and lays out its keys.
+func (k *Keyboard) setLayer(layer int) {
+	k.layer = layer
+	k.keys = k.layers[layer]
+	k.positions = make([][]image.Point, len(k.keys))
 	bgbnds := k.bginact.Bounds()
 	const margin = 2
 	bgsz := bgbnds.Size().Add(image.Pt(margin, margin))
 	longest := 0
-	for _, row := range kbdKeys {
+	for _, row := range k.keys {
 		if n := len(row); n > longest {
 			longest = n
 		}
 	}
 	maxw := longest*bgsz.X - margin
-	for i, row := range kbdKeys {
+	for i, row := range k.keys {
 		n := len(row)
-		if i == len(kbdKeys)-1 {
+		if i == len(k.keys)-1 {
 			// Center row without the backspace key.
 			n--
 		}
@@ -1862,10 +2801,14 @@ func NewKeyboard(ctx *Context) *Keyboard {
 	}
 	k.size = image.Point{
 		X: maxw,
-		Y: len(kbdKeys)*bgsz.Y - margin,
+		Y: len(k.keys)*bgsz.Y - margin,
+	}
+	if k.row >= len(k.keys) {
+		k.row = len(k.keys) - 1
+	}
+	if n := len(k.keys[k.row]); k.col >= n {
+		k.col = n - 1
 	}
-	k.Clear()
-	return k
 }
 
 func (k *Keyboard) Complete() (bip39.Word, bool) {
@@ -1880,14 +2823,20 @@ func (k *Keyboard) Complete() (bip39.Word, bool) {
 
 func (k *Keyboard) Clear() {
 	k.Word = ""
+	if k.layer != 0 {
+		k.setLayer(0)
+	}
 	k.updateMask()
-	k.row = len(kbdKeys) / 2
-	k.col = len(kbdKeys[k.row]) / 2
+	k.row = len(k.keys) / 2
+	k.col = len(k.keys[k.row]) / 2
 	k.adjust(false)
 }
 
 func (k *Keyboard) updateMask() {
-	k.mask = ^uint32(0)
+	k.mask = ^uint64(0)
+	if k.charset != "" {
+		return
+	}
 	word := strings.ToLower(k.Word)
 	w, valid := bip39.ClosestWord(word)
 	if !valid {
@@ -1911,21 +2860,25 @@ func (k *Keyboard) updateMask() {
 		}
 	}
 	if k.nvalid == 1 {
-		k.mask = ^uint32(0)
+		k.mask = ^uint64(0)
 	}
 }
 
 func (k *Keyboard) idxForRune(r rune) (int, bool) {
-	idx := int(r - 'A')
-	if idx < 0 || idx >= 32 {
+	if r < 'A' || r > 'Z' {
 		return 0, false
 	}
-	return idx, true
+	return int(r - 'A'), true
 }
 
 func (k *Keyboard) Valid(r rune) bool {
-	if r == '⌫' {
+	switch {
+	case r == '⌫':
 		return len(k.Word) > 0
+	case r == '⇧':
+		return len(k.layers) > 1
+	case k.charset != "":
+		return strings.ContainsRune(k.charset, r)
 	}
 	idx, valid := k.idxForRune(r)
 	return valid && k.mask&(1<<idx) == 0
@@ -1938,11 +2891,11 @@ func (k *Keyboard) Event(e Event) {
 	switch e.Button {
 	case input.Left:
 		next := k.col
-		row := kbdKeys[k.row]
+		row := k.keys[k.row]
 		n := len(row)
 		for {
 			next = (next - 1 + n) % n
-			if !k.Valid(kbdKeys[k.row][next]) {
+			if !k.Valid(k.keys[k.row][next]) {
 				continue
 			}
 			k.col = next
@@ -1951,11 +2904,11 @@ func (k *Keyboard) Event(e Event) {
 		}
 	case input.Right:
 		next := k.col
-		row := kbdKeys[k.row]
+		row := k.keys[k.row]
 		n := len(row)
 		for {
 			next = (next + 1) % n
-			if !k.Valid(kbdKeys[k.row][next]) {
+			if !k.Valid(k.keys[k.row][next]) {
 				continue
 			}
 			k.col = next
@@ -1963,7 +2916,7 @@ func (k *Keyboard) Event(e Event) {
 			break
 		}
 	case input.Up:
-		n := len(kbdKeys)
+		n := len(k.keys)
 		next := k.row
 		for {
 			next = (next - 1 + n) % n
@@ -1973,7 +2926,7 @@ func (k *Keyboard) Event(e Event) {
 			}
 		}
 	case input.Down:
-		n := len(kbdKeys)
+		n := len(k.keys)
 		next := k.row
 		for {
 			next = (next + 1) % n
@@ -1985,7 +2938,7 @@ func (k *Keyboard) Event(e Event) {
 	case input.Rune:
 		k.rune(e.Rune)
 	case input.Center, input.Button3:
-		r := kbdKeys[k.row][k.col]
+		r := k.keys[k.row][k.col]
 		k.rune(r)
 	}
 }
@@ -1994,6 +2947,10 @@ func (k *Keyboard) rune(r rune) {
 	if !k.Valid(r) {
 		return
 	}
+	if r == '⇧' {
+		k.setLayer((k.layer + 1) % len(k.layers))
+		return
+	}
 	if r == '⌫' {
 		_, n := utf8.DecodeLastRuneInString(k.Word)
 		k.Word = k.Word[:len(k.Word)-n]
@@ -2009,7 +2966,7 @@ func (k *Keyboard) adjust(allowBackspace bool) {
 	dist := int(1e6)
 	current := k.positions[k.row][k.col]
 	found := false
-	for i, row := range kbdKeys {
+	for i, row := range k.keys {
 		j := 0
 		for _, key := range row {
 			if !k.Valid(key) || key == '⌫' && !allowBackspace {
@@ -2039,7 +2996,7 @@ func (k *Keyboard) adjustCol(row int) bool {
 	dist := int(1e6)
 	found := false
 	x := k.positions[k.row][k.col].X
-	for i, r := range kbdKeys[row] {
+	for i, r := range k.keys[row] {
 		if !k.Valid(r) {
 			continue
 		}
@@ -2059,7 +3016,7 @@ func (k *Keyboard) adjustCol(row int) bool {
 }
 
 func (k *Keyboard) Layout(ctx *Context, ops op.Ctx, th *Colors) image.Point {
-	for i, row := range kbdKeys {
+	for i, row := range k.keys {
 		for j, key := range row {
 			valid := k.Valid(key)
 			bg := k.bginact
@@ -2083,12 +3040,18 @@ func (k *Keyboard) Layout(ctx *Context, ops op.Ctx, th *Colors) image.Point {
 				col = th.Background
 			}
 			var sz image.Point
-			if key == '⌫' {
+			switch key {
+			case '⌫', '⇧':
 				icn := assets.KeyBackspace
+				if key == '⇧' {
+					icn = assets.ArrowUp
+				}
 				sz = icn.Bounds().Size()
 				op.MaskOp(ops.Begin(), icn)
 				op.ColorOp(ops, col)
-			} else {
+			case ' ':
+				sz = widget.Label(ops.Begin(), style, col, spaceLabel)
+			default:
 				sz = widget.Label(ops.Begin(), style, col, string(key))
 			}
 			key := ops.End()
@@ -2205,6 +3168,7 @@ type MainScreen struct {
 	scanner  *ScanScreen
 	desc     *DescriptorScreen
 	seed     *SeedScreen
+	account  *AccountScreen
 	warning  *ErrorScreen
 	sdcard   struct {
 		warning *ConfirmWarningScreen
@@ -2215,7 +3179,7 @@ type MainScreen struct {
 
 func (s *MainScreen) Select(ctx *Context) {
 	switch s.page {
-	case singleKey:
+	case singleKey, exportKey:
 		s.seed = NewEmptySeedScreen(ctx, "Input Seed")
 	case multiKey:
 		s.scanner = &ScanScreen{
@@ -2240,6 +3204,9 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 		case multiKey:
 			title = "Backup Multisig"
 			th = &descriptorTheme
+		case exportKey:
+			title = "Export Key"
+			th = &descriptorTheme
 		}
 		switch {
 		case s.seed != nil:
@@ -2249,12 +3216,25 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 				dialog.Add(ops)
 				return
 			}
+			pass := s.seed.Passphrase
 			s.seed = nil
 			if m == nil {
 				break
 			}
+			if s.page == exportKey {
+				acc, ok := seedAccount(m, pass.Text)
+				if !ok {
+					s.warning = &ErrorScreen{
+						Title: "Invalid Seed",
+						Body:  "The seed is invalid.",
+					}
+					continue
+				}
+				s.account = &AccountScreen{Account: acc}
+				continue
+			}
 			s.mnemonic = m
-			desc, ok := singlesigDescriptor(s.mnemonic, passphrase)
+			desc, ok := singlesigDescriptor(s.mnemonic, pass.Text)
 			if !ok {
 				s.warning = &ErrorScreen{
 					Title: "Invalid Seed",
@@ -2263,7 +3243,7 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 				continue
 			}
 			s.seed = nil
-			eng, err := NewEngraveScreen(ctx, desc, s.mnemonic, passphrase)
+			eng, err := NewEngraveScreen(ctx, desc, s.mnemonic, pass)
 			if err != nil {
 				s.warning = NewErrorScreen(err)
 				continue
@@ -2306,6 +3286,15 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 			s.seed = NewSeedScreen(ctx, s.mnemonic)
 			s.engrave = nil
 			continue
+		case s.account != nil:
+			done := s.account.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return
+			}
+			s.account = nil
+			continue
 		case s.desc != nil:
 			done := s.desc.Layout(ctx, ops.Begin(), dims)
 			dialog := ops.End()
@@ -2364,14 +3353,14 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 			}
 			s.page--
 			if s.page < 0 {
-				s.page = multiKey
+				s.page = exportKey
 			}
 		case input.Right:
 			if !e.Pressed {
 				break
 			}
 			s.page++
-			if s.page > multiKey {
+			if s.page > exportKey {
 				s.page = 0
 			}
 		}
@@ -2520,12 +3509,16 @@ func (s *MainScreen) layoutMainPlates(ops op.Ctx) image.Point {
 			cursor = cursor.Add(off)
 		}
 		return img.Bounds().Size().Add(cursor).Sub(off)
+	case exportKey:
+		img := assets.PlateSquarePrimary
+		op.ImageOp(ops, img)
+		return img.Bounds().Size()
 	}
 	panic("invalid page")
 }
 
 func (s *MainScreen) layoutPager(ops op.Ctx, th *Colors) image.Point {
-	const npages = int(multiKey) + 1
+	const npages = int(exportKey) + 1
 	const space = 4
 	sz := assets.CircleFilled.Bounds().Size()
 	for i := 0; i < npages; i++ {
@@ -2542,11 +3535,146 @@ func (s *MainScreen) layoutPager(ops op.Ctx, th *Colors) image.Point {
 
 type Platform interface {
 	Input(ch chan<- input.Event) error
-	Engraver() (io.ReadWriteCloser, error)
+	Engraver() (Engraver, error)
 	Camera(size image.Point, frames chan camera.Frame, out <-chan camera.Frame) (func(), error)
 	Dump(path string, r io.Reader) error
 	Now() time.Time
 	SDCard() <-chan bool
+	// EngraverModel returns the model of the engravers returned by
+	// Engraver, for estimating durations before connecting.
+	EngraverModel() EngraverModel
+}
+
+// EngraverModel identifies a kind of engraving machine.
+type EngraverModel int
+
+const (
+	MjolnirModel EngraverModel = iota
+	GRBLModel
+)
+
+// Estimate returns the approximate duration of engraving the design
+// measured by s with an engraver of model m.
+func (m EngraverModel) Estimate(s engrave.Stats, dryRun bool, prof mjolnir.Profile) time.Duration {
+	switch m {
+	case GRBLModel:
+		prog := &grbl.Program{DryRun: dryRun}
+		return prog.Estimate(s)
+	default:
+		prog := &mjolnir.Program{DryRun: dryRun, Profile: prof}
+		return prog.Estimate(s)
+	}
+}
+
+// Engraver is a connection to an engraving machine.
+type Engraver interface {
+	// Engrave a job while reporting its stages and progress to
+	// events. Receiving true from pause pauses the engraving, and
+	// false continues it. Closing quit cancels the engraving. A job
+	// interrupted after some progress may be resumed by engraving
+	// it again.
+	Engrave(job *EngraveJob, events chan mjolnir.Event, pause <-chan bool, quit <-chan struct{}) error
+	Close() error
+}
+
+// EngraveJob is the engraving of a design.
+type EngraveJob struct {
+	Design engrave.Command
+	DryRun bool
+	// Profile is the material profile of MarkgWay engravers.
+	Profile mjolnir.Profile
+
+	// state is the driver program of the job, kept for resuming.
+	state interface{}
+}
+
+// Resumable reports whether the job was interrupted after some
+// progress and can be resumed from where it stopped.
+func (j *EngraveJob) Resumable() bool {
+	p, ok := j.state.(*mjolnir.Program)
+	return ok && p.Checkpoint() > 0
+}
+
+// NewMjolnirEngraver returns an Engraver for a MarkgWay machine
+// connected through dev.
+func NewMjolnirEngraver(dev io.ReadWriteCloser) Engraver {
+	return &mjolnirEngraver{dev}
+}
+
+type mjolnirEngraver struct {
+	dev io.ReadWriteCloser
+}
+
+func (m *mjolnirEngraver) Engrave(job *EngraveJob, events chan mjolnir.Event, pause <-chan bool, quit <-chan struct{}) error {
+	prog, ok := job.state.(*mjolnir.Program)
+	if !ok {
+		prog = &mjolnir.Program{
+			DryRun:  job.DryRun,
+			Profile: job.Profile,
+		}
+		job.Design.Engrave(prog)
+		job.state = prog
+	}
+	prog.Prepare()
+	// Wait for the generator, because resuming reuses prog. The driver
+	// consumes every command, even when interrupted.
+	gen := make(chan struct{})
+	defer func() { <-gen }()
+	go func() {
+		defer close(gen)
+		job.Design.Engrave(prog)
+	}()
+	return mjolnir.Engrave(m.dev, prog, events, pause, quit)
+}
+
+func (m *mjolnirEngraver) Close() error {
+	return m.dev.Close()
+}
+
+// NewGRBLEngraver returns an Engraver for a GRBL machine connected
+// through dev.
+func NewGRBLEngraver(dev io.ReadWriteCloser) Engraver {
+	return &grblEngraver{dev}
+}
+
+type grblEngraver struct {
+	dev io.ReadWriteCloser
+}
+
+func (g *grblEngraver) Engrave(job *EngraveJob, events chan mjolnir.Event, pause <-chan bool, quit <-chan struct{}) error {
+	prog := &grbl.Program{
+		DryRun: job.DryRun,
+	}
+	job.Design.Engrave(prog)
+	prog.Prepare()
+	go job.Design.Engrave(prog)
+	// The GRBL driver reports only progress.
+	progress := make(chan float32, 1)
+	done := make(chan struct{})
+	defer func() { <-done }()
+	defer close(progress)
+	go func() {
+		defer close(done)
+		start := time.Now()
+		for p := range progress {
+			e := mjolnir.Event{
+				Stage:    mjolnir.Executing,
+				Start:    start,
+				Time:     time.Now(),
+				Progress: p,
+			}
+			select {
+			case <-events:
+			default:
+			}
+			events <- e
+		}
+	}()
+	return grbl.Engrave(g.dev, prog, progress, pause, quit)
+}
+
+func (g *grblEngraver) Close() error {
+	return g.dev.Close()
 }
 
 type LCD interface {
@@ -1,6 +1,7 @@
 package gui
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"image"
@@ -13,17 +14,21 @@ import (
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/math/f32"
 	"seedhammer.com/backup"
+	"seedhammer.com/bc/ur"
 	"seedhammer.com/bc/urtypes"
 	"seedhammer.com/bip32"
 	"seedhammer.com/bip39"
 	"seedhammer.com/camera"
 	"seedhammer.com/engrave"
 	"seedhammer.com/font/sh"
+	"seedhammer.com/grbl"
 	"seedhammer.com/gui/op"
 	"seedhammer.com/input"
 	"seedhammer.com/mjolnir"
 	"seedhammer.com/rgb16"
+	"seedhammer.com/zbar"
 )
 
 func TestDescriptorScreenError(t *testing.T) {
@@ -160,18 +165,148 @@ func TestDescriptorScreen(t *testing.T) {
 		ctxString(ctx, strings.ToUpper(bip39.LabelFor(w)))
 		ctxButton(ctx, input.Button2)
 	}
-	// Accept seed.
-	ctxButton(ctx, input.Button3)
+	// Accept seed, no passphrase.
+	ctxButton(ctx, input.Button3, input.Button3)
 	scr.Layout(ctx, op.Ctx{}, image.Point{})
 	if scr.warning == nil {
 		t.Fatal("a non-participating seed was accepted")
 	}
 }
 
+func TestQRScreen(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	scr := &CosignersScreen{Descriptor: twoOfThree.Descriptor}
+	dims := image.Pt(240, 240)
+	ctxButton(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	qr := scr.qr
+	if qr == nil {
+		t.Fatal("export screen not shown")
+	}
+	want := twoOfThree.Descriptor.Encode()
+	if qr.encoder.SeqLen() < 2 {
+		t.Fatalf("descriptor of %d bytes encoded in a single part", len(want))
+	}
+	typ, got := scanQRScreen(t, p, qr, dims, func() {
+		scr.Layout(ctx, op.Ctx{}, dims)
+	})
+	if typ != "crypto-output" || !bytes.Equal(got, want) {
+		t.Errorf("decoded %s %x, want crypto-output %x", typ, got, want)
+	}
+
+	// Frames don't advance before their time.
+	part := qr.part
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if qr.part != part {
+		t.Error("frame advanced too early")
+	}
+	ctxButton(ctx, input.Up, input.Up, input.Up)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if got, want := qrSpeeds[qr.speed], qrSpeeds[len(qrSpeeds)-1]; got != want {
+		t.Errorf("speed %d fps, want %d fps", got, want)
+	}
+	ctxButton(ctx, input.Button1)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if scr.qr != nil {
+		t.Error("failed to exit export screen")
+	}
+}
+
+// scanQRScreen scans the frames of an animated QR code until
+// they decode to a complete UR.
+func scanQRScreen(t *testing.T, p *testPlatform, qr *QRScreen, dims image.Point, frame func()) (string, []byte) {
+	t.Helper()
+	var d ur.Decoder
+	for i := 0; ; i++ {
+		if i == 10*qr.encoder.SeqLen() {
+			t.Fatalf("QR code not decoded after %d frames", i)
+		}
+		if sz := qr.qr.Bounds().Size(); sz.X > dims.X || sz.Y > dims.Y-leadingSize {
+			t.Fatalf("%v QR code doesn't fit the screen", sz)
+		}
+		res, err := zbar.Scan(qr.qr)
+		if err != nil || len(res) != 1 {
+			t.Fatalf("frame %d: failed to scan %q: %v", i, qr.part, err)
+		}
+		if err := d.Add(string(res[0])); err != nil {
+			t.Fatal(err)
+		}
+		typ, got, err := d.Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			return typ, got
+		}
+		p.timeOffset += time.Second
+		frame()
+	}
+}
+
+func TestAccountScreen(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	scr := &MainScreen{page: exportKey}
+	dims := image.Pt(240, 240)
+	frame := func() {
+		scr.Layout(ctx, op.Ctx{}, dims, nil)
+	}
+	mnemonic := twoOfThree.Mnemonic
+	scr.seed = NewSeedScreen(ctx, mnemonic)
+	// Accept seed, no passphrase.
+	ctxButton(ctx, input.Button3, input.Button3)
+	frame()
+	if scr.account == nil {
+		t.Fatal("account screen not shown")
+	}
+	acc := scr.account.Account
+	if len(acc.Descriptors) != len(accountKeys) {
+		t.Fatalf("account has %d keys, want %d", len(acc.Descriptors), len(accountKeys))
+	}
+	// The multisig key must match the seed's key in the descriptor.
+	idx, ok := descriptorKeyIdx(twoOfThree.Descriptor, mnemonic, "")
+	if !ok {
+		t.Fatal("seed doesn't match descriptor")
+	}
+	if got, want := acc.Descriptors[0].Keys[0], twoOfThree.Descriptor.Keys[idx]; got.MasterFingerprint != want.MasterFingerprint ||
+		got.Key.String() != want.Key.String() {
+		t.Errorf("exported key %.8x:%v, want %.8x:%v", got.MasterFingerprint, got.Key.String(), want.MasterFingerprint, want.Key.String())
+	}
+
+	ctxButton(ctx, input.Right, input.Button3)
+	frame()
+	if scr.account.page != 1 {
+		t.Errorf("account screen on page %d, want 1", scr.account.page)
+	}
+	qr := scr.account.qr
+	if qr == nil {
+		t.Fatal("export screen not shown")
+	}
+	typ, enc := scanQRScreen(t, p, qr, dims, frame)
+	if typ != "crypto-account" {
+		t.Fatalf("decoded %s, want crypto-account", typ)
+	}
+	got, err := urtypes.Parse(typ, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, acc) {
+		t.Errorf("decoded account\n%#v\nwant\n%#v", got, acc)
+	}
+
+	// Exit export and account screens.
+	ctxButton(ctx, input.Button1, input.Button1)
+	frame()
+	if scr.account != nil {
+		t.Error("failed to exit account screen")
+	}
+}
+
 func TestEngraveScreenCancel(t *testing.T) {
 	p := newPlatform()
 	ctx := NewContext(p)
-	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, "")
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -216,7 +351,7 @@ func TestEngraveScreenError(t *testing.T) {
 				Keys:      make([]urtypes.KeyDescriptor, test.keys),
 			}
 			mnemonic := fillDescriptor(t, desc, test.path, 12, 0)
-			_, err := NewEngraveScreen(ctx, desc, mnemonic, "")
+			_, err := NewEngraveScreen(ctx, desc, mnemonic, Passphrase{})
 			if err == nil {
 				t.Fatal("invalid descriptor succeeded")
 			}
@@ -232,7 +367,7 @@ func TestEngraveScreenConnectionError(t *testing.T) {
 	p.engrave.closed = make(chan []mjolnir.Cmd, 1)
 	p.engrave.connErr = errors.New("failed to connect")
 	ctx := NewContext(p)
-	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, "")
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -279,6 +414,328 @@ func TestEngraveScreenConnectionError(t *testing.T) {
 	<-p.engrave.closed
 }
 
+func TestEngraveScreenPause(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range scr.plate.Sides {
+		scr.plate.Sides[i] = testDesign(400)
+	}
+	for scr.instructions[scr.step].Type != ConnectInstruction {
+		ctxButton(ctx, input.Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	// Hold connect.
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	engraveStep := scr.step
+	// Hold pause.
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if scr.paused == nil {
+		t.Fatal("engraving didn't pause")
+	}
+	for i := 0; i < 200; i++ {
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+		time.Sleep(time.Millisecond)
+	}
+	if scr.step != engraveStep || scr.paused == nil {
+		t.Fatal("paused engraving completed")
+	}
+	// Hold continue.
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if scr.paused != nil {
+		t.Fatal("engraving didn't continue")
+	}
+	for scr.step == engraveStep {
+		if scr.engrave.warning != nil {
+			t.Fatal("continued engraving failed")
+		}
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEngraveScreenTimeout(t *testing.T) {
+	p := newPlatform()
+	p.engrave.ioErr = &mjolnir.TimeoutError{Phase: mjolnir.HomingPhase}
+	ctx := NewContext(p)
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for scr.instructions[scr.step].Type != ConnectInstruction {
+		ctxButton(ctx, input.Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	// Hold connect.
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	for scr.engrave.warning == nil {
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+		time.Sleep(time.Millisecond)
+	}
+	w := scr.engrave.warning
+	if w.Title != "Not Responding" || !strings.Contains(w.Body, "homing") {
+		t.Errorf("timeout reported as %q: %q", w.Title, w.Body)
+	}
+	if !scr.engrave.fatal {
+		t.Error("timeout before engraving is not fatal")
+	}
+}
+
+func TestEngraveScreenResume(t *testing.T) {
+	p := newPlatform()
+	// Drop the connection after a few batches.
+	p.engrave.faults = []mjolnir.Fault{{Kind: mjolnir.Disconnect, After: 400}}
+	ctx := NewContext(p)
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the plate with a short design.
+	for i := range scr.plate.Sides {
+		scr.plate.Sides[i] = testDesign(400)
+	}
+	for scr.instructions[scr.step].Type != ConnectInstruction {
+		ctxButton(ctx, input.Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	// Hold connect.
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	engraveStep := scr.step
+	for scr.resume == nil {
+		if scr.engrave.warning != nil {
+			t.Fatal("interrupted engraving is not resumable")
+		}
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+		// Leave time for the engraver.
+		time.Sleep(time.Millisecond)
+	}
+	// Hold resume.
+	p.engrave.faults = nil
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if scr.resume != nil {
+		t.Fatal("engraving didn't resume")
+	}
+	for scr.step == engraveStep {
+		if scr.engrave.warning != nil || scr.resume != nil {
+			t.Fatal("resumed engraving failed")
+		}
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEngraveScreenPositionError(t *testing.T) {
+	p := newPlatform()
+	p.engrave.faults = []mjolnir.Fault{{Kind: mjolnir.FalseOrigin}}
+	ctx := NewContext(p)
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range scr.plate.Sides {
+		scr.plate.Sides[i] = testDesign(10)
+	}
+	for scr.instructions[scr.step].Type != ConnectInstruction {
+		ctxButton(ctx, input.Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	// Hold connect.
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	engraveStep := scr.step
+	for scr.engrave.warning == nil {
+		if scr.resume != nil || scr.step != engraveStep {
+			t.Fatal("false origin not detected")
+		}
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+		time.Sleep(time.Millisecond)
+	}
+	if !scr.engrave.fatal {
+		t.Error("position error is not fatal")
+	}
+}
+
+// testDesign engraves a number of short lines.
+type testDesign int
+
+func (d testDesign) Engrave(p engrave.Program) {
+	for i := 0; i < int(d); i++ {
+		y := float32(i / 50)
+		p.Move(f32.Vec2{float32(i % 50), y})
+		p.Line(f32.Vec2{float32(i%50) + .5, y})
+	}
+}
+
+func TestEngraveScreenEstimate(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scr.estimates) != len(scr.plate.Sides) {
+		t.Fatalf("%d estimates for %d sides", len(scr.estimates), len(scr.plate.Sides))
+	}
+	for i, ins := range scr.instructions {
+		if ins.Type != ConnectInstruction {
+			continue
+		}
+		est := formatDuration(scr.estimates[scr.instructions[i+1].Side])
+		if !strings.Contains(ins.resolvedBody, est) {
+			t.Errorf("instruction %q doesn't contain estimate %q", ins.resolvedBody, est)
+		}
+	}
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "1 min"},
+		{25 * time.Minute, "25 min"},
+		{75*time.Minute + time.Second, "1 h 16 min"},
+	}
+	for _, test := range tests {
+		if got := formatDuration(test.d); got != test.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", test.d, got, test.want)
+		}
+	}
+}
+
+func TestEngraveScreenEstimateJob(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	est := scr.estimates[0]
+	ctxPress(ctx, input.Button2)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if !scr.dryRun.enabled {
+		t.Fatal("dry run not enabled")
+	}
+	if scr.estimates[0] >= est {
+		t.Errorf("dry run estimate %v not less than %v", scr.estimates[0], est)
+	}
+
+	p = newPlatform()
+	p.engrave.model = GRBLModel
+	ctx = NewContext(p)
+	scr, err = NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog := &grbl.Program{}
+	if got, want := scr.estimates[0], prog.Estimate(engrave.Measure(scr.plate.Sides[0])); got != want {
+		t.Errorf("GRBL estimate %v, want %v", got, want)
+	}
+}
+
+func TestEngraveStatus(t *testing.T) {
+	tests := []struct {
+		e    mjolnir.Event
+		want string
+	}{
+		{mjolnir.Event{}, "Connecting"},
+		{mjolnir.Event{Stage: mjolnir.Homing}, "Homing"},
+		{mjolnir.Event{Stage: mjolnir.Buffering}, "20 min left"},
+		{mjolnir.Event{Stage: mjolnir.Executing, Progress: .75}, "5 min left"},
+		{mjolnir.Event{Stage: mjolnir.Returning, Progress: 1}, "Finishing"},
+	}
+	for _, test := range tests {
+		if got := engraveStatus(test.e, 20*time.Minute); got != test.want {
+			t.Errorf("engraveStatus(%v) = %q, want %q", test.e.Stage, got, test.want)
+		}
+	}
+}
+
+func TestEngraveScreenProfile(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	scr, err := NewEngraveScreen(ctx, twoOfThree.Descriptor, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for scr.instructions[scr.step].Type != MaterialInstruction {
+		ctxButton(ctx, input.Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	est := scr.estimates[0]
+	ctxButton(ctx, input.Button2)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if got, want := scr.profile(ctx), mjolnir.Titanium; got != want {
+		t.Fatalf("selected profile %q, want %q", got.Name, want.Name)
+	}
+	if body := scr.instructions[scr.step].resolvedBody; !strings.Contains(body, mjolnir.Titanium.Name) {
+		t.Errorf("instruction %q doesn't contain material %q", body, mjolnir.Titanium.Name)
+	}
+	if scr.estimates[0] <= est {
+		t.Errorf("estimate %v for titanium not greater than %v", scr.estimates[0], est)
+	}
+	// Verify the profile is used by the engraving.
+	for scr.instructions[scr.step].Type != ConnectInstruction {
+		ctxButton(ctx, input.Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	ctxPress(ctx, input.Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if scr.job == nil || scr.job.Profile != mjolnir.Titanium {
+		t.Error("engraving doesn't use the selected profile")
+	}
+	scr.close()
+}
+
+func TestGRBLEngraver(t *testing.T) {
+	plate, err := engravePlate(twoOfThree.Descriptor, 0, twoOfThree.Mnemonic, Passphrase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := grbl.NewSimulator()
+	dev := NewGRBLEngraver(sim)
+	defer dev.Close()
+	events := make(chan mjolnir.Event, 1)
+	if err := dev.Engrave(&EngraveJob{Design: plate.Sides[0]}, events, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if e := <-events; e.Progress != 1 {
+		t.Errorf("final progress %v, want 1", e.Progress)
+	}
+	lines := 0
+	for _, c := range sim.Cmds {
+		if c.Type == grbl.LineTo {
+			lines++
+		}
+	}
+	if lines == 0 {
+		t.Error("no lines engraved")
+	}
+}
+
 func TestScanScreenError(t *testing.T) {
 	p := newPlatform()
 	// Fail on connect.
@@ -320,6 +777,151 @@ func TestWordKeyboardcreen(t *testing.T) {
 	}
 }
 
+func TestSeedScreenCodex32(t *testing.T) {
+	tests := []struct {
+		share string
+		title string
+	}{
+		{"MS12NAMEA320ZYXWVUTSRQPNMLKJHGFEDCAXRPP870HKKQRM", "Valid Share"},
+		{"MS12NAMEA320ZYXWVUTSRQPNMLKJHGFEDCAXRPP870HKKQRQ", "Invalid Share"},
+	}
+	for _, test := range tests {
+		ctx := NewContext(newPlatform())
+		scr := NewEmptySeedScreen(ctx, "")
+		frame := func() {
+			scr.Layout(ctx, op.Ctx{}, &singleTheme, image.Point{})
+		}
+		// Select keyboard, then codex32.
+		ctxButton(ctx, input.Button3, input.Down, input.Down, input.Button3)
+		frame()
+		ctxString(ctx, strings.TrimPrefix(test.share, codex32Prefix))
+		ctxButton(ctx, input.Button2)
+		frame()
+		if scr.warning == nil || scr.warning.Title != test.title {
+			t.Errorf("%s: got result %+v, want %q", test.share, scr.warning, test.title)
+		}
+	}
+}
+
+func TestPassphraseScreen(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	scr := new(PassphraseScreen)
+	frame := func() (*Passphrase, bool) {
+		return scr.Layout(ctx, op.Ctx{}, &singleTheme, image.Point{})
+	}
+	const pass = "Tr0ub4dor &3"
+	// Select marked passphrase.
+	ctxButton(ctx, input.Down, input.Down, input.Button3)
+	frame()
+	ctxString(ctx, pass)
+	ctxButton(ctx, input.Button2)
+	frame()
+	// Mistype confirmation.
+	ctxString(ctx, "Tr0ub4dor &4")
+	ctxButton(ctx, input.Button2)
+	frame()
+	if scr.warning == nil {
+		t.Fatal("mismatched passphrase accepted")
+	}
+	// Dismiss error, try again.
+	ctxButton(ctx, input.Button3)
+	frame()
+	for i := 0; i < 2; i++ {
+		ctxString(ctx, pass)
+		ctxButton(ctx, input.Button2)
+	}
+	got, done := frame()
+	if !done || got == nil {
+		t.Fatal("passphrase not accepted")
+	}
+	if want := (Passphrase{Text: pass, Marked: true}); *got != want {
+		t.Errorf("got passphrase %+v, want %+v", *got, want)
+	}
+}
+
+func TestKeyboardRunes(t *testing.T) {
+	kbd := NewKeyboard(NewContext(newPlatform()))
+	// Punctuation after 'Z' doesn't map to letter keys.
+	for _, r := range "[\\]^_`@" {
+		if kbd.Valid(r) {
+			t.Errorf("keyboard accepted %q", r)
+		}
+	}
+	if !kbd.Valid('A') || !kbd.Valid('Z') {
+		t.Error("keyboard rejected letters")
+	}
+}
+
+func TestPassphraseKeyboard(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	kbd := NewPassphraseKeyboard(ctx)
+	// Type every key of every layer by navigating to it.
+	var want strings.Builder
+	for l, layer := range passphraseKeys {
+		for i, row := range layer {
+			for j, r := range row {
+				if r == '⇧' || r == '⌫' {
+					continue
+				}
+				kbd.row, kbd.col = i, j
+				kbd.Event(Event{Event: input.Event{Button: input.Center, Pressed: true}})
+				want.WriteRune(r)
+			}
+		}
+		// Shift to the next layer.
+		kbd.row, kbd.col = len(layer)-1, 0
+		kbd.Event(Event{Event: input.Event{Button: input.Center, Pressed: true}})
+		if n := (l + 1) % len(passphraseKeys); kbd.layer != n {
+			t.Fatalf("shift moved to layer %d, want %d", kbd.layer, n)
+		}
+	}
+	if got := kbd.Word; got != want.String() {
+		t.Errorf("typed %q, want %q", got, want.String())
+	}
+	// Every printable ASCII character is accepted.
+	kbd.Clear()
+	for r := rune(' '); r <= '~'; r++ {
+		kbd.Event(Event{Event: input.Event{Button: input.Rune, Rune: r, Pressed: true}})
+	}
+	if n := len(kbd.Word); n != '~'-' '+1 {
+		t.Errorf("keyboard accepted %d of %d printable characters", n, '~'-' '+1)
+	}
+}
+
+func TestEngraveScreenPassphrase(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	const pass = "passphrase"
+	desc := urtypes.OutputDescriptor{
+		Type:      urtypes.P2WSH,
+		Threshold: 1,
+		Keys:      make([]urtypes.KeyDescriptor, 2),
+	}
+	mnemonic := fillDescriptor(t, desc, desc.DerivationPath(), 12, 1)
+	// Replace the key with the passphrase protected key.
+	mk, ok := deriveMasterKey(mnemonic, pass)
+	if !ok {
+		t.Fatal("failed to derive master key")
+	}
+	mfp, xpub, err := bip32.Derive(mk, desc.DerivationPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc.Keys[1].MasterFingerprint = mfp
+	desc.Keys[1].Key = *xpub
+	if _, err := NewEngraveScreen(ctx, desc, mnemonic, Passphrase{}); !errors.Is(err, errKeyNotInDescriptor) {
+		t.Errorf("got error %v, want %v", err, errKeyNotInDescriptor)
+	}
+	for _, marked := range []bool{false, true} {
+		scr, err := NewEngraveScreen(ctx, desc, mnemonic, Passphrase{Text: pass, Marked: marked})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(scr.Key, desc.Keys[1]) {
+			t.Errorf("passphrase matched the wrong key")
+		}
+	}
+}
+
 func ctxQR(t *testing.T, p *testPlatform, frame func(), qrs ...string) {
 	for _, qr := range qrs {
 		<-p.camera.init
@@ -409,17 +1011,40 @@ func TestSeedScreenInvalidSeed(t *testing.T) {
 	}
 }
 
+func TestScanScreenShares(t *testing.T) {
+	scr := new(ScanScreen)
+	// A corrupt share doesn't prevent recovery.
+	corrupt := []byte(twoOfThreeUR[1])
+	corrupt[len(corrupt)-1] = 'A'
+	for _, qr := range []string{string(corrupt), twoOfThreeUR[0], twoOfThreeUR[0]} {
+		if _, ok := scr.parseQR([]byte(qr)); ok {
+			t.Fatalf("descriptor recovered from %s", qr)
+		}
+	}
+	v, ok := scr.parseQR([]byte(twoOfThreeUR[1]))
+	if !ok {
+		t.Fatal("failed to recover descriptor")
+	}
+	if !reflect.DeepEqual(v, twoOfThree.Descriptor) {
+		t.Errorf("recovered %v, want %v", v, twoOfThree.Descriptor)
+	}
+	if len(scr.shares) > 0 {
+		t.Error("shares not cleared after recovery")
+	}
+}
+
+// twoOfThreeUR are the descriptor shares of the twoOfThree plates.
+var twoOfThreeUR = []string{
+	"UR:CRYPTO-OUTPUT/1347-2/LPCFAHFXAOCFADIOCYCMSWIDBYHDQZCYHNOEDWSBMUAMWYOTAHPFFXNECKNBNTHKDEADHLVLJKLYCMAHTAADEHOEADAEAOAEAMTAADDYOTADLOCSDYYKAEYKAEYKAOYKAOCYUTGWPMWYAXAAAYCYCPMTMUKTTAADDLOLAOWKAXHDCLAOZOJPGDLBSABTUYPTDTMEPAKEGRQZIYBWBKTAFTLOJTJKCHGDEORKFXVLRFKSHTJNAAHDCXMDQDGABWMULBONWNSWCXHPGMHPREKIVYGYKODAVTFELNREMDRNISVDBWIDTEWESKAHTAADEHOEADAEAOAEAMTAADDYOTADLOCSDYYKAEYKAEYKAOYKAOCYNDPSTLRTAXAAAYCYMSWPETYTAEVDTLISPT",
+	"UR:CRYPTO-OUTPUT/1355-2/LPCFAHGRAOCFADIOCYCMSWIDBYHDQZSRHSEOYKSGAAOXWSOYATEONYNNEHAMNEPMDNHKKEVTTNROHHDRSRGLPDSRFRJSJEHFTOLGBAHLCFJTMHLUDWTEESVWJPTYPFMOTLHTJPJZPTRPCNURVTCMNLTPNTENGMATUYTBTIHPVEWTVTKKCEJKZOHEPLGHKIYLGSESMDKICLTPCMCMTETPBDDRJLJKBZGDECIDFWTECTKKTDKPEEPMCXHNQDRFBYIYKIRSPYTODKROGYHERYIODSWEMELGESFYPTBWMSGEJERSEYHNWZKGISSTLNURDIFSVSDMJPKOMTLABYBGTBTEFNBBYTJPKOCTPYIORDURLRASSKFMTTMKCNFLLNVWWPTSBAGWTTPYMUOELP",
+}
+
 func TestMulti(t *testing.T) {
 	if testing.Short() {
 		t.Skipf("skipped in -short mode")
 	}
 	t.Parallel()
 
-	twoOfThreeUR := []string{
-		"UR:CRYPTO-OUTPUT/1347-2/LPCFAHFXAOCFADIOCYCMSWIDBYHDQZCYHNOEDWSBMUAMWYOTAHPFFXNECKNBNTHKDEADHLVLJKLYCMAHTAADEHOEADAEAOAEAMTAADDYOTADLOCSDYYKAEYKAEYKAOYKAOCYUTGWPMWYAXAAAYCYCPMTMUKTTAADDLOLAOWKAXHDCLAOZOJPGDLBSABTUYPTDTMEPAKEGRQZIYBWBKTAFTLOJTJKCHGDEORKFXVLRFKSHTJNAAHDCXMDQDGABWMULBONWNSWCXHPGMHPREKIVYGYKODAVTFELNREMDRNISVDBWIDTEWESKAHTAADEHOEADAEAOAEAMTAADDYOTADLOCSDYYKAEYKAEYKAOYKAOCYNDPSTLRTAXAAAYCYMSWPETYTAEVDTLISPT",
-		"UR:CRYPTO-OUTPUT/1355-2/LPCFAHGRAOCFADIOCYCMSWIDBYHDQZSRHSEOYKSGAAOXWSOYATEONYNNEHAMNEPMDNHKKEVTTNROHHDRSRGLPDSRFRJSJEHFTOLGBAHLCFJTMHLUDWTEESVWJPTYPFMOTLHTJPJZPTRPCNURVTCMNLTPNTENGMATUYTBTIHPVEWTVTKKCEJKZOHEPLGHKIYLGSESMDKICLTPCMCMTETPBDDRJLJKBZGDECIDFWTECTKKTDKPEEPMCXHNQDRFBYIYKIRSPYTODKROGYHERYIODSWEMELGESFYPTBWMSGEJERSEYHNWZKGISSTLNURDIFSVSDMJPKOMTLABYBGTBTEFNBBYTJPKOCTPYIORDURLRASSKFMTTMKCNFLLNVWWPTSBAGWTTPYMUOELP",
-	}
-
 	r := newRunner(t)
 	// Select multisig, start scanner.
 	r.Button(t, input.Right, input.Button3)
@@ -448,8 +1073,8 @@ func TestMulti(t *testing.T) {
 		t.Fatalf("got seed %v, wanted %v", got, mnemonic)
 	}
 
-	// Accept seed, go to engrave.
-	r.Button(t, input.Button3)
+	// Accept seed, no passphrase, go to engrave.
+	r.Button(t, input.Button3, input.Button3)
 	for r.app.scr.desc.engrave == nil {
 		r.Frame(t)
 	}
@@ -491,8 +1116,8 @@ func TestSingle(t *testing.T) {
 		t.Fatalf("got seed %v, wanted %v", got, mnemonic)
 	}
 
-	// Accept seed.
-	r.Button(t, input.Button3)
+	// Accept seed, no passphrase.
+	r.Button(t, input.Button3, input.Button3)
 	for r.app.scr.engrave == nil {
 		r.Frame(t)
 	}
@@ -564,6 +1189,10 @@ type testPlatform struct {
 		closed  chan []mjolnir.Cmd
 		connErr error
 		ioErr   error
+		// faults are injected into the simulators of new
+		// connections.
+		faults []mjolnir.Fault
+		model  EngraverModel
 	}
 
 	timeOffset time.Duration
@@ -578,6 +1207,10 @@ func (t *testPlatform) SDCard() <-chan bool {
 	return t.sdcard
 }
 
+func (t *testPlatform) EngraverModel() EngraverModel {
+	return t.engrave.model
+}
+
 func (t *testPlatform) Now() time.Time {
 	return time.Now().Add(t.timeOffset)
 }
@@ -659,12 +1292,15 @@ func (w *wrappedEngraver) Close() error {
 	return w.dev.Close()
 }
 
-func (p *testPlatform) Engraver() (io.ReadWriteCloser, error) {
+func (p *testPlatform) Engraver() (Engraver, error) {
 	if err := p.engrave.connErr; err != nil {
 		return nil, err
 	}
 	sim := mjolnir.NewSimulator()
-	return &wrappedEngraver{sim, p.engrave.closed, p.engrave.ioErr}, nil
+	for _, f := range p.engrave.faults {
+		sim.Inject(f)
+	}
+	return NewMjolnirEngraver(&wrappedEngraver{sim, p.engrave.closed, p.engrave.ioErr}), nil
 }
 
 func (p *testPlatform) Camera(dims image.Point, frames chan camera.Frame, out <-chan camera.Frame) (func(), error) {
@@ -863,7 +1499,7 @@ func simEngrave(t *testing.T, plate engrave.Command) []mjolnir.Cmd {
 	prog.Prepare()
 	errs := make(chan error, 1)
 	go func() {
-		errs <- mjolnir.Engrave(sim, prog, nil, nil)
+		errs <- mjolnir.Engrave(sim, prog, nil, nil, nil)
 	}()
 	plate.Engrave(prog)
 	if err := <-errs; err != nil {
@@ -10,14 +10,20 @@ import (
 	"io"
 	"math"
 	"runtime"
+	"time"
 
 	"github.com/tarm/serial"
 	"golang.org/x/image/math/f32"
 	"seedhammer.com/affine"
+	"seedhammer.com/engrave"
 )
 
 type Program struct {
-	DryRun     bool
+	DryRun bool
+	// Profile is the material profile, Stainless304 if not set.
+	Profile Profile
+	// MoveSpeed and PrintSpeed override the profile speeds if
+	// not zero.
 	MoveSpeed  float32
 	PrintSpeed float32
 	End        f32.Vec2
@@ -25,6 +31,9 @@ type Program struct {
 	pen        f32.Vec2
 	count      int
 	sent       int
+	// checkpoint is the number of commands completed by an
+	// interrupted engraving.
+	checkpoint int
 }
 
 const StrokeWidth = 0.3
@@ -89,6 +98,7 @@ const (
 	moveToOriginCmdExtra    = 0x50
 	moveToOriginCmdResponse = 0x00
 	initProgramCmd          = 0x60
+	queryPosCmd             = 0x16
 	moveCmd                 = 0x80
 	lineCmd                 = 0x00
 	nopCmd                  = 0xff
@@ -106,7 +116,28 @@ const (
 // The engraver expects program commands in batches.
 const progBatchSize = 80
 
-func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-chan struct{}) (eerr error) {
+// Engrave homes the engraver and streams prog while reporting an
+// Event to events, if not nil, for each stage and for every few
+// completed commands. Events are sent without blocking; a full
+// events channel loses its oldest event. Receiving true from pause
+// stops feeding commands after the current batch, leaving the hammer
+// in place, and receiving false continues from the same command.
+// Closing quit cancels the engraving and results in ErrCancelled.
+func Engrave(dev io.ReadWriter, prog *Program, events chan Event, pause <-chan bool, quit <-chan struct{}) (eerr error) {
+	ev := Event{
+		Start:     time.Now(),
+		Completed: prog.checkpoint,
+		Total:     prog.count,
+	}
+	if prog.count > 0 {
+		ev.Progress = float32(prog.checkpoint) / float32(prog.count)
+	}
+	emit := func(s Stage) {
+		ev.Stage = s
+		ev.Time = time.Now()
+		send(events, ev)
+	}
+	emit(Connecting)
 	bufw := bufio.NewWriterSize(dev, progBatchSize*cmdSize)
 	defer func() {
 		for i := prog.sent; i < prog.count; i++ {
@@ -148,7 +179,18 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 		case <-done:
 		}
 	}()
-	bufr := bufio.NewReaderSize(dev, 100)
+	dr := &deadlineReader{r: dev}
+	bufr := bufio.NewReaderSize(dr, 100)
+	// phase is the current phase of the engraving, and responded
+	// reports whether the engraver replied since the deadline was
+	// last set.
+	phase := InitPhase
+	responded := false
+	setDeadline := func(p Phase, timeout time.Duration) {
+		phase = p
+		responded = false
+		dr.deadline = time.Now().Add(timeout)
+	}
 	r := func(c int) []byte {
 		flush()
 		if eerr != nil {
@@ -157,7 +199,13 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 		data := make([]byte, c)
 		n, err := bufr.Read(data)
 		eerr = err
+		if err == errDeadline {
+			eerr = &TimeoutError{Phase: phase, Stalled: responded}
+		}
 		data = data[:n]
+		if n > 0 {
+			responded = true
+		}
 		return data
 	}
 	expect := func(exp ...byte) {
@@ -173,7 +221,7 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 	}
 	atleast := func(n int) []byte {
 		var res []byte
-		for n > 0 {
+		for n > 0 && eerr == nil {
 			data := r(n)
 			res = append(res, data...)
 			n -= len(data)
@@ -211,17 +259,40 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 		return
 	}
 	queryPos := func() (x int, y int, z int) {
-		wr(0x16)
-		expect(0x16)
-		x, y, z = parseCoords(atleast(9))
+		wr(queryPosCmd)
+		expect(queryPosCmd)
+		coords := atleast(9)
+		if eerr != nil {
+			return
+		}
+		x, y, z = parseCoords(coords)
 		return
 	}
-	_, _ = atleast, queryPos
+	// checkPos verifies the position reported by the engraver.
+	checkPos := func(want [2]int, homing bool) {
+		x, y, _ := queryPos()
+		if eerr != nil {
+			return
+		}
+		if abs(x-want[0]) > posTolerance || abs(y-want[1]) > posTolerance {
+			eerr = &PositionError{
+				Homing: homing,
+				Want:   f32.Vec2{float32(want[0]) * stepSize, float32(want[1]) * stepSize},
+				Got:    f32.Vec2{float32(x) * stepSize, float32(y) * stepSize},
+			}
+		}
+	}
 
+	setDeadline(InitPhase, initTimeout)
 	initialize()
+	if eerr == nil {
+		emit(Initializing)
+	}
 
 	// Speed range: [1000,30].
+	var printSpeed, moveSpeed int
 	setSpeeds := func(print, move, xxx int) {
+		printSpeed, moveSpeed = print, move
 		wr(setSpeedCmd, byte(print), byte(print>>8), byte(move), byte(move>>8), byte(xxx), byte(xxx>>8))
 		expect(setSpeedCmd)
 	}
@@ -231,39 +302,120 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 		wr(setDelaysCmd, byte(penDown), byte(penUp))
 		expect(setDelaysCmd)
 	}
-	setDelays(0x14, 0x14)
+	prof := prog.profile()
+	setDelays(int(prof.PenDown), int(prof.PenUp))
 
 	// Init done.
 
-	runProgram := func(p *Program, progress chan float32) {
+	paused := false
+	// hold waits for the engraving to continue if paused, and
+	// reports whether it was cancelled in the meantime.
+	hold := func() bool {
+		for {
+			select {
+			case paused = <-pause:
+			case <-quit:
+				return true
+			default:
+				if !paused {
+					return false
+				}
+				select {
+				case paused = <-pause:
+				case <-quit:
+					return true
+				}
+			}
+		}
+	}
+
+	// runProgram runs a program during a phase and returns the
+	// position of its last command.
+	runProgram := func(p *Program, ph Phase) (end [2]int) {
 		p.sent = 0
-		nbatches := (p.count + progBatchSize - 1) / progBatchSize
+		// Skip the commands completed by an interrupted run, and
+		// move to where they left off.
+		skip := p.checkpoint
+		var resume [][cmdSize]byte
+		if skip > 0 {
+			var last [cmdSize]byte
+			for i := 0; i < skip; i++ {
+				last = <-p.cmds
+				p.sent++
+			}
+			last[0] = moveCmd
+			resume = append(resume, last)
+		}
+		count := len(resume) + p.count - skip
+		nbatches := (count + progBatchSize - 1) / progBatchSize
 		if nbatches > 0xffff {
 			eerr = errors.New("engrave: program too large")
 			return
 		}
 		wr(initProgramCmd, byte(nbatches), byte(nbatches>>8))
+		if ph == ProgramPhase {
+			ev.Batches = nbatches
+		}
 		completed := 0
+		sent := 0
+		// longest is the longest distance between commands sent.
+		longest := 0
+		// stepTimeout is the deadline for the next command to
+		// complete.
+		stepTimeout := func() time.Duration {
+			speed := printSpeed
+			if moveSpeed > speed {
+				speed = moveSpeed
+			}
+			mmPerSec := float64(speedFactor) / float64(speed)
+			d := time.Duration(float64(longest) * stepSize / mmPerSec * float64(time.Second))
+			return commandTimeout + 2*d
+		}
+		setDeadline(ph, stepTimeout())
 	done:
 		for {
 			status := r(1)
 			if eerr != nil {
 				return
 			}
-			paddedCount := (p.count + progBatchSize - 1) / progBatchSize * progBatchSize
+			paddedCount := (count + progBatchSize - 1) / progBatchSize * progBatchSize
 			switch status[0] {
 			case bufferProgramStatus:
-				rem := p.count - p.sent
+				rem := count - sent
 				if rem == 0 {
 					break
 				}
+				if ph == ProgramPhase {
+					cancelled := hold()
+					setDeadline(ph, stepTimeout())
+					if cancelled {
+						// Wait for the cancellation instead
+						// of sending the batch.
+						break
+					}
+				}
 				ncmd := progBatchSize
 				if ncmd > rem {
 					ncmd = rem
 				}
+				if ph == ProgramPhase {
+					ev.Batch = (sent + progBatchSize) / progBatchSize
+					emit(Buffering)
+				}
 				for i := 0; i < ncmd; i++ {
-					cmd := <-p.cmds
-					p.sent++
+					var cmd [cmdSize]byte
+					if sent < len(resume) {
+						cmd = resume[sent]
+					} else {
+						cmd = <-p.cmds
+						p.sent++
+					}
+					sent++
+					x, y, _ := parseCoords(cmd[1:])
+					if d := abs(x-end[0]) + abs(y-end[1]); d > longest {
+						longest = d
+					}
+					end = [2]int{x, y}
 					wr(cmd[:]...)
 				}
 				// Pad with 0xff.
@@ -276,19 +428,40 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 				}
 			case programStepStatus:
 				completed++
-				if progress == nil {
+				if completed < paddedCount {
+					setDeadline(ph, stepTimeout())
+				} else if ph == ProgramPhase {
+					setDeadline(CompletionPhase, completionTimeout)
+				}
+				// Checkpoint completed batches.
+				if completed%progBatchSize == 0 {
+					if done := completed - len(resume); done > 0 {
+						p.checkpoint = skip + done
+					}
+					// Don't count padding.
+					if p.checkpoint > p.count {
+						p.checkpoint = p.count
+					}
+				}
+				if ph != ProgramPhase {
 					break
 				}
-				// Don't spam the progress channel.
+				// Don't spam the events channel.
 				if completed%10 != 0 && completed < paddedCount {
 					break
 				}
-				select {
-				case <-progress:
-				default:
+				// Don't count the resume move and padding.
+				ev.Completed = skip + completed - len(resume)
+				if ev.Completed < skip {
+					ev.Completed = skip
+				}
+				if ev.Completed > p.count {
+					ev.Completed = p.count
 				}
-				progress <- float32(completed) / float32(paddedCount)
+				ev.Progress = float32(skip+completed) / float32(skip+paddedCount)
+				emit(Executing)
 			case programCompleteStatus:
+				p.checkpoint = 0
 				break done
 			case cancellingStatus:
 			case cancelledStatus:
@@ -297,9 +470,10 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 				}
 			}
 		}
+		return
 	}
 
-	moveTo := func(x, y float32) {
+	moveTo := func(x, y float32, ph Phase) {
 		move := new(Program)
 		f := func() {
 			move.Move(f32.Vec2{x, y})
@@ -307,31 +481,43 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 		f()
 		move.Prepare()
 		go f()
-		runProgram(move, nil)
+		runProgram(move, ph)
 	}
 
 	setSpeeds(300, 300, 0xe6)
 	// Move to origin.
+	if eerr == nil {
+		emit(Homing)
+	}
+	setDeadline(HomingPhase, homingTimeout)
 	origin()
 	// Avoid false origin.
-	moveTo(10, 10)
+	moveTo(10, 10, HomingPhase)
+	setDeadline(HomingPhase, homingTimeout)
 	origin()
-	// 0 lowest, 1 highest.
-	moveSpeed := prog.MoveSpeed
-	printSpeed := prog.PrintSpeed
-	if moveSpeed == 0 {
-		moveSpeed = defaultMoveSpeed
-	}
-	if printSpeed == 0 {
-		printSpeed = defaultPrintSpeed
+	checkPos([2]int{}, true)
+	mms, mps := prog.speeds()
+	if eerr == nil {
+		emit(SettingSpeeds)
 	}
-	mms := int(moveSpeed*float32(30) + (1.-moveSpeed)*float32(1000))
-	mps := int(printSpeed*float32(30) + (1.-printSpeed)*float32(1000))
 	setSpeeds(mps, mms, 0xe6)
-	runProgram(prog, progress)
+	end := runProgram(prog, ProgramPhase)
+	if eerr == nil {
+		checkPos(end, false)
+	}
 	if eerr == nil || eerr == ErrCancelled {
+		if eerr == nil {
+			emit(Returning)
+		}
 		setSpeeds(300, 300, 0xe6)
-		moveTo(prog.End[0], prog.End[1])
+		moveTo(prog.End[0], prog.End[1], CompletionPhase)
+	}
+	switch eerr {
+	case nil:
+		ev.Completed, ev.Progress = ev.Total, 1
+		emit(Done)
+	case ErrCancelled:
+		emit(Cancelled)
 	}
 
 	return eerr
@@ -339,6 +525,188 @@ func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-cha
 
 var ErrCancelled = errors.New("cancelled")
 
+// Phase is a phase of an engraving.
+type Phase int
+
+const (
+	InitPhase Phase = iota
+	HomingPhase
+	ProgramPhase
+	CompletionPhase
+)
+
+func (p Phase) String() string {
+	switch p {
+	case InitPhase:
+		return "initialization"
+	case HomingPhase:
+		return "homing"
+	case ProgramPhase:
+		return "engraving"
+	case CompletionPhase:
+		return "completion"
+	default:
+		return fmt.Sprintf("phase %d", int(p))
+	}
+}
+
+// TimeoutError is returned when the engraver misses the deadline of
+// an engraving phase.
+type TimeoutError struct {
+	Phase Phase
+	// Stalled reports whether the engraver replied, but without
+	// making progress.
+	Stalled bool
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Stalled {
+		return fmt.Sprintf("engrave: machine stalled during %v", e.Phase)
+	}
+	return fmt.Sprintf("engrave: machine not responding during %v", e.Phase)
+}
+
+// Deadlines of the engraving phases. The deadline of each program
+// command extends commandTimeout by twice the duration of the longest
+// command sent so far.
+var (
+	initTimeout       = 5 * time.Second
+	homingTimeout     = 90 * time.Second
+	commandTimeout    = 10 * time.Second
+	completionTimeout = 10 * time.Second
+)
+
+// errDeadline is returned by deadlineReader when its deadline
+// passes.
+var errDeadline = errors.New("engrave: deadline exceeded")
+
+// deadlineReader reads in separate goroutines to abandon reads that
+// exceed a deadline, after which the reader must not be used.
+type deadlineReader struct {
+	r        io.Reader
+	deadline time.Time
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	timeout := time.Until(d.deadline)
+	if timeout <= 0 {
+		return 0, errDeadline
+	}
+	type result struct {
+		n   int
+		err error
+	}
+	// Don't let an abandoned read write to p.
+	buf := make([]byte, len(p))
+	res := make(chan result, 1)
+	go func() {
+		n, err := d.r.Read(buf)
+		res <- result{n, err}
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-res:
+		return copy(p, buf[:r.n]), r.err
+	case <-timer.C:
+		return 0, errDeadline
+	}
+}
+
+// PositionError is returned when the position reported by the
+// engraver differs from the expected position, such as when homing
+// hits a false origin or the engraver loses steps.
+type PositionError struct {
+	// Homing reports whether the position was checked after
+	// homing.
+	Homing bool
+	// Want and Got are the expected and reported positions in
+	// millimeters.
+	Want, Got f32.Vec2
+}
+
+func (e *PositionError) Error() string {
+	if e.Homing {
+		return fmt.Sprintf("engrave: false origin at (%.2f,%.2f) mm", e.Got[0], e.Got[1])
+	}
+	return fmt.Sprintf("engrave: engraver at (%.2f,%.2f) mm, expected (%.2f,%.2f) mm", e.Got[0], e.Got[1], e.Want[0], e.Want[1])
+}
+
+// posTolerance is the largest difference in machine units between a
+// reported and expected position.
+const posTolerance = 6
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Checkpoint returns the number of commands of the program completed
+// by an interrupted engraving, counted in whole batches acknowledged
+// by the engraver. Engraving the program again re-homes the engraver
+// and resumes after the completed commands. Checkpoint returns 0 if
+// the program has not been interrupted.
+func (p *Program) Checkpoint() int {
+	return p.checkpoint
+}
+
+// speeds maps the program speeds to the machine speed range.
+func (p *Program) speeds() (move, print int) {
+	// 0 lowest, 1 highest.
+	prof := p.profile()
+	moveSpeed := p.MoveSpeed
+	printSpeed := p.PrintSpeed
+	if moveSpeed == 0 {
+		moveSpeed = prof.MoveSpeed
+	}
+	if printSpeed == 0 {
+		printSpeed = prof.PrintSpeed
+	}
+	move = int(moveSpeed*float32(30) + (1.-moveSpeed)*float32(1000))
+	print = int(printSpeed*float32(30) + (1.-printSpeed)*float32(1000))
+	return
+}
+
+// Timing model of the engraver, approximated from engravings at
+// the default speeds.
+const (
+	// speedFactor converts machine speeds to millimeters per second.
+	// Machine speeds are delays; higher values are slower.
+	speedFactor = 1500
+	// turnDelay covers the slowdown at sharp corners.
+	turnDelay = 10 * time.Millisecond
+	// setupDuration covers initialization and homing.
+	setupDuration = 10 * time.Second
+)
+
+// Estimate returns the approximate duration of engraving a design
+// measured by s, including setup.
+func (p *Program) Estimate(s engrave.Stats) time.Duration {
+	move, print := p.speeds()
+	seconds := func(length float32, speed int) time.Duration {
+		mmPerSec := float32(speedFactor) / float32(speed)
+		return time.Duration(float64(length/mmPerSec) * float64(time.Second))
+	}
+	if p.DryRun {
+		// Lines are moves.
+		return setupDuration + seconds(s.MoveLength+s.LineLength, move)
+	}
+	prof := p.profile()
+	// Every pass but the first moves back to the start of each
+	// line and engraves it again.
+	extra := prof.Passes - 1
+	moves := s.MoveLength + float32(extra)*s.LineLength
+	lines := float32(prof.Passes) * s.LineLength
+	strokes := s.Strokes + extra*s.Lines
+	// Lowering and raising the hammer.
+	strokeDelay := time.Duration(int(prof.PenDown)+int(prof.PenUp)) * time.Millisecond
+	d := setupDuration + seconds(moves, move) + seconds(lines, print)
+	d += time.Duration(strokes)*strokeDelay + time.Duration(s.Turns)*turnDelay
+	return d
+}
+
 func mkcoords(p f32.Vec2) [9]byte {
 	p = affine.Scale(p, millimeter)
 	x, y := int(math.Round(float64(p[0]))), int(math.Round(float64(p[1])))
@@ -362,6 +730,7 @@ func (p *Program) cmd(c [cmdSize]byte) {
 
 func (p *Program) Prepare() {
 	p.cmds = make(chan [cmdSize]byte)
+	p.pen = f32.Vec2{}
 }
 
 func (p *Program) Move(to f32.Vec2) {
@@ -370,6 +739,7 @@ func (p *Program) Move(to f32.Vec2) {
 	coords := mkcoords(to)
 	copy(cmd[1:], coords[:])
 	p.cmd(cmd)
+	p.pen = to
 	p.pause()
 }
 
@@ -386,6 +756,26 @@ func (p *Program) Line(to f32.Vec2) {
 	cmd[0] = lineCmd
 	coords := mkcoords(to)
 	copy(cmd[1:], coords[:])
-	p.cmd(cmd)
+	from := p.pen
+	for i := 0; i < p.profile().Passes; i++ {
+		if i > 0 {
+			p.Move(from)
+		}
+		p.cmd(cmd)
+	}
+	p.pen = to
 	p.pause()
 }
+
+// Dot strikes a single point with a line of zero length, moving to it
+// first unless the hammer is already there.
+func (p *Program) Dot(at f32.Vec2) {
+	if p.DryRun {
+		p.Move(at)
+		return
+	}
+	if p.pen != at {
+		p.Move(at)
+	}
+	p.Line(at)
+}
@@ -1,9 +1,18 @@
 package mjolnir
 
 import (
+	"bytes"
+	"errors"
+	"image/png"
+	"io"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/image/math/f32"
+	"seedhammer.com/engrave"
 )
 
 func TestEndToEnd(t *testing.T) {
@@ -22,10 +31,512 @@ func TestEndToEnd(t *testing.T) {
 	prog.Prepare()
 	engraveErr := make(chan error)
 	go func() {
-		engraveErr <- Engrave(s, prog, nil, nil)
+		engraveErr <- Engrave(s, prog, nil, nil, nil)
 	}()
 	design()
 	if err := <-engraveErr; err != nil {
 		t.Error(err)
 	}
 }
+
+func TestEstimate(t *testing.T) {
+	s := engrave.Stats{
+		LineLength: 4000,
+		MoveLength: 2000,
+		Lines:      20000,
+		Strokes:    3000,
+		Turns:      500,
+	}
+	d := new(Program).Estimate(s)
+	if d < 10*time.Minute || d > 2*time.Hour {
+		t.Errorf("estimate %v out of range", d)
+	}
+	fast := &Program{PrintSpeed: .8}
+	if fd := fast.Estimate(s); fd >= d {
+		t.Errorf("estimate %v at higher speed not less than %v", fd, d)
+	}
+	slow := &Program{Profile: Titanium}
+	if sd := slow.Estimate(s); sd <= d {
+		t.Errorf("estimate %v of multiple passes not greater than %v", sd, d)
+	}
+	dry := &Program{DryRun: true}
+	if dd := dry.Estimate(s); dd >= d {
+		t.Errorf("dry run estimate %v not less than %v", dd, d)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	for _, prof := range Profiles {
+		s := NewSimulator()
+		rec := &recorder{ReadWriter: s}
+		prog := &Program{Profile: prof}
+		design := engrave.Commands{square(10), square(5)}
+		design.Engrave(prog)
+		prog.Prepare()
+		engraveErr := make(chan error)
+		go func() {
+			engraveErr <- Engrave(rec, prog, nil, nil, nil)
+		}()
+		design.Engrave(prog)
+		if err := <-engraveErr; err != nil {
+			t.Fatalf("%s: %v", prof.Name, err)
+		}
+		s.Close()
+		delays := []byte{setDelaysCmd, prof.PenDown, prof.PenUp}
+		if !bytes.Contains(rec.written.Bytes(), delays) {
+			t.Errorf("%s: delays %#x not sent", prof.Name, delays)
+		}
+		move, print := prog.speeds()
+		speeds := []byte{setSpeedCmd, byte(print), byte(print >> 8), byte(move), byte(move >> 8), 0xe6, 0x00}
+		if !bytes.Contains(rec.written.Bytes(), speeds) {
+			t.Errorf("%s: speeds %#x not sent", prof.Name, speeds)
+		}
+		lines := 0
+		for _, c := range s.Cmds {
+			if c.Type == LineTo {
+				lines++
+			}
+		}
+		if want := 8 * prof.Passes; lines != want {
+			t.Errorf("%s: engraved %d lines, want %d", prof.Name, lines, want)
+		}
+		if got, ok := LookupProfile(strings.ToLower(prof.Name)); !ok || got != prof {
+			t.Errorf("LookupProfile(%q) = %q, %v", strings.ToLower(prof.Name), got.Name, ok)
+		}
+	}
+	if _, ok := LookupProfile("unobtainium"); ok {
+		t.Error("looked up an unknown profile")
+	}
+}
+
+// recorder records the data written to a device.
+type recorder struct {
+	io.ReadWriter
+	written bytes.Buffer
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.written.Write(data)
+	return r.ReadWriter.Write(data)
+}
+
+func TestDots(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	prog := &Program{}
+	design := func() {
+		prog.Dot(f32.Vec2{1, 1})
+		prog.Dot(f32.Vec2{1, 1})
+		prog.Dot(f32.Vec2{2, 1})
+	}
+	design()
+	prog.Prepare()
+	engraveErr := make(chan error)
+	go func() {
+		engraveErr <- Engrave(s, prog, nil, nil, nil)
+	}()
+	design()
+	if err := <-engraveErr; err != nil {
+		t.Fatal(err)
+	}
+	x1, x2 := uint32(math.Round(1*millimeter)), uint32(math.Round(2*millimeter))
+	want := []Cmd{
+		{MoveTo, x1, x1},
+		{LineTo, x1, x1},
+		{LineTo, x1, x1},
+		{MoveTo, x2, x1},
+		{LineTo, x2, x1},
+	}
+	// Skip the final move to the end position.
+	n := len(s.Cmds) - 1
+	if n < len(want) || !reflect.DeepEqual(s.Cmds[n-len(want):n], want) {
+		t.Errorf("got commands %v, want %v", s.Cmds, want)
+	}
+}
+
+func TestResume(t *testing.T) {
+	design := func(p *Program) {
+		for i := 0; i < 250; i++ {
+			p.Move(f32.Vec2{float32(i % 50), float32(i / 50)})
+			p.Line(f32.Vec2{float32(i%50) + .5, float32(i / 50)})
+		}
+	}
+	run := func(dev io.ReadWriter, prog *Program) error {
+		if prog.cmds == nil {
+			design(prog)
+		}
+		prog.Prepare()
+		engraveErr := make(chan error)
+		go func() {
+			engraveErr <- Engrave(dev, prog, nil, nil, nil)
+		}()
+		design(prog)
+		return <-engraveErr
+	}
+	ref := NewSimulator()
+	defer ref.Close()
+	if err := run(ref, new(Program)); err != nil {
+		t.Fatal(err)
+	}
+	// Skip the homing moves and the end move.
+	const homing = 3
+	want := ref.Cmds[homing : len(ref.Cmds)-1]
+
+	prog := new(Program)
+	s1 := NewSimulator()
+	defer s1.Close()
+	// Include the homing move.
+	s1.Inject(Fault{Kind: Disconnect, After: 1 + 2*progBatchSize + 10})
+	if err := run(s1, prog); err != ErrDisconnected {
+		t.Fatalf("interrupted engraving returned %v, want %v", err, ErrDisconnected)
+	}
+	cp := prog.Checkpoint()
+	if cp != 2*progBatchSize {
+		t.Fatalf("checkpoint at %d commands, want %d", cp, 2*progBatchSize)
+	}
+	s2 := NewSimulator()
+	defer s2.Close()
+	if err := run(s2, prog); err != nil {
+		t.Fatal(err)
+	}
+	got := s2.Cmds[homing : len(s2.Cmds)-1]
+	resume := Cmd{MoveTo, want[cp-1].X, want[cp-1].Y}
+	if got[0] != resume {
+		t.Errorf("resumed with %v, want %v", got[0], resume)
+	}
+	if !reflect.DeepEqual(got[1:], want[cp:]) {
+		t.Error("resumed commands don't match the remaining program")
+	}
+	if c := prog.Checkpoint(); c != 0 {
+		t.Errorf("checkpoint at %d commands after completion", c)
+	}
+}
+
+func TestPause(t *testing.T) {
+	design := func(p *Program) {
+		for i := 0; i < 250; i++ {
+			p.Move(f32.Vec2{float32(i % 50), float32(i / 50)})
+			p.Line(f32.Vec2{float32(i%50) + .5, float32(i / 50)})
+		}
+	}
+	s := NewSimulator()
+	defer s.Close()
+	prog := new(Program)
+	design(prog)
+	prog.Prepare()
+	pause := make(chan bool, 1)
+	engraveErr := make(chan error, 1)
+	go func() {
+		engraveErr <- Engrave(s, prog, nil, pause, nil)
+	}()
+	go design(prog)
+	// The homing move.
+	const homing = 1
+	for s.Steps() < homing+progBatchSize/2 {
+		time.Sleep(time.Millisecond)
+	}
+	pause <- true
+	// Wait for the current batch to complete.
+	steps := s.Steps()
+	for {
+		time.Sleep(50 * time.Millisecond)
+		n := s.Steps()
+		if n == steps {
+			break
+		}
+		steps = n
+	}
+	if want := homing + progBatchSize; steps != want {
+		t.Errorf("paused after %d commands, want %d", steps, want)
+	}
+	select {
+	case err := <-engraveErr:
+		t.Fatalf("paused engraving returned %v", err)
+	default:
+	}
+	pause <- false
+	if err := <-engraveErr; err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Steps(), homing+2*250+1; got != want {
+		t.Errorf("executed %d commands, want %d", got, want)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+	design := func(p *Program) {
+		for i := 0; i < 250; i++ {
+			p.Move(f32.Vec2{float32(i % 50), float32(i / 50)})
+			p.Line(f32.Vec2{float32(i%50) + .5, float32(i / 50)})
+		}
+	}
+	prog := new(Program)
+	design(prog)
+	prog.Prepare()
+	events := make(chan Event, 1000)
+	engraveErr := make(chan error)
+	go func() {
+		engraveErr <- Engrave(s, prog, events, nil, nil)
+	}()
+	design(prog)
+	if err := <-engraveErr; err != nil {
+		t.Fatal(err)
+	}
+	close(events)
+	var stages []Stage
+	var last Event
+	batches := 0
+	for e := range events {
+		if e.Time.Before(last.Time) || e.Start != last.Start && !last.Start.IsZero() {
+			t.Errorf("event %v out of order", e)
+		}
+		if e.Total != 500 {
+			t.Errorf("event %v reports %d commands, want 500", e, e.Total)
+		}
+		if e.Completed < last.Completed || e.Progress < last.Progress {
+			t.Errorf("event %v regresses from %v", e, last)
+		}
+		if e.Stage == Buffering {
+			batches++
+			if e.Batch != batches || e.Batches != 7 {
+				t.Errorf("event %v, want batch %d of 7", e, batches)
+			}
+		}
+		if n := len(stages); n == 0 || stages[n-1] != e.Stage {
+			stages = append(stages, e.Stage)
+		}
+		last = e
+	}
+	if batches != 7 {
+		t.Errorf("buffered %d batches, want 7", batches)
+	}
+	if last.Stage != Done || last.Completed != 500 || last.Progress != 1 {
+		t.Errorf("last event %v, want 500 completed commands", last)
+	}
+	want := []Stage{Connecting, Initializing, Homing, SettingSpeeds, Buffering}
+	if len(stages) < len(want) || !reflect.DeepEqual(stages[:len(want)], want) {
+		t.Errorf("engraving started with stages %v, want %v", stages, want)
+	}
+	want = []Stage{Executing, Returning, Done}
+	if n := len(stages); n < len(want) || !reflect.DeepEqual(stages[n-len(want):], want) {
+		t.Errorf("engraving ended with stages %v, want %v", stages, want)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	trace := func(faults ...Fault) []TraceEvent {
+		s := NewSimulator()
+		defer s.Close()
+		for _, f := range faults {
+			s.Inject(f)
+		}
+		buf := new(bytes.Buffer)
+		tr := NewTracer(s, buf)
+		design := engrave.Commands{square(10), square(5)}
+		prog := new(Program)
+		design.Engrave(prog)
+		prog.Prepare()
+		engraveErr := make(chan error)
+		go func() {
+			engraveErr <- Engrave(tr, prog, nil, nil, nil)
+		}()
+		design.Engrave(prog)
+		if err := <-engraveErr; err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Err(); err != nil {
+			t.Fatal(err)
+		}
+		dec, err := ReadTrace(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dec.Decode()
+	}
+	events := trace()
+	var msgs []string
+	for _, e := range events {
+		if e.Violation != "" {
+			t.Errorf("unexpected violation: %v", e)
+		}
+		msgs = append(msgs, e.Msg)
+	}
+	want := []string{
+		"start program of 1 batches",
+		"request batch",
+		"batch 1 of 1: 10 commands, 70 padding, ending at (0.00,0.00) mm",
+		"executed 80 commands",
+		"program complete",
+	}
+	all := strings.Join(msgs, "\n")
+	if !strings.Contains(all, strings.Join(want, "\n")) {
+		t.Errorf("decoded trace\n%s\ndoesn't contain\n%s", all, strings.Join(want, "\n"))
+	}
+
+	violations := 0
+	for _, e := range trace(Fault{Kind: Garble, After: 3}) {
+		if e.Violation != "" {
+			violations++
+			if e.Violation != "invalid status" {
+				t.Errorf("garbled trace: unexpected violation: %v", e)
+			}
+		}
+	}
+	if violations != 1 {
+		t.Errorf("garbled trace: %d violations, want 1", violations)
+	}
+
+	if _, err := ReadTrace(strings.NewReader(traceMagic)); !errors.Is(err, ErrTraceFormat) {
+		t.Errorf("truncated trace decoded with error %v, want %v", err, ErrTraceFormat)
+	}
+}
+
+func TestSimulator(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+	design := engrave.Commands{
+		engrave.Offset(20, 20, square(10)),
+		engrave.Offset(40, 20, square(5)),
+	}
+	prog := new(Program)
+	design.Engrave(prog)
+	prog.Prepare()
+	engraveErr := make(chan error)
+	go func() {
+		engraveErr <- Engrave(s, prog, nil, nil, nil)
+	}()
+	design.Engrave(prog)
+	if err := <-engraveErr; err != nil {
+		t.Fatal(err)
+	}
+	var length float32
+	traj := s.Trajectory()
+	for i := 1; i < len(traj); i++ {
+		a, b := traj[i-1], traj[i]
+		if b.T < a.T {
+			t.Fatalf("trajectory time decreases from %v to %v", a.T, b.T)
+		}
+		if b.Down {
+			length += float32(math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y)))
+		}
+	}
+	if want := float32(4*10 + 4*5); math.Abs(float64(length-want)) > .1 {
+		t.Errorf("engraved %f mm, want %f mm", length, want)
+	}
+	// The simulator doesn't model setup and turns, but should
+	// otherwise match the estimate.
+	est := prog.Estimate(engrave.Measure(design)) - setupDuration
+	if d := s.Elapsed(); d < est/2 || d > est*2 {
+		t.Errorf("simulated %v, estimated %v", d, est)
+	}
+	buf := new(bytes.Buffer)
+	if err := s.WritePNG(buf, 10); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sample the middle of the top edge of the first square.
+	if r, _, _, _ := img.At(250, 200).RGBA(); r > 0x8000 {
+		t.Error("engraved line missing from image")
+	}
+	if r, _, _, _ := img.At(250, 250).RGBA(); r < 0x8000 {
+		t.Error("image engraved inside square")
+	}
+}
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		fault Fault
+		err   error
+	}{
+		{Fault{Kind: Disconnect, After: 50}, ErrDisconnected},
+		{Fault{Kind: Cancel, After: 50}, ErrCancelled},
+		{Fault{Kind: Garble, After: 50}, nil},
+		{Fault{Kind: Slow, After: 50, Delay: 50 * time.Millisecond}, nil},
+		{Fault{Kind: LoseSteps, After: 50}, &PositionError{}},
+		{Fault{Kind: FalseOrigin}, &PositionError{Homing: true}},
+	}
+	for _, test := range tests {
+		s := NewSimulator()
+		s.Inject(test.fault)
+		prog := new(Program)
+		var design engrave.Commands
+		for i := 0; i < 20; i++ {
+			design = append(design, square(10))
+		}
+		design.Engrave(prog)
+		prog.Prepare()
+		engraveErr := make(chan error)
+		go func() {
+			engraveErr <- Engrave(s, prog, nil, nil, nil)
+		}()
+		design.Engrave(prog)
+		err := <-engraveErr
+		if want, ok := test.err.(*PositionError); ok {
+			var perr *PositionError
+			if !errors.As(err, &perr) || perr.Homing != want.Homing {
+				t.Errorf("fault %d: got error %v, want position error (homing: %v)", test.fault.Kind, err, want.Homing)
+			}
+		} else if err != test.err {
+			t.Errorf("fault %d: got error %v, want %v", test.fault.Kind, err, test.err)
+		}
+		s.Close()
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	defer func(init, homing, cmd, completion time.Duration) {
+		initTimeout, homingTimeout, commandTimeout, completionTimeout = init, homing, cmd, completion
+	}(initTimeout, homingTimeout, commandTimeout, completionTimeout)
+	const timeout = 300 * time.Millisecond
+	initTimeout, homingTimeout, commandTimeout, completionTimeout = timeout, timeout, timeout, timeout
+	tests := []struct {
+		fault Fault
+		err   TimeoutError
+	}{
+		{Fault{Kind: Hang}, TimeoutError{Phase: InitPhase}},
+		{Fault{Kind: HangHoming}, TimeoutError{Phase: HomingPhase}},
+		{Fault{Kind: Hang, After: 50}, TimeoutError{Phase: ProgramPhase}},
+		{Fault{Kind: Stall, After: 50}, TimeoutError{Phase: ProgramPhase, Stalled: true}},
+		{Fault{Kind: HangCompletion, After: 2}, TimeoutError{Phase: CompletionPhase}},
+	}
+	for _, test := range tests {
+		s := NewSimulator()
+		s.Inject(test.fault)
+		prog := new(Program)
+		var design engrave.Commands
+		for i := 0; i < 20; i++ {
+			// Short lines for short command deadlines.
+			design = append(design, square(1))
+		}
+		design.Engrave(prog)
+		prog.Prepare()
+		engraveErr := make(chan error)
+		go func() {
+			engraveErr <- Engrave(s, prog, nil, nil, nil)
+		}()
+		design.Engrave(prog)
+		err := <-engraveErr
+		var terr *TimeoutError
+		if !errors.As(err, &terr) || *terr != test.err {
+			t.Errorf("fault %d: got error %v, want %v", test.fault.Kind, err, &test.err)
+		}
+		s.Close()
+	}
+}
+
+// square engraves a square outline.
+type square float32
+
+func (s square) Engrave(p engrave.Program) {
+	p.Move(f32.Vec2{0, 0})
+	p.Line(f32.Vec2{float32(s), 0})
+	p.Line(f32.Vec2{float32(s), float32(s)})
+	p.Line(f32.Vec2{0, float32(s)})
+	p.Line(f32.Vec2{0, 0})
+}
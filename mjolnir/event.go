@@ -0,0 +1,102 @@
+package mjolnir
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stage is a step of an engraving, as reported by an Event.
+type Stage int
+
+const (
+	// Connecting waits for the engraver to initialize.
+	Connecting Stage = iota
+	// Initializing configures the engraver.
+	Initializing
+	// Homing moves the engraver to its origin.
+	Homing
+	// SettingSpeeds sets the speeds of the program.
+	SettingSpeeds
+	// Buffering sends a batch of program commands.
+	Buffering
+	// Executing runs the buffered program commands.
+	Executing
+	// Returning moves the engraver to the program end.
+	Returning
+	// Done reports a completed engraving.
+	Done
+	// Cancelled reports a cancelled engraving.
+	Cancelled
+)
+
+func (s Stage) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+	case Initializing:
+		return "initializing"
+	case Homing:
+		return "homing"
+	case SettingSpeeds:
+		return "setting speeds"
+	case Buffering:
+		return "buffering"
+	case Executing:
+		return "executing"
+	case Returning:
+		return "returning"
+	case Done:
+		return "done"
+	case Cancelled:
+		return "cancelled"
+	default:
+		return fmt.Sprintf("stage %d", int(s))
+	}
+}
+
+// Event describes the state of an engraving when it enters a stage
+// or makes progress.
+type Event struct {
+	Stage Stage
+	// Start is the time the engraving started, and Time is the time
+	// of the event.
+	Start, Time time.Time
+	// Batch is the number, counting from 1, of the latest batch sent
+	// out of Batches.
+	Batch, Batches int
+	// Completed is the number of program commands completed out of
+	// Total, including those completed before resuming.
+	Completed, Total int
+	// Progress is the completed fraction of the program, from 0 to 1.
+	Progress float32
+}
+
+func (e Event) String() string {
+	elapsed := e.Time.Sub(e.Start).Round(time.Millisecond)
+	switch e.Stage {
+	case Buffering:
+		return fmt.Sprintf("%v: buffering batch %d of %d", elapsed, e.Batch, e.Batches)
+	case Executing, Done, Cancelled:
+		return fmt.Sprintf("%v: %v, %d of %d commands completed", elapsed, e.Stage, e.Completed, e.Total)
+	default:
+		return fmt.Sprintf("%v: %v", elapsed, e.Stage)
+	}
+}
+
+// send delivers e to events without blocking, replacing the oldest
+// undelivered event if events is full.
+func send(events chan Event, e Event) {
+	if events == nil {
+		return
+	}
+	select {
+	case events <- e:
+		return
+	default:
+	}
+	select {
+	case <-events:
+	default:
+	}
+	events <- e
+}
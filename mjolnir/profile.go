@@ -0,0 +1,74 @@
+package mjolnir
+
+import "strings"
+
+// Profile is a set of engraving parameters suited to a plate
+// material.
+type Profile struct {
+	Name string
+	// Depth is the setting of the depth selector of the engraver.
+	Depth string
+	// MoveSpeed and PrintSpeed range from 0, the slowest, to 1,
+	// the fastest.
+	MoveSpeed, PrintSpeed float32
+	// PenDown and PenUp are the delays in milliseconds of lowering
+	// and raising the hammer.
+	PenDown, PenUp uint8
+	// Passes is the number of times each line is engraved.
+	Passes int
+}
+
+var (
+	Stainless304 = Profile{
+		Name:       "Stainless 304",
+		Depth:      "Strong",
+		MoveSpeed:  defaultMoveSpeed,
+		PrintSpeed: defaultPrintSpeed,
+		PenDown:    0x14,
+		PenUp:      0x14,
+		Passes:     1,
+	}
+	Titanium = Profile{
+		Name:       "Titanium",
+		Depth:      "Strong",
+		MoveSpeed:  defaultMoveSpeed,
+		PrintSpeed: .3,
+		PenDown:    0x1e,
+		PenUp:      0x14,
+		Passes:     2,
+	}
+	Aluminium = Profile{
+		Name:       "Aluminium",
+		Depth:      "Medium",
+		MoveSpeed:  defaultMoveSpeed,
+		PrintSpeed: .5,
+		PenDown:    0x10,
+		PenUp:      0x10,
+		Passes:     1,
+	}
+)
+
+// Profiles lists the supported profiles, starting with the default.
+var Profiles = []Profile{Stainless304, Titanium, Aluminium}
+
+// LookupProfile returns the profile with a name, ignoring case.
+func LookupProfile(name string) (Profile, bool) {
+	for _, p := range Profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// profile returns the program profile, or the default if not set.
+func (p *Program) profile() Profile {
+	if p.Profile.Name == "" {
+		return Stainless304
+	}
+	prof := p.Profile
+	if prof.Passes < 1 {
+		prof.Passes = 1
+	}
+	return prof
+}
@@ -2,18 +2,56 @@ package mjolnir
 
 import (
 	"errors"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sync"
 	"time"
+
+	"golang.org/x/image/math/f32"
+	"seedhammer.com/engrave"
 )
 
+// Simulator is an in-process engraver. It records the commands it
+// receives, models the motion of the hammer with the timing model of
+// Program.Estimate, and injects faults on demand.
 type Simulator struct {
 	state     deviceState
 	ncmds     int
 	nbuffered int
 
+	// Cmds lists the commands received. It must not be accessed
+	// concurrently with reads and writes.
 	Cmds  []Cmd
 	close chan struct{}
 	in    chan ioRequest
 	out   chan ioResult
+
+	// queue holds the buffered program commands, nil for padding.
+	queue []*Cmd
+	// steps counts the executed program commands.
+	steps int
+	// speeds are the print and move speeds in machine units.
+	printSpeed, moveSpeed int
+	// penDown and penUp are the hammer delays in milliseconds.
+	penDown, penUp int
+	// coords is the commanded position in machine units, and offset
+	// the difference of the hammer position from it.
+	coords, offset [2]int
+	falseOrigin    bool
+	pos            f32.Vec2
+	down           bool
+	elapsed        time.Duration
+	trajectory     []Point
+
+	mu           sync.Mutex
+	faults       []Fault
+	disconnected bool
+	// hung stops replies, and stalled replaces them with
+	// garbledStatus.
+	hung, stalled bool
 }
 
 type Cmd struct {
@@ -28,11 +66,80 @@ const (
 	LineTo
 )
 
+// Point is a position of the hammer at a simulated time, in
+// millimeters.
+type Point struct {
+	T    time.Duration
+	X, Y float32
+	// Down reports whether the hammer engraved the way to the
+	// point.
+	Down bool
+}
+
+// Fault is a failure injected by a Simulator.
+type Fault struct {
+	Kind FaultKind
+	// After is the number of program commands to execute before the
+	// fault occurs.
+	After int
+	// Delay is the reply delay of Slow faults.
+	Delay time.Duration
+}
+
+type FaultKind int
+
+const (
+	// Disconnect fails every read and write with
+	// ErrDisconnected.
+	Disconnect FaultKind = iota
+	// Garble replies with an invalid status byte.
+	Garble
+	// Slow delays a reply.
+	Slow
+	// Cancel replies with cancelledStatus during a program, as if the
+	// engraver was cancelled from its panel.
+	Cancel
+	// LoseSteps offsets the hammer from its commanded position.
+	LoseSteps
+	// FalseOrigin makes every following homing stop short of the
+	// origin, as if obstructed.
+	FalseOrigin
+	// Hang stops replying.
+	Hang
+	// HangHoming stops replying instead of completing the next
+	// homing.
+	HangHoming
+	// HangCompletion stops replying instead of reporting the
+	// completion of the next program.
+	HangCompletion
+	// Stall replies with invalid status bytes instead of executing
+	// program commands.
+	Stall
+)
+
+// Offsets in machine units of the LoseSteps and FalseOrigin faults.
+const (
+	lostSteps         = 64
+	falseOriginOffset = 400
+)
+
+// ErrDisconnected is returned by a Simulator after a Disconnect
+// fault.
+var ErrDisconnected = errors.New("mjolnir: simulated disconnect")
+
+// garbledStatus is not a valid status byte.
+const garbledStatus = 0x42
+
+// errHung is returned by doRead for reads that never complete.
+var errHung = errors.New("mjolnir: simulated hang")
+
 func NewSimulator() *Simulator {
 	sim := &Simulator{
-		close: make(chan struct{}),
-		in:    make(chan ioRequest),
-		out:   make(chan ioResult),
+		close:      make(chan struct{}),
+		in:         make(chan ioRequest),
+		out:        make(chan ioResult),
+		printSpeed: 300,
+		moveSpeed:  300,
 	}
 	go sim.run()
 	return sim
@@ -47,6 +154,8 @@ const (
 	stateSetDelays
 	stateMoveToOrigin
 	stateExecuting
+	stateCancelled
+	stateQueryPos
 )
 
 type ioRequest struct {
@@ -59,6 +168,41 @@ type ioResult struct {
 	err   error
 }
 
+// Inject schedules a fault.
+func (s *Simulator) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.After += s.steps
+	s.faults = append(s.faults, f)
+}
+
+// fault removes and returns the first due fault, if any.
+func (s *Simulator) fault() (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if f.After <= s.steps && s.applies(f) {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			return f, true
+		}
+	}
+	return Fault{}, false
+}
+
+// applies reports whether a fault applies to the current state.
+func (s *Simulator) applies(f Fault) bool {
+	switch f.Kind {
+	case HangHoming:
+		return s.state == stateMoveToOrigin
+	case HangCompletion:
+		return s.state == stateExecuting && s.nbuffered == 0 && s.ncmds == 0
+	case Stall:
+		return s.state == stateExecuting
+	default:
+		return true
+	}
+}
+
 func (s *Simulator) run() {
 	for {
 		select {
@@ -84,6 +228,12 @@ func coordsFromCmd(cmd []byte) (uint32, uint32) {
 }
 
 func (s *Simulator) doRead(data []byte) (int, error) {
+	if s.disconnected {
+		return 0, ErrDisconnected
+	}
+	if s.hung {
+		return 0, errHung
+	}
 	read := func(resp []byte) (int, error) {
 		if len(resp) > len(data) {
 			return 0, errors.New("read overflow")
@@ -91,6 +241,37 @@ func (s *Simulator) doRead(data []byte) (int, error) {
 		copy(data, resp)
 		return len(resp), nil
 	}
+	if f, ok := s.fault(); ok {
+		switch f.Kind {
+		case Disconnect:
+			s.disconnected = true
+			return 0, ErrDisconnected
+		case Garble:
+			return read([]byte{garbledStatus})
+		case Slow:
+			time.Sleep(f.Delay)
+		case Cancel:
+			if s.state == stateExecuting {
+				s.state = stateReady
+				s.queue = nil
+				s.nbuffered = 0
+				return read([]byte{cancelledStatus})
+			}
+		case LoseSteps:
+			s.offset[0] += lostSteps
+		case FalseOrigin:
+			s.falseOrigin = true
+		case Hang, HangHoming, HangCompletion:
+			s.hung = true
+			return 0, errHung
+		case Stall:
+			s.stalled = true
+		}
+	}
+	if s.stalled && s.state == stateExecuting {
+		time.Sleep(time.Millisecond)
+		return read([]byte{garbledStatus})
+	}
 	switch s.state {
 	case stateInitializing:
 		s.state = stateReady
@@ -103,16 +284,34 @@ func (s *Simulator) doRead(data []byte) (int, error) {
 		return read([]byte{setDelaysCmd})
 	case stateMoveToOrigin:
 		s.state = stateReady
+		s.coords, s.offset = [2]int{}, [2]int{}
+		if s.falseOrigin {
+			s.offset = [2]int{falseOriginOffset, falseOriginOffset}
+		}
+		s.moveTo(s.position(), false)
 		return read([]byte{moveToOriginCmd, moveToOriginCmdResponse})
+	case stateQueryPos:
+		s.state = stateReady
+		x, y := s.coords[0]+s.offset[0], s.coords[1]+s.offset[1]
+		return read([]byte{
+			queryPosCmd,
+			byte(x), byte(x >> 8), byte(x >> 16),
+			byte(y), byte(y >> 8), byte(y >> 16),
+			0, 0, 0,
+		})
+	case stateCancelled:
+		s.state = stateReady
+		return read([]byte{cancelledStatus})
 	case stateExecuting:
 		switch {
 		case s.nbuffered == 0 && s.ncmds > 0:
 			return read([]byte{bufferProgramStatus})
 		case s.nbuffered == 0 && s.ncmds == 0:
+			s.state = stateReady
 			return read([]byte{programCompleteStatus})
 		default:
 			time.Sleep(400 * time.Microsecond)
-			s.nbuffered--
+			s.step()
 			return read([]byte{programStepStatus})
 		}
 	default:
@@ -120,7 +319,57 @@ func (s *Simulator) doRead(data []byte) (int, error) {
 	}
 }
 
+// step executes the next buffered command.
+func (s *Simulator) step() {
+	c := s.queue[0]
+	s.queue = s.queue[1:]
+	s.nbuffered--
+	if c == nil {
+		return
+	}
+	s.mu.Lock()
+	s.steps++
+	s.mu.Unlock()
+	s.coords = [2]int{int(c.X), int(c.Y)}
+	s.moveTo(s.position(), c.Type == LineTo)
+}
+
+// position returns the hammer position in millimeters.
+func (s *Simulator) position() f32.Vec2 {
+	return f32.Vec2{
+		float32(s.coords[0]+s.offset[0]) * stepSize,
+		float32(s.coords[1]+s.offset[1]) * stepSize,
+	}
+}
+
+// moveTo advances the hammer and the simulated time.
+func (s *Simulator) moveTo(p f32.Vec2, down bool) {
+	if len(s.trajectory) == 0 {
+		s.trajectory = append(s.trajectory, Point{X: s.pos[0], Y: s.pos[1]})
+	}
+	if down != s.down {
+		delay := s.penUp
+		if down {
+			delay = s.penDown
+		}
+		s.elapsed += time.Duration(delay) * time.Millisecond
+		s.down = down
+	}
+	speed := s.moveSpeed
+	if down {
+		speed = s.printSpeed
+	}
+	dx, dy := float64(p[0]-s.pos[0]), float64(p[1]-s.pos[1])
+	mmPerSec := float64(speedFactor) / float64(speed)
+	s.elapsed += time.Duration(math.Sqrt(dx*dx+dy*dy) / mmPerSec * float64(time.Second))
+	s.pos = p
+	s.trajectory = append(s.trajectory, Point{T: s.elapsed, X: p[0], Y: p[1], Down: down})
+}
+
 func (s *Simulator) doWrite(data []byte) (n int, err error) {
+	if s.disconnected {
+		return 0, ErrDisconnected
+	}
 	skip := func(bytes int) {
 		if len(data) < bytes {
 			err = errors.New("buffer underflow")
@@ -135,33 +384,46 @@ func (s *Simulator) doWrite(data []byte) (n int, err error) {
 		skip(bytes)
 		return res
 	}
-	batchCmd := func() {
+	batchCmd := func(c *Cmd) {
 		s.nbuffered++
 		s.ncmds--
+		s.queue = append(s.queue, c)
 		skip(9)
 	}
+	record := func(typ CmdType) Cmd {
+		x, y := coordsFromCmd(data)
+		c := Cmd{typ, x, y}
+		s.Cmds = append(s.Cmds, c)
+		return c
+	}
 	for len(data) > 0 {
 		n += 1
 		cmd := data[0]
 		data = data[1:]
 		switch cmd {
 		case cancelCmd:
-			s.state = stateReady
+			if s.state == stateExecuting {
+				s.state = stateCancelled
+			}
+			s.queue = nil
+			s.nbuffered = 0
 		case initCmd:
 			if s.state == stateExecuting {
 				// 0x00 is line to in programming mode.
-				x, y := coordsFromCmd(data)
-				s.Cmds = append(s.Cmds, Cmd{LineTo, x, y})
-				batchCmd()
+				c := record(LineTo)
+				batchCmd(&c)
 			} else {
 				s.state = stateInitializing
 			}
 		case setSpeedCmd:
 			s.state = stateSetSpeed
-			skip(6)
+			speeds := read(6)
+			s.printSpeed = int(speeds[0]) | int(speeds[1])<<8
+			s.moveSpeed = int(speeds[2]) | int(speeds[3])<<8
 		case setDelaysCmd:
 			s.state = stateSetDelays
-			skip(2)
+			delays := read(2)
+			s.penDown, s.penUp = int(delays[0]), int(delays[1])
 		case moveToOriginCmd:
 			s.state = stateMoveToOrigin
 			subCmd := read(1)
@@ -174,11 +436,12 @@ func (s *Simulator) doWrite(data []byte) (n int, err error) {
 			ncmds := read(2)
 			s.ncmds = (int(ncmds[0]) | int(ncmds[1])<<8) * progBatchSize
 		case moveCmd:
-			x, y := coordsFromCmd(data)
-			s.Cmds = append(s.Cmds, Cmd{MoveTo, x, y})
-			batchCmd()
+			c := record(MoveTo)
+			batchCmd(&c)
 		case nopCmd:
-			batchCmd()
+			batchCmd(nil)
+		case queryPosCmd:
+			s.state = stateQueryPos
 		default:
 			return n, errors.New("invalid command")
 		}
@@ -186,14 +449,75 @@ func (s *Simulator) doWrite(data []byte) (n int, err error) {
 	return
 }
 
+// Steps returns the number of program commands executed so far. It
+// may be called concurrently with reads and writes.
+func (s *Simulator) Steps() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.steps
+}
+
+// Elapsed returns the simulated duration of the commands executed so
+// far.
+func (s *Simulator) Elapsed() time.Duration {
+	return s.elapsed
+}
+
+// Trajectory returns the positions of the hammer after each executed
+// command, starting at its initial position. It must not be called
+// concurrently with reads and writes.
+func (s *Simulator) Trajectory() []Point {
+	return s.trajectory
+}
+
+// WritePNG renders the engraved lines of the trajectory to w at
+// ppmm pixels per millimeter.
+func (s *Simulator) WritePNG(w io.Writer, ppmm float32) error {
+	var max f32.Vec2
+	for _, p := range s.trajectory {
+		if p.X > max[0] {
+			max[0] = p.X
+		}
+		if p.Y > max[1] {
+			max[1] = p.Y
+		}
+	}
+	const margin = StrokeWidth + 1
+	bounds := image.Rect(0, 0, int((max[0]+margin)*ppmm), int((max[1]+margin)*ppmm))
+	img := image.NewGray(bounds)
+	draw.Draw(img, bounds, image.White, image.Point{}, draw.Src)
+	r := engrave.NewRasterizer(img, bounds, StrokeWidth*ppmm)
+	for _, p := range s.trajectory {
+		pos := f32.Vec2{p.X * ppmm, p.Y * ppmm}
+		if p.Down {
+			r.Line(pos)
+		} else {
+			r.Move(pos)
+		}
+	}
+	r.Rasterize()
+	return png.Encode(w, img)
+}
+
 func (s *Simulator) Read(data []byte) (int, error) {
-	s.in <- ioRequest{false, data}
-	r := <-s.out
-	return r.bytes, r.err
+	r, err := s.do(ioRequest{false, data})
+	if err == errHung {
+		<-s.close
+		return 0, io.ErrClosedPipe
+	}
+	return r, err
 }
 
 func (s *Simulator) Write(data []byte) (int, error) {
-	s.in <- ioRequest{true, data}
+	return s.do(ioRequest{true, data})
+}
+
+func (s *Simulator) do(req ioRequest) (int, error) {
+	select {
+	case s.in <- req:
+	case <-s.close:
+		return 0, io.ErrClosedPipe
+	}
 	r := <-s.out
 	return r.bytes, r.err
 }
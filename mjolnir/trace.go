@@ -0,0 +1,507 @@
+package mjolnir
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Tracer is an io.ReadWriter that records the data read from and
+// written to a device, with timestamps, in the format read by
+// ReadTrace.
+type Tracer struct {
+	dev   io.ReadWriter
+	start time.Time
+
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// TraceOp is the direction of a TraceRecord.
+type TraceOp uint8
+
+const (
+	// TraceRead records data received from the engraver.
+	TraceRead TraceOp = iota
+	// TraceWrite records data sent to the engraver.
+	TraceWrite
+)
+
+// TraceRecord is a single read or write of a trace.
+type TraceRecord struct {
+	Op TraceOp
+	// Time is the time of the operation since the start of the
+	// trace.
+	Time time.Duration
+	Data []byte
+	// Err is the error returned by the operation, if any.
+	Err string
+}
+
+// Trace is a recorded serial stream.
+type Trace struct {
+	Start   time.Time
+	Records []TraceRecord
+}
+
+// ErrTraceFormat is returned when decoding a malformed trace.
+var ErrTraceFormat = errors.New("mjolnir: invalid trace")
+
+// Trace file header and version.
+const (
+	traceMagic   = "SHTRACE"
+	traceVersion = 1
+)
+
+// NewTracer returns a Tracer that records the stream of dev to w.
+func NewTracer(dev io.ReadWriter, w io.Writer) *Tracer {
+	t := &Tracer{dev: dev, w: w, start: time.Now()}
+	hdr := append([]byte(traceMagic), traceVersion)
+	hdr = binary.LittleEndian.AppendUint64(hdr, uint64(t.start.UnixNano()))
+	_, t.err = w.Write(hdr)
+	return t
+}
+
+func (t *Tracer) Read(p []byte) (int, error) {
+	n, err := t.dev.Read(p)
+	t.record(TraceRead, p[:n], err)
+	return n, err
+}
+
+func (t *Tracer) Write(p []byte) (int, error) {
+	n, err := t.dev.Write(p)
+	t.record(TraceWrite, p[:n], err)
+	return n, err
+}
+
+// Err returns the first error writing the trace.
+func (t *Tracer) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Tracer) record(op TraceOp, data []byte, err error) {
+	if len(data) == 0 && err == nil {
+		return
+	}
+	var msg string
+	if err != nil {
+		msg = err.Error()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return
+	}
+	rec := []byte{byte(op)}
+	rec = binary.AppendUvarint(rec, uint64(time.Since(t.start)))
+	rec = binary.AppendUvarint(rec, uint64(len(data)))
+	rec = append(rec, data...)
+	rec = binary.AppendUvarint(rec, uint64(len(msg)))
+	rec = append(rec, msg...)
+	_, t.err = t.w.Write(rec)
+}
+
+// ReadTrace decodes a trace recorded by a Tracer.
+func ReadTrace(r io.Reader) (*Trace, error) {
+	br := bufio.NewReader(r)
+	hdr := make([]byte, len(traceMagic)+1+8)
+	if _, err := io.ReadFull(br, hdr); err != nil || string(hdr[:len(traceMagic)]) != traceMagic {
+		return nil, ErrTraceFormat
+	}
+	if v := hdr[len(traceMagic)]; v != traceVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrTraceFormat, v)
+	}
+	t := &Trace{
+		Start: time.Unix(0, int64(binary.LittleEndian.Uint64(hdr[len(traceMagic)+1:]))),
+	}
+	readBytes := func() ([]byte, error) {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		// Guard against corrupt lengths.
+		if n > 1<<20 {
+			return nil, fmt.Errorf("%w: record too large", ErrTraceFormat)
+		}
+		data := make([]byte, n)
+		_, err = io.ReadFull(br, data)
+		return data, err
+	}
+	for {
+		op, err := br.ReadByte()
+		if err == io.EOF {
+			return t, nil
+		}
+		if op > byte(TraceWrite) {
+			return nil, fmt.Errorf("%w: unknown operation %d", ErrTraceFormat, op)
+		}
+		ts, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("%w: truncated", ErrTraceFormat)
+		}
+		data, err := readBytes()
+		if err != nil {
+			return nil, fmt.Errorf("%w: truncated", ErrTraceFormat)
+		}
+		msg, err := readBytes()
+		if err != nil {
+			return nil, fmt.Errorf("%w: truncated", ErrTraceFormat)
+		}
+		t.Records = append(t.Records, TraceRecord{
+			Op:   TraceOp(op),
+			Time: time.Duration(ts),
+			Data: data,
+			Err:  string(msg),
+		})
+	}
+}
+
+// TraceEvent is a protocol message decoded from a trace.
+type TraceEvent struct {
+	// Time is the time of the message since the start of the trace.
+	Time time.Duration
+	// Sent reports whether the message was sent to the engraver.
+	Sent bool
+	Msg  string
+	// Violation describes how the message violates the protocol
+	// expected by the driver and Simulator, if it does.
+	Violation string
+}
+
+func (e TraceEvent) String() string {
+	dir := "<"
+	if e.Sent {
+		dir = ">"
+	}
+	s := fmt.Sprintf("%10.3fs %s %s", e.Time.Seconds(), dir, e.Msg)
+	if e.Violation != "" {
+		s += " !! " + e.Violation
+	}
+	return s
+}
+
+// Decode decodes the protocol messages of the trace and checks them
+// against the state machine of the Simulator. Program batches and
+// runs of executed commands are summarized in single events.
+func (t *Trace) Decode() []TraceEvent {
+	d := &traceDecoder{}
+	for _, r := range t.Records {
+		d.time = r.Time
+		switch r.Op {
+		case TraceWrite:
+			d.out = append(d.out, r.Data...)
+			d.decodeSent()
+		case TraceRead:
+			d.in = append(d.in, r.Data...)
+			d.decodeReceived()
+		}
+		if r.Err != "" {
+			d.flushSteps()
+			op := "read"
+			if r.Op == TraceWrite {
+				op = "write"
+			}
+			d.emit(r.Op == TraceWrite, fmt.Sprintf("%s failed: %s", op, r.Err), "")
+		}
+	}
+	d.flushSteps()
+	if len(d.out) > 0 {
+		d.emit(true, fmt.Sprintf("%#x", d.out), "truncated command")
+	}
+	if len(d.in) > 0 {
+		d.emit(false, fmt.Sprintf("%#x", d.in), "truncated reply")
+	}
+	if d.batchFill > 0 {
+		d.emit(true, fmt.Sprintf("batch %d: %d commands", d.batch, d.batchFill), "incomplete batch")
+	}
+	return d.events
+}
+
+// traceDecoder tracks the engraver state of a trace.
+type traceDecoder struct {
+	events []TraceEvent
+	time   time.Duration
+	// out and in are the undecoded bytes sent and received.
+	out, in []byte
+
+	state deviceState
+	// ncmds is the number of program commands not yet sent, and
+	// nbuffered the number sent but not executed.
+	ncmds, nbuffered int
+	// batches is the number of program batches, requested the
+	// number requested but not sent, and batch the number of the
+	// current batch.
+	batches, requested, batch int
+	// batchFill and batchPad count the commands and padding of the
+	// current batch.
+	batchFill, batchPad int
+	batchViolation      string
+	// batchEnd is the position of the last command of the batch.
+	batchEnd [2]uint32
+	// steps counts executed commands not yet reported, starting at
+	// stepsTime.
+	steps     int
+	stepsTime time.Duration
+}
+
+func (d *traceDecoder) emit(sent bool, msg, violation string) {
+	d.events = append(d.events, TraceEvent{
+		Time:      d.time,
+		Sent:      sent,
+		Msg:       msg,
+		Violation: violation,
+	})
+}
+
+// flushSteps reports the run of executed commands, if any.
+func (d *traceDecoder) flushSteps() {
+	if d.steps == 0 {
+		return
+	}
+	d.events = append(d.events, TraceEvent{
+		Time: d.stepsTime,
+		Msg:  fmt.Sprintf("executed %d commands", d.steps),
+	})
+	d.steps = 0
+}
+
+// cmdLen returns the length of the command starting with b.
+func (d *traceDecoder) cmdLen(b byte) (int, bool) {
+	if d.state == stateExecuting {
+		switch b {
+		case moveCmd, lineCmd, nopCmd:
+			return cmdSize, true
+		case cancelCmd:
+			return 1, true
+		}
+		return 0, false
+	}
+	switch b {
+	case cancelCmd, initCmd, queryPosCmd:
+		return 1, true
+	case setSpeedCmd:
+		return 7, true
+	case setDelaysCmd:
+		return 3, true
+	case moveToOriginCmd:
+		return 2, true
+	case initProgramCmd:
+		return 3, true
+	}
+	return 0, false
+}
+
+func (d *traceDecoder) decodeSent() {
+	for len(d.out) > 0 {
+		n, ok := d.cmdLen(d.out[0])
+		if !ok {
+			d.flushSteps()
+			d.emit(true, fmt.Sprintf("%#x", d.out[0]), fmt.Sprintf("invalid command in state %d", d.state))
+			d.out = d.out[1:]
+			continue
+		}
+		if len(d.out) < n {
+			return
+		}
+		cmd := d.out[:n]
+		d.out = d.out[n:]
+		if d.state == stateExecuting && n == cmdSize {
+			d.programCmd(cmd)
+			continue
+		}
+		d.flushSteps()
+		switch cmd[0] {
+		case cancelCmd:
+			if d.state == stateExecuting {
+				d.state = stateCancelled
+			}
+			d.nbuffered, d.batchFill, d.batchPad = 0, 0, 0
+			d.emit(true, "cancel", "")
+		case initCmd:
+			d.state = stateInitializing
+			d.emit(true, "initialize", "")
+		case setSpeedCmd:
+			d.state = stateSetSpeed
+			print := int(cmd[1]) | int(cmd[2])<<8
+			move := int(cmd[3]) | int(cmd[4])<<8
+			d.emit(true, fmt.Sprintf("set speeds: print %d, move %d", print, move), "")
+		case setDelaysCmd:
+			d.state = stateSetDelays
+			d.emit(true, fmt.Sprintf("set delays: down %d ms, up %d ms", cmd[1], cmd[2]), "")
+		case moveToOriginCmd:
+			d.state = stateMoveToOrigin
+			var violation string
+			if cmd[1] != moveToOriginCmdExtra {
+				violation = "invalid origin command"
+			}
+			d.emit(true, "move to origin", violation)
+		case initProgramCmd:
+			d.state = stateExecuting
+			d.batches = int(cmd[1]) | int(cmd[2])<<8
+			d.ncmds = d.batches * progBatchSize
+			d.nbuffered, d.requested, d.batch = 0, 0, 0
+			d.emit(true, fmt.Sprintf("start program of %d batches", d.batches), "")
+		case queryPosCmd:
+			d.state = stateQueryPos
+			d.emit(true, "query position", "")
+		}
+	}
+}
+
+// programCmd decodes a command of a program batch.
+func (d *traceDecoder) programCmd(cmd []byte) {
+	if d.batchFill == 0 {
+		d.flushSteps()
+		d.batch++
+		d.batchViolation = ""
+		if d.requested == 0 {
+			d.batchViolation = "batch sent before requested"
+		} else {
+			d.requested--
+		}
+	}
+	switch cmd[0] {
+	case nopCmd:
+		d.batchPad++
+	default:
+		if d.batchPad > 0 && d.batchViolation == "" {
+			d.batchViolation = "command after padding"
+		}
+		d.batchEnd[0], d.batchEnd[1] = coordsFromCmd(cmd[1:])
+	}
+	d.ncmds--
+	if d.ncmds < 0 && d.batchViolation == "" {
+		d.batchViolation = "more commands than declared"
+	}
+	d.nbuffered++
+	d.batchFill++
+	if d.batchFill < progBatchSize {
+		return
+	}
+	msg := fmt.Sprintf("batch %d of %d: %d commands", d.batch, d.batches, d.batchFill-d.batchPad)
+	if d.batchPad > 0 {
+		msg += fmt.Sprintf(", %d padding", d.batchPad)
+	}
+	if d.batchPad < d.batchFill {
+		msg += fmt.Sprintf(", ending at %s", formatCoords(d.batchEnd[0], d.batchEnd[1]))
+	}
+	d.emit(true, msg, d.batchViolation)
+	d.batchFill, d.batchPad = 0, 0
+}
+
+func (d *traceDecoder) decodeReceived() {
+	for len(d.in) > 0 {
+		n := len(d.in)
+		status := d.in[0]
+		switch status {
+		case cancellingStatus:
+			d.in = d.in[1:]
+			d.flushSteps()
+			d.emit(false, "cancelling", "")
+			continue
+		case cancelledStatus:
+			d.in = d.in[1:]
+			d.flushSteps()
+			// The driver re-initializes a cancelled engraver.
+			if d.state != stateInitializing {
+				d.state = stateReady
+			}
+			d.nbuffered, d.ncmds, d.batchFill, d.batchPad = 0, 0, 0, 0
+			d.emit(false, "cancelled", "")
+			continue
+		}
+		switch d.state {
+		case stateInitializing:
+			d.reply(1, "initialized", initializedStatus)
+		case stateSetSpeed:
+			d.reply(1, "speeds set", setSpeedCmd)
+		case stateSetDelays:
+			d.reply(1, "delays set", setDelaysCmd)
+		case stateMoveToOrigin:
+			d.reply(2, "at origin", moveToOriginCmd, moveToOriginCmdResponse)
+		case stateQueryPos:
+			if len(d.in) < 10 {
+				return
+			}
+			x, y := coordsFromCmd(d.in[1:])
+			d.reply(10, "position "+formatCoords(x, y), queryPosCmd)
+		case stateExecuting:
+			d.in = d.in[1:]
+			d.programStatus(status)
+		default:
+			d.in = d.in[1:]
+			d.flushSteps()
+			d.emit(false, fmt.Sprintf("%#x", status), "unexpected reply")
+		}
+		if len(d.in) == n {
+			// Wait for the rest of a reply.
+			return
+		}
+	}
+}
+
+// reply decodes a reply of n bytes starting with exp.
+func (d *traceDecoder) reply(n int, msg string, exp ...byte) {
+	if len(d.in) < n {
+		return
+	}
+	d.flushSteps()
+	if got := d.in[:len(exp)]; !bytes.Equal(got, exp) {
+		// Skip the unexpected byte and keep waiting.
+		d.in = d.in[1:]
+		d.emit(false, fmt.Sprintf("%#x", got[0]), fmt.Sprintf("expected %#x", exp))
+		return
+	}
+	d.in = d.in[n:]
+	d.state = stateReady
+	d.emit(false, msg, "")
+}
+
+// programStatus decodes a status byte received during a program.
+func (d *traceDecoder) programStatus(status byte) {
+	switch status {
+	case programStepStatus:
+		if d.nbuffered == 0 {
+			d.flushSteps()
+			d.emit(false, "executed command", "no command buffered")
+			return
+		}
+		d.nbuffered--
+		if d.steps == 0 {
+			d.stepsTime = d.time
+		}
+		d.steps++
+		return
+	}
+	d.flushSteps()
+	switch status {
+	case bufferProgramStatus:
+		var violation string
+		if d.ncmds <= 0 {
+			violation = "all batches sent"
+		}
+		d.requested++
+		d.emit(false, "request batch", violation)
+	case programCompleteStatus:
+		var violation string
+		if d.ncmds > 0 || d.nbuffered > 0 {
+			violation = fmt.Sprintf("%d commands not executed", d.ncmds+d.nbuffered)
+		}
+		d.state = stateReady
+		d.emit(false, "program complete", violation)
+	default:
+		d.emit(false, fmt.Sprintf("%#x", status), "invalid status")
+	}
+}
+
+func formatCoords(x, y uint32) string {
+	return fmt.Sprintf("(%.2f,%.2f) mm", float32(x)*stepSize, float32(y)*stepSize)
+}
@@ -0,0 +1,1540 @@
+// Package slip39 implements the [SLIP-39] Shamir's secret-sharing scheme
+// for mnemonic codes.
+//
+// [SLIP-39]: https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+type Word int
+
+type Mnemonic []Word
+
+// Share is the decoded content of a SLIP-39 mnemonic.
+type Share struct {
+	Identifier        uint16
+	Extendable        bool
+	IterationExponent int
+	GroupIndex        int
+	GroupThreshold    int
+	GroupCount        int
+	MemberIndex       int
+	MemberThreshold   int
+	Value             []byte
+}
+
+// Group describes the member shares of a group.
+type Group struct {
+	Threshold int
+	Count     int
+}
+
+const (
+	radixBits = 10
+	// idBits is the length of the random identifier.
+	idBits = 15
+	// iterationExpBits is the length of the iteration exponent.
+	iterationExpBits = 4
+	// maxShares is the maximum number of groups or shares in a group.
+	maxShares = 16
+	// checksumWords is the number of words in the RS1024 checksum.
+	checksumWords = 3
+	// headerWords is the number of words before the share value.
+	headerWords = 4
+	// minSecretLen is the minimum length of the master secret in bytes.
+	minSecretLen = 16
+	// minWords is the length of the shortest valid mnemonic.
+	minWords = headerWords + (minSecretLen*8+radixBits-1)/radixBits + checksumWords
+	// baseIterations is the PBKDF2 iteration count for iteration
+	// exponent 0, divided among the rounds of the Feistel network.
+	baseIterations = 10000
+	rounds         = 4
+	digestLen      = 4
+	digestIndex    = 254
+	secretIndex    = 255
+)
+
+var (
+	ErrChecksum   = errors.New("slip39: invalid checksum")
+	ErrDigest     = errors.New("slip39: invalid digest of the shared secret")
+	ErrShareCount = errors.New("slip39: insufficient number of shares")
+)
+
+// LabelFor returns the word for w, or the empty string if w
+// is invalid.
+func LabelFor(w Word) string {
+	if !w.valid() {
+		return ""
+	}
+	return Wordlist[w]
+}
+
+func (w Word) valid() bool {
+	return w >= 0 && int(w) < len(Wordlist)
+}
+
+// ClosestWord returns the first word that sorts after or equal to word,
+// and reports whether word is a prefix of it.
+func ClosestWord(word string) (Word, bool) {
+	i := sort.Search(len(Wordlist), func(i int) bool {
+		return Wordlist[i] >= word
+	})
+	if i == len(Wordlist) {
+		return -1, false
+	}
+	return Word(i), strings.HasPrefix(Wordlist[i], word)
+}
+
+func ParseMnemonic(mnemonic string) (Mnemonic, error) {
+	words := strings.Fields(mnemonic)
+	m := make(Mnemonic, len(words))
+	for i, w := range words {
+		closest, valid := ClosestWord(w)
+		if !valid || Wordlist[closest] != w {
+			return nil, fmt.Errorf("slip39: unknown word: %q", w)
+		}
+		m[i] = closest
+	}
+	return m, nil
+}
+
+func (m Mnemonic) String() string {
+	var b strings.Builder
+	for i, w := range m {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(LabelFor(w))
+	}
+	return b.String()
+}
+
+// Split splits secret into groups of shares, of which groupThreshold
+// groups are required to recover the secret. The secret is encrypted with
+// passphrase before splitting. The share identifier and the random
+// polynomial coefficients are read from rng.
+func Split(rng io.Reader, secret []byte, passphrase string, iterationExponent, groupThreshold int, groups []Group) ([][]Share, error) {
+	if len(secret) < minSecretLen || len(secret)%2 != 0 {
+		return nil, fmt.Errorf("slip39: secret length must be even and at least %d bytes", minSecretLen)
+	}
+	if iterationExponent < 0 || iterationExponent >= 1<<iterationExpBits {
+		return nil, fmt.Errorf("slip39: iteration exponent %d out of range", iterationExponent)
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) || len(groups) > maxShares {
+		return nil, fmt.Errorf("slip39: invalid group threshold %d of %d", groupThreshold, len(groups))
+	}
+	for _, g := range groups {
+		if g.Threshold < 1 || g.Threshold > g.Count || g.Count > maxShares {
+			return nil, fmt.Errorf("slip39: invalid member threshold %d of %d", g.Threshold, g.Count)
+		}
+		if g.Threshold == 1 && g.Count > 1 {
+			return nil, errors.New("slip39: use 1-of-1 member sharing instead of 1-of-n")
+		}
+	}
+	var idb [2]byte
+	if _, err := io.ReadFull(rng, idb[:]); err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(idb[:]) & (1<<idBits - 1)
+	const extendable = true
+	ems := crypt(secret, passphrase, id, extendable, iterationExponent, false)
+	groupShares, err := splitSecret(rng, groupThreshold, len(groups), ems)
+	if err != nil {
+		return nil, err
+	}
+	var shares [][]Share
+	for gi, g := range groups {
+		members, err := splitSecret(rng, g.Threshold, g.Count, groupShares[gi])
+		if err != nil {
+			return nil, err
+		}
+		var group []Share
+		for mi, v := range members {
+			group = append(group, Share{
+				Identifier:        id,
+				Extendable:        extendable,
+				IterationExponent: iterationExponent,
+				GroupIndex:        gi,
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       mi,
+				MemberThreshold:   g.Threshold,
+				Value:             v,
+			})
+		}
+		shares = append(shares, group)
+	}
+	return shares, nil
+}
+
+// Combine recovers the secret from a sufficient set of shares.
+func Combine(shares []Share, passphrase string) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrShareCount
+	}
+	first := shares[0]
+	groups := make(map[int][]Share)
+	for _, s := range shares {
+		if s.Identifier != first.Identifier || s.Extendable != first.Extendable ||
+			s.IterationExponent != first.IterationExponent {
+			return nil, errors.New("slip39: shares belong to different secrets")
+		}
+		if s.GroupThreshold != first.GroupThreshold || s.GroupCount != first.GroupCount {
+			return nil, errors.New("slip39: shares have mismatching group parameters")
+		}
+		if len(s.Value) != len(first.Value) {
+			return nil, errors.New("slip39: shares have different lengths")
+		}
+		g := groups[s.GroupIndex]
+		if len(g) > 0 && g[0].MemberThreshold != s.MemberThreshold {
+			return nil, errors.New("slip39: shares have mismatching member thresholds")
+		}
+		groups[s.GroupIndex] = append(g, s)
+	}
+	var groupShares []point
+	for gi, g := range groups {
+		if len(g) < g[0].MemberThreshold {
+			continue
+		}
+		var members []point
+		for _, s := range g {
+			members = append(members, point{x: byte(s.MemberIndex), y: s.Value})
+		}
+		v, err := recoverSecret(g[0].MemberThreshold, members)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, point{x: byte(gi), y: v})
+	}
+	if len(groupShares) < first.GroupThreshold {
+		return nil, ErrShareCount
+	}
+	ems, err := recoverSecret(first.GroupThreshold, groupShares)
+	if err != nil {
+		return nil, err
+	}
+	return crypt(ems, passphrase, first.Identifier, first.Extendable, first.IterationExponent, true), nil
+}
+
+// crypt encrypts or decrypts a secret with the 4-round Feistel
+// network specified by SLIP-39.
+func crypt(secret []byte, passphrase string, id uint16, extendable bool, iterationExponent int, decrypt bool) []byte {
+	var salt []byte
+	if !extendable {
+		salt = binary.BigEndian.AppendUint16([]byte("shamir"), id)
+	}
+	iterations := (baseIterations << iterationExponent) / rounds
+	half := len(secret) / 2
+	l := append([]byte(nil), secret[:half]...)
+	r := append([]byte(nil), secret[half:]...)
+	for i := 0; i < rounds; i++ {
+		round := i
+		if decrypt {
+			round = rounds - 1 - i
+		}
+		pass := append([]byte{byte(round)}, passphrase...)
+		f := pbkdf2.Key(pass, append(salt[:len(salt):len(salt)], r...), iterations, half, sha256.New)
+		for j := range l {
+			l[j] ^= f[j]
+		}
+		l, r = r, l
+	}
+	return append(r, l...)
+}
+
+// point is a share of a secret at a particular x coordinate.
+type point struct {
+	x byte
+	y []byte
+}
+
+func splitSecret(rng io.Reader, threshold, count int, secret []byte) ([][]byte, error) {
+	shares := make([][]byte, count)
+	if threshold == 1 {
+		for i := range shares {
+			shares[i] = secret
+		}
+		return shares, nil
+	}
+	var base []point
+	for i := 0; i < threshold-2; i++ {
+		v := make([]byte, len(secret))
+		if _, err := io.ReadFull(rng, v); err != nil {
+			return nil, err
+		}
+		shares[i] = v
+		base = append(base, point{x: byte(i), y: v})
+	}
+	random := make([]byte, len(secret)-digestLen)
+	if _, err := io.ReadFull(rng, random); err != nil {
+		return nil, err
+	}
+	d := append(digest(random, secret), random...)
+	base = append(base, point{x: digestIndex, y: d}, point{x: secretIndex, y: secret})
+	for i := threshold - 2; i < count; i++ {
+		shares[i] = interpolate(base, byte(i))
+	}
+	return shares, nil
+}
+
+func recoverSecret(threshold int, shares []point) ([]byte, error) {
+	if len(shares) < threshold {
+		return nil, ErrShareCount
+	}
+	// Any threshold shares determine the polynomial.
+	shares = shares[:threshold]
+	if threshold == 1 {
+		return shares[0].y, nil
+	}
+	seen := make(map[byte]bool)
+	for _, s := range shares {
+		if seen[s.x] {
+			return nil, fmt.Errorf("slip39: duplicate share index %d", s.x)
+		}
+		seen[s.x] = true
+	}
+	secret := interpolate(shares, secretIndex)
+	d := interpolate(shares, digestIndex)
+	if !hmac.Equal(d[:digestLen], digest(d[digestLen:], secret)) {
+		return nil, ErrDigest
+	}
+	return secret, nil
+}
+
+func digest(random, secret []byte) []byte {
+	mac := hmac.New(sha256.New, random)
+	mac.Write(secret)
+	return mac.Sum(nil)[:digestLen]
+}
+
+// interpolate evaluates the Lagrange polynomial through shares
+// at x in GF(256).
+func interpolate(shares []point, x byte) []byte {
+	for _, s := range shares {
+		if s.x == x {
+			return s.y
+		}
+	}
+	logProd := 0
+	for _, s := range shares {
+		logProd += int(gfLog[s.x^x])
+	}
+	res := make([]byte, len(shares[0].y))
+	for _, s := range shares {
+		logBasis := logProd - int(gfLog[s.x^x])
+		for _, o := range shares {
+			if o.x != s.x {
+				logBasis -= int(gfLog[s.x^o.x])
+			}
+		}
+		logBasis = (logBasis%255 + 255) % 255
+		for i, v := range s.y {
+			if v != 0 {
+				res[i] ^= gfExp[(int(gfLog[v])+logBasis)%255]
+			}
+		}
+	}
+	return res
+}
+
+// gfExp and gfLog are the exponent and logarithm tables of
+// GF(256) with the Rijndael polynomial and generator 3.
+var gfExp, gfLog [256]byte
+
+func init() {
+	p := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(p)
+		gfLog[p] = byte(i)
+		// Multiply by the generator, x + 1.
+		p = p<<1 ^ p
+		if p&0x100 != 0 {
+			p ^= 0x11b
+		}
+	}
+}
+
+// Mnemonic encodes the share as a mnemonic.
+func (s Share) Mnemonic() Mnemonic {
+	var m Mnemonic
+	ext := 0
+	if s.Extendable {
+		ext = 1
+	}
+	idExp := int(s.Identifier)<<(iterationExpBits+1) | ext<<iterationExpBits | s.IterationExponent
+	m = append(m, Word(idExp>>radixBits), Word(idExp&(1<<radixBits-1)))
+	params := s.GroupIndex<<16 | (s.GroupThreshold-1)<<12 | (s.GroupCount-1)<<8 |
+		s.MemberIndex<<4 | (s.MemberThreshold - 1)
+	m = append(m, Word(params>>radixBits), Word(params&(1<<radixBits-1)))
+	m = append(m, bytesToWords(s.Value)...)
+	return append(m, checksum(customization(s.Extendable), m)...)
+}
+
+// Share decodes and validates the mnemonic.
+func (m Mnemonic) Share() (Share, error) {
+	if len(m) < minWords {
+		return Share{}, fmt.Errorf("slip39: mnemonic too short (%d words)", len(m))
+	}
+	for _, w := range m {
+		if !w.valid() {
+			return Share{}, fmt.Errorf("slip39: invalid word %d", w)
+		}
+	}
+	valueWords := len(m) - headerWords - checksumWords
+	padding := radixBits * valueWords % 16
+	if padding > 8 {
+		return Share{}, fmt.Errorf("slip39: invalid mnemonic length (%d words)", len(m))
+	}
+	idExp := int(m[0])<<radixBits | int(m[1])
+	s := Share{
+		Identifier:        uint16(idExp >> (iterationExpBits + 1)),
+		Extendable:        idExp>>iterationExpBits&1 == 1,
+		IterationExponent: idExp & (1<<iterationExpBits - 1),
+	}
+	if !verifyChecksum(customization(s.Extendable), m) {
+		return Share{}, ErrChecksum
+	}
+	params := int(m[2])<<radixBits | int(m[3])
+	s.GroupIndex = params >> 16
+	s.GroupThreshold = params>>12&0xf + 1
+	s.GroupCount = params>>8&0xf + 1
+	s.MemberIndex = params >> 4 & 0xf
+	s.MemberThreshold = params&0xf + 1
+	if s.GroupThreshold > s.GroupCount {
+		return Share{}, errors.New("slip39: group threshold exceeds group count")
+	}
+	value, ok := wordsToBytes(m[headerWords:len(m)-checksumWords], padding)
+	if !ok {
+		return Share{}, errors.New("slip39: invalid padding")
+	}
+	s.Value = value
+	return s, nil
+}
+
+// bytesToWords encodes data as big-endian radix 1024 words, padded
+// with leading zero bits.
+func bytesToWords(data []byte) []Word {
+	n := (len(data)*8 + radixBits - 1) / radixBits
+	words := make([]Word, n)
+	acc, bits := 0, n*radixBits-len(data)*8
+	i := 0
+	for _, b := range data {
+		acc = acc<<8 | int(b)
+		bits += 8
+		for bits >= radixBits {
+			bits -= radixBits
+			words[i] = Word(acc >> bits)
+			acc &= 1<<bits - 1
+			i++
+		}
+	}
+	return words
+}
+
+func wordsToBytes(words []Word, padding int) ([]byte, bool) {
+	var data []byte
+	acc, bits := 0, 0
+	for _, w := range words {
+		acc = acc<<radixBits | int(w)
+		bits += radixBits
+		if padding > 0 {
+			// Leading padding bits must be zero.
+			if acc>>(bits-padding) != 0 {
+				return nil, false
+			}
+			bits -= padding
+			padding = 0
+		}
+		for bits >= 8 {
+			bits -= 8
+			data = append(data, byte(acc>>bits))
+			acc &= 1<<bits - 1
+		}
+	}
+	return data, true
+}
+
+func customization(extendable bool) string {
+	if extendable {
+		return "shamir_extendable"
+	}
+	return "shamir"
+}
+
+var generator = [...]uint32{
+	0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+	0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+}
+
+func polymod(custom string, words []Word) uint32 {
+	chk := uint32(1)
+	add := func(v uint32) {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ v
+		for i, g := range generator {
+			if b>>i&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	for _, c := range []byte(custom) {
+		add(uint32(c))
+	}
+	for _, w := range words {
+		add(uint32(w))
+	}
+	return chk
+}
+
+// checksum computes the RS1024 checksum words of data.
+func checksum(custom string, data []Word) []Word {
+	padded := append(append([]Word(nil), data...), make([]Word, checksumWords)...)
+	chk := polymod(custom, padded) ^ 1
+	words := make([]Word, checksumWords)
+	for i := range words {
+		words[i] = Word(chk >> (radixBits * (checksumWords - 1 - i)) & (1<<radixBits - 1))
+	}
+	return words
+}
+
+func verifyChecksum(custom string, m Mnemonic) bool {
+	return polymod(custom, m) == 1
+}
+
+var Wordlist = [...]string{
+	"academic",
+	"acid",
+	"acne",
+	"acquire",
+	"acrobat",
+	"activity",
+	"actress",
+	"adapt",
+	"adequate",
+	"adjust",
+	"admit",
+	"adorn",
+	"adult",
+	"advance",
+	"advocate",
+	"afraid",
+	"again",
+	"agency",
+	"agree",
+	"aide",
+	"aircraft",
+	"airline",
+	"airport",
+	"ajar",
+	"alarm",
+	"album",
+	"alcohol",
+	"alien",
+	"alive",
+	"alpha",
+	"already",
+	"alto",
+	"aluminum",
+	"always",
+	"amazing",
+	"ambition",
+	"amount",
+	"amuse",
+	"analysis",
+	"anatomy",
+	"ancestor",
+	"ancient",
+	"angel",
+	"angry",
+	"animal",
+	"answer",
+	"antenna",
+	"anxiety",
+	"apart",
+	"aquatic",
+	"arcade",
+	"arena",
+	"argue",
+	"armed",
+	"artist",
+	"artwork",
+	"aspect",
+	"auction",
+	"august",
+	"aunt",
+	"average",
+	"aviation",
+	"avoid",
+	"award",
+	"away",
+	"axis",
+	"axle",
+	"beam",
+	"beard",
+	"beaver",
+	"become",
+	"bedroom",
+	"behavior",
+	"being",
+	"believe",
+	"belong",
+	"benefit",
+	"best",
+	"beyond",
+	"bike",
+	"biology",
+	"birthday",
+	"bishop",
+	"black",
+	"blanket",
+	"blessing",
+	"blimp",
+	"blind",
+	"blue",
+	"body",
+	"bolt",
+	"boring",
+	"born",
+	"both",
+	"boundary",
+	"bracelet",
+	"branch",
+	"brave",
+	"breathe",
+	"briefing",
+	"broken",
+	"brother",
+	"browser",
+	"bucket",
+	"budget",
+	"building",
+	"bulb",
+	"bulge",
+	"bumpy",
+	"bundle",
+	"burden",
+	"burning",
+	"busy",
+	"buyer",
+	"cage",
+	"calcium",
+	"camera",
+	"campus",
+	"canyon",
+	"capacity",
+	"capital",
+	"capture",
+	"carbon",
+	"cards",
+	"careful",
+	"cargo",
+	"carpet",
+	"carve",
+	"category",
+	"cause",
+	"ceiling",
+	"center",
+	"ceramic",
+	"champion",
+	"change",
+	"charity",
+	"check",
+	"chemical",
+	"chest",
+	"chew",
+	"chubby",
+	"cinema",
+	"civil",
+	"class",
+	"clay",
+	"cleanup",
+	"client",
+	"climate",
+	"clinic",
+	"clock",
+	"clogs",
+	"closet",
+	"clothes",
+	"club",
+	"cluster",
+	"coal",
+	"coastal",
+	"coding",
+	"column",
+	"company",
+	"corner",
+	"costume",
+	"counter",
+	"course",
+	"cover",
+	"cowboy",
+	"cradle",
+	"craft",
+	"crazy",
+	"credit",
+	"cricket",
+	"criminal",
+	"crisis",
+	"critical",
+	"crowd",
+	"crucial",
+	"crunch",
+	"crush",
+	"crystal",
+	"cubic",
+	"cultural",
+	"curious",
+	"curly",
+	"custody",
+	"cylinder",
+	"daisy",
+	"damage",
+	"dance",
+	"darkness",
+	"database",
+	"daughter",
+	"deadline",
+	"deal",
+	"debris",
+	"debut",
+	"decent",
+	"decision",
+	"declare",
+	"decorate",
+	"decrease",
+	"deliver",
+	"demand",
+	"density",
+	"deny",
+	"depart",
+	"depend",
+	"depict",
+	"deploy",
+	"describe",
+	"desert",
+	"desire",
+	"desktop",
+	"destroy",
+	"detailed",
+	"detect",
+	"device",
+	"devote",
+	"diagnose",
+	"dictate",
+	"diet",
+	"dilemma",
+	"diminish",
+	"dining",
+	"diploma",
+	"disaster",
+	"discuss",
+	"disease",
+	"dish",
+	"dismiss",
+	"display",
+	"distance",
+	"dive",
+	"divorce",
+	"document",
+	"domain",
+	"domestic",
+	"dominant",
+	"dough",
+	"downtown",
+	"dragon",
+	"dramatic",
+	"dream",
+	"dress",
+	"drift",
+	"drink",
+	"drove",
+	"drug",
+	"dryer",
+	"duckling",
+	"duke",
+	"duration",
+	"dwarf",
+	"dynamic",
+	"early",
+	"earth",
+	"easel",
+	"easy",
+	"echo",
+	"eclipse",
+	"ecology",
+	"edge",
+	"editor",
+	"educate",
+	"either",
+	"elbow",
+	"elder",
+	"election",
+	"elegant",
+	"element",
+	"elephant",
+	"elevator",
+	"elite",
+	"else",
+	"email",
+	"emerald",
+	"emission",
+	"emperor",
+	"emphasis",
+	"employer",
+	"empty",
+	"ending",
+	"endless",
+	"endorse",
+	"enemy",
+	"energy",
+	"enforce",
+	"engage",
+	"enjoy",
+	"enlarge",
+	"entrance",
+	"envelope",
+	"envy",
+	"epidemic",
+	"episode",
+	"equation",
+	"equip",
+	"eraser",
+	"erode",
+	"escape",
+	"estate",
+	"estimate",
+	"evaluate",
+	"evening",
+	"evidence",
+	"evil",
+	"evoke",
+	"exact",
+	"example",
+	"exceed",
+	"exchange",
+	"exclude",
+	"excuse",
+	"execute",
+	"exercise",
+	"exhaust",
+	"exotic",
+	"expand",
+	"expect",
+	"explain",
+	"express",
+	"extend",
+	"extra",
+	"eyebrow",
+	"facility",
+	"fact",
+	"failure",
+	"faint",
+	"fake",
+	"false",
+	"family",
+	"famous",
+	"fancy",
+	"fangs",
+	"fantasy",
+	"fatal",
+	"fatigue",
+	"favorite",
+	"fawn",
+	"fiber",
+	"fiction",
+	"filter",
+	"finance",
+	"findings",
+	"finger",
+	"firefly",
+	"firm",
+	"fiscal",
+	"fishing",
+	"fitness",
+	"flame",
+	"flash",
+	"flavor",
+	"flea",
+	"flexible",
+	"flip",
+	"float",
+	"floral",
+	"fluff",
+	"focus",
+	"forbid",
+	"force",
+	"forecast",
+	"forget",
+	"formal",
+	"fortune",
+	"forward",
+	"founder",
+	"fraction",
+	"fragment",
+	"frequent",
+	"freshman",
+	"friar",
+	"fridge",
+	"friendly",
+	"frost",
+	"froth",
+	"frozen",
+	"fumes",
+	"funding",
+	"furl",
+	"fused",
+	"galaxy",
+	"game",
+	"garbage",
+	"garden",
+	"garlic",
+	"gasoline",
+	"gather",
+	"general",
+	"genius",
+	"genre",
+	"genuine",
+	"geology",
+	"gesture",
+	"glad",
+	"glance",
+	"glasses",
+	"glen",
+	"glimpse",
+	"goat",
+	"golden",
+	"graduate",
+	"grant",
+	"grasp",
+	"gravity",
+	"gray",
+	"greatest",
+	"grief",
+	"grill",
+	"grin",
+	"grocery",
+	"gross",
+	"group",
+	"grownup",
+	"grumpy",
+	"guard",
+	"guest",
+	"guilt",
+	"guitar",
+	"gums",
+	"hairy",
+	"hamster",
+	"hand",
+	"hanger",
+	"harvest",
+	"have",
+	"havoc",
+	"hawk",
+	"hazard",
+	"headset",
+	"health",
+	"hearing",
+	"heat",
+	"helpful",
+	"herald",
+	"herd",
+	"hesitate",
+	"hobo",
+	"holiday",
+	"holy",
+	"home",
+	"hormone",
+	"hospital",
+	"hour",
+	"huge",
+	"human",
+	"humidity",
+	"hunting",
+	"husband",
+	"hush",
+	"husky",
+	"hybrid",
+	"idea",
+	"identify",
+	"idle",
+	"image",
+	"impact",
+	"imply",
+	"improve",
+	"impulse",
+	"include",
+	"income",
+	"increase",
+	"index",
+	"indicate",
+	"industry",
+	"infant",
+	"inform",
+	"inherit",
+	"injury",
+	"inmate",
+	"insect",
+	"inside",
+	"install",
+	"intend",
+	"intimate",
+	"invasion",
+	"involve",
+	"iris",
+	"island",
+	"isolate",
+	"item",
+	"ivory",
+	"jacket",
+	"jerky",
+	"jewelry",
+	"join",
+	"judicial",
+	"juice",
+	"jump",
+	"junction",
+	"junior",
+	"junk",
+	"jury",
+	"justice",
+	"kernel",
+	"keyboard",
+	"kidney",
+	"kind",
+	"kitchen",
+	"knife",
+	"knit",
+	"laden",
+	"ladle",
+	"ladybug",
+	"lair",
+	"lamp",
+	"language",
+	"large",
+	"laser",
+	"laundry",
+	"lawsuit",
+	"leader",
+	"leaf",
+	"learn",
+	"leaves",
+	"lecture",
+	"legal",
+	"legend",
+	"legs",
+	"lend",
+	"length",
+	"level",
+	"liberty",
+	"library",
+	"license",
+	"lift",
+	"likely",
+	"lilac",
+	"lily",
+	"lips",
+	"liquid",
+	"listen",
+	"literary",
+	"living",
+	"lizard",
+	"loan",
+	"lobe",
+	"location",
+	"losing",
+	"loud",
+	"loyalty",
+	"luck",
+	"lunar",
+	"lunch",
+	"lungs",
+	"luxury",
+	"lying",
+	"lyrics",
+	"machine",
+	"magazine",
+	"maiden",
+	"mailman",
+	"main",
+	"makeup",
+	"making",
+	"mama",
+	"manager",
+	"mandate",
+	"mansion",
+	"manual",
+	"marathon",
+	"march",
+	"market",
+	"marvel",
+	"mason",
+	"material",
+	"math",
+	"maximum",
+	"mayor",
+	"meaning",
+	"medal",
+	"medical",
+	"member",
+	"memory",
+	"mental",
+	"merchant",
+	"merit",
+	"method",
+	"metric",
+	"midst",
+	"mild",
+	"military",
+	"mineral",
+	"minister",
+	"miracle",
+	"mixed",
+	"mixture",
+	"mobile",
+	"modern",
+	"modify",
+	"moisture",
+	"moment",
+	"morning",
+	"mortgage",
+	"mother",
+	"mountain",
+	"mouse",
+	"move",
+	"much",
+	"mule",
+	"multiple",
+	"muscle",
+	"museum",
+	"music",
+	"mustang",
+	"nail",
+	"national",
+	"necklace",
+	"negative",
+	"nervous",
+	"network",
+	"news",
+	"nuclear",
+	"numb",
+	"numerous",
+	"nylon",
+	"oasis",
+	"obesity",
+	"object",
+	"observe",
+	"obtain",
+	"ocean",
+	"often",
+	"olympic",
+	"omit",
+	"oral",
+	"orange",
+	"orbit",
+	"order",
+	"ordinary",
+	"organize",
+	"ounce",
+	"oven",
+	"overall",
+	"owner",
+	"paces",
+	"pacific",
+	"package",
+	"paid",
+	"painting",
+	"pajamas",
+	"pancake",
+	"pants",
+	"papa",
+	"paper",
+	"parcel",
+	"parking",
+	"party",
+	"patent",
+	"patrol",
+	"payment",
+	"payroll",
+	"peaceful",
+	"peanut",
+	"peasant",
+	"pecan",
+	"penalty",
+	"pencil",
+	"percent",
+	"perfect",
+	"permit",
+	"petition",
+	"phantom",
+	"pharmacy",
+	"photo",
+	"phrase",
+	"physics",
+	"pickup",
+	"picture",
+	"piece",
+	"pile",
+	"pink",
+	"pipeline",
+	"pistol",
+	"pitch",
+	"plains",
+	"plan",
+	"plastic",
+	"platform",
+	"playoff",
+	"pleasure",
+	"plot",
+	"plunge",
+	"practice",
+	"prayer",
+	"preach",
+	"predator",
+	"pregnant",
+	"premium",
+	"prepare",
+	"presence",
+	"prevent",
+	"priest",
+	"primary",
+	"priority",
+	"prisoner",
+	"privacy",
+	"prize",
+	"problem",
+	"process",
+	"profile",
+	"program",
+	"promise",
+	"prospect",
+	"provide",
+	"prune",
+	"public",
+	"pulse",
+	"pumps",
+	"punish",
+	"puny",
+	"pupal",
+	"purchase",
+	"purple",
+	"python",
+	"quantity",
+	"quarter",
+	"quick",
+	"quiet",
+	"race",
+	"racism",
+	"radar",
+	"railroad",
+	"rainbow",
+	"raisin",
+	"random",
+	"ranked",
+	"rapids",
+	"raspy",
+	"reaction",
+	"realize",
+	"rebound",
+	"rebuild",
+	"recall",
+	"receiver",
+	"recover",
+	"regret",
+	"regular",
+	"reject",
+	"relate",
+	"remember",
+	"remind",
+	"remove",
+	"render",
+	"repair",
+	"repeat",
+	"replace",
+	"require",
+	"rescue",
+	"research",
+	"resident",
+	"response",
+	"result",
+	"retailer",
+	"retreat",
+	"reunion",
+	"revenue",
+	"review",
+	"reward",
+	"rhyme",
+	"rhythm",
+	"rich",
+	"rival",
+	"river",
+	"robin",
+	"rocky",
+	"romantic",
+	"romp",
+	"roster",
+	"round",
+	"royal",
+	"ruin",
+	"ruler",
+	"rumor",
+	"sack",
+	"safari",
+	"salary",
+	"salon",
+	"salt",
+	"satisfy",
+	"satoshi",
+	"saver",
+	"says",
+	"scandal",
+	"scared",
+	"scatter",
+	"scene",
+	"scholar",
+	"science",
+	"scout",
+	"scramble",
+	"screw",
+	"script",
+	"scroll",
+	"seafood",
+	"season",
+	"secret",
+	"security",
+	"segment",
+	"senior",
+	"shadow",
+	"shaft",
+	"shame",
+	"shaped",
+	"sharp",
+	"shelter",
+	"sheriff",
+	"short",
+	"should",
+	"shrimp",
+	"sidewalk",
+	"silent",
+	"silver",
+	"similar",
+	"simple",
+	"single",
+	"sister",
+	"skin",
+	"skunk",
+	"slap",
+	"slavery",
+	"sled",
+	"slice",
+	"slim",
+	"slow",
+	"slush",
+	"smart",
+	"smear",
+	"smell",
+	"smirk",
+	"smith",
+	"smoking",
+	"smug",
+	"snake",
+	"snapshot",
+	"sniff",
+	"society",
+	"software",
+	"soldier",
+	"solution",
+	"soul",
+	"source",
+	"space",
+	"spark",
+	"speak",
+	"species",
+	"spelling",
+	"spend",
+	"spew",
+	"spider",
+	"spill",
+	"spine",
+	"spirit",
+	"spit",
+	"spray",
+	"sprinkle",
+	"square",
+	"squeeze",
+	"stadium",
+	"staff",
+	"standard",
+	"starting",
+	"station",
+	"stay",
+	"steady",
+	"step",
+	"stick",
+	"stilt",
+	"story",
+	"strategy",
+	"strike",
+	"style",
+	"subject",
+	"submit",
+	"sugar",
+	"suitable",
+	"sunlight",
+	"superior",
+	"surface",
+	"surprise",
+	"survive",
+	"sweater",
+	"swimming",
+	"swing",
+	"switch",
+	"symbolic",
+	"sympathy",
+	"syndrome",
+	"system",
+	"tackle",
+	"tactics",
+	"tadpole",
+	"talent",
+	"task",
+	"taste",
+	"taught",
+	"taxi",
+	"teacher",
+	"teammate",
+	"teaspoon",
+	"temple",
+	"tenant",
+	"tendency",
+	"tension",
+	"terminal",
+	"testify",
+	"texture",
+	"thank",
+	"that",
+	"theater",
+	"theory",
+	"therapy",
+	"thorn",
+	"threaten",
+	"thumb",
+	"thunder",
+	"ticket",
+	"tidy",
+	"timber",
+	"timely",
+	"ting",
+	"tofu",
+	"together",
+	"tolerate",
+	"total",
+	"toxic",
+	"tracks",
+	"traffic",
+	"training",
+	"transfer",
+	"trash",
+	"traveler",
+	"treat",
+	"trend",
+	"trial",
+	"tricycle",
+	"trip",
+	"triumph",
+	"trouble",
+	"true",
+	"trust",
+	"twice",
+	"twin",
+	"type",
+	"typical",
+	"ugly",
+	"ultimate",
+	"umbrella",
+	"uncover",
+	"undergo",
+	"unfair",
+	"unfold",
+	"unhappy",
+	"union",
+	"universe",
+	"unkind",
+	"unknown",
+	"unusual",
+	"unwrap",
+	"upgrade",
+	"upstairs",
+	"username",
+	"usher",
+	"usual",
+	"valid",
+	"valuable",
+	"vampire",
+	"vanish",
+	"various",
+	"vegan",
+	"velvet",
+	"venture",
+	"verdict",
+	"verify",
+	"very",
+	"veteran",
+	"vexed",
+	"victim",
+	"video",
+	"view",
+	"vintage",
+	"violence",
+	"viral",
+	"visitor",
+	"visual",
+	"vitamins",
+	"vocal",
+	"voice",
+	"volume",
+	"voter",
+	"voting",
+	"walnut",
+	"warmth",
+	"warn",
+	"watch",
+	"wavy",
+	"wealthy",
+	"weapon",
+	"webcam",
+	"welcome",
+	"welfare",
+	"western",
+	"width",
+	"wildlife",
+	"window",
+	"wine",
+	"wireless",
+	"wisdom",
+	"withdraw",
+	"wits",
+	"wolf",
+	"woman",
+	"work",
+	"worthy",
+	"wrap",
+	"wrist",
+	"writing",
+	"wrote",
+	"year",
+	"yelp",
+	"yield",
+	"yoga",
+	"zero",
+}
@@ -0,0 +1,127 @@
+package slip39
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	for _, v := range testVectors {
+		var shares []Share
+		for _, mnemonic := range v.mnemonics {
+			m, err := ParseMnemonic(mnemonic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := m.Share()
+			if err != nil {
+				t.Fatalf("%q: %v", mnemonic, err)
+			}
+			if got := s.Mnemonic().String(); got != mnemonic {
+				t.Errorf("share encoding mismatch:\ngot:  %s\nwant: %s", got, mnemonic)
+			}
+			shares = append(shares, s)
+		}
+		want, err := hex.DecodeString(v.secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Combine(shares, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("secret mismatch: got %x, want %x", got, want)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	m, err := ParseMnemonic(testVectors[0].mnemonics[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	m[len(m)-1] = (m[len(m)-1] + 1) % Word(len(Wordlist))
+	if _, err := m.Share(); !errors.Is(err, ErrChecksum) {
+		t.Errorf("got error %v, wanted %v", err, ErrChecksum)
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	tests := []struct {
+		secretLen      int
+		groupThreshold int
+		groups         []Group
+		words          int
+	}{
+		{16, 1, []Group{{1, 1}}, 20},
+		{16, 1, []Group{{3, 5}}, 20},
+		{32, 1, []Group{{2, 3}}, 33},
+		{16, 2, []Group{{1, 1}, {2, 3}, {3, 5}}, 20},
+	}
+	for _, test := range tests {
+		secret := make([]byte, test.secretLen)
+		if _, err := rand.Read(secret); err != nil {
+			t.Fatal(err)
+		}
+		const passphrase = "passphrase"
+		groups, err := Split(rand.Reader, secret, passphrase, 0, test.groupThreshold, test.groups)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var shares []Share
+		for gi, g := range groups {
+			if gi >= test.groupThreshold {
+				break
+			}
+			for mi, s := range g {
+				if mi >= test.groups[gi].Threshold {
+					break
+				}
+				m := s.Mnemonic()
+				if len(m) != test.words {
+					t.Errorf("got %d words, want %d", len(m), test.words)
+				}
+				s, err := m.Share()
+				if err != nil {
+					t.Fatal(err)
+				}
+				shares = append(shares, s)
+			}
+		}
+		got, err := Combine(shares, passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("secret mismatch: got %x, want %x", got, secret)
+		}
+		if len(shares) > 1 {
+			if _, err := Combine(shares[1:], passphrase); err == nil {
+				t.Error("combined secret from insufficient shares")
+			}
+		}
+	}
+}
+
+var testVectors = []struct {
+	mnemonics []string
+	secret    string
+}{
+	{
+		mnemonics: []string{
+			"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard",
+		},
+		secret: "bb54aac4b89dc868ba37d9cc21b2cece",
+	},
+	{
+		mnemonics: []string{
+			"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+			"shadow pistol academic acid actress prayer class unknown daughter sweater depict flip twice unkind craft early superior advocate guest smoking",
+		},
+		secret: "b43ceb7e57a0ea8766221624d01b0864",
+	},
+}
@@ -3,6 +3,7 @@ package zbar
 /*
 #cgo CFLAGS: -DENABLE_QRCODE -DNO_STATS -Wno-shift-op-parentheses -Wno-format -Wno-format-security
 
+#include <stdlib.h>
 #include "zbar.h"
 #include "binarize.h"
 */
@@ -11,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"image/draw"
 	"unsafe"
 )
 
@@ -59,3 +61,22 @@ func Scan(img *image.Gray) ([][]byte, error) {
 
 	return results, nil
 }
+
+// ScanImage is like Scan, but accepts any image and copies it to
+// memory allocated outside Go.
+func ScanImage(img image.Image) ([][]byte, error) {
+	sz := img.Bounds().Size()
+	n := sz.X * sz.Y
+	if n == 0 {
+		return nil, nil
+	}
+	pix := C.malloc(C.size_t(n))
+	defer C.free(pix)
+	gray := &image.Gray{
+		Pix:    unsafe.Slice((*byte)(pix), n),
+		Stride: sz.X,
+		Rect:   image.Rectangle{Max: sz},
+	}
+	draw.Draw(gray, gray.Bounds(), img, img.Bounds().Min, draw.Src)
+	return Scan(gray)
+}